@@ -5,39 +5,59 @@ import (
 	"strings"
 )
 
+// defaultCallerMaxDepth is the default number of frames searched for the user's call site.
+const defaultCallerMaxDepth = 32
+
+// pkgPrefix is the function name prefix shared by everything in this package,
+// e.g. "github.com/muhammadluth/goslogx.". It is derived at runtime so that
+// forks and vendored copies are recognized too.
+var pkgPrefix = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name()
+	// Strip ".glob..func1" (or similar) from the end of the package path
+	slash := strings.LastIndexByte(name, '/')
+	return name[:slash+strings.IndexByte(name[slash+1:], '.')+2]
+}()
+
 // detectCallerSkip dynamically detects the correct caller skip level
 // by finding the first caller outside the goslogx package.
 // This allows the logger to correctly report the source location
 // regardless of how many wrapper functions are used.
-func detectCallerSkip() int {
-	const maxDepth = 15 // Maximum call stack depth to search
+//
+// The result is meant for zap.AddCallerSkip on a log call made directly by
+// the function that calls detectCallerSkip: skip 0 is that function itself.
+// Frames are walked with runtime.CallersFrames, which expands inlined calls
+// and counts closures and method-value wrappers the same way zap does.
+// At most maxDepth frames are searched; values less than 1 use the default.
+func detectCallerSkip(maxDepth int) int {
+	return findCallerSkip(maxDepth, isPackageFrame)
+}
 
-	for i := 1; i < maxDepth; i++ {
-		pc, _, _, ok := runtime.Caller(i)
-		if !ok {
-			break
-		}
+// isPackageFrame reports whether fn belongs to the goslogx package.
+func isPackageFrame(fn string) bool {
+	return strings.HasPrefix(fn, pkgPrefix)
+}
 
-		// Get the function name to check the package
-		fn := runtime.FuncForPC(pc)
-		if fn == nil {
-			continue
+// findCallerSkip returns the skip of the first frame, counted from the
+// function that called findCallerSkip's caller, that internal does not match.
+func findCallerSkip(maxDepth int, internal func(fn string) bool) int {
+	if maxDepth < 1 {
+		maxDepth = defaultCallerMaxDepth
+	}
+	pcs := make([]uintptr, maxDepth)
+	// Skip runtime.Callers, findCallerSkip and its caller
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for skip := 0; ; skip++ {
+		frame, more := frames.Next()
+		// Found the first caller outside goslogx package
+		if !internal(frame.Function) {
+			return skip
 		}
-
-		funcName := fn.Name()
-
-		// Skip if this is still within goslogx package
-		// Check for both "goslogx." and "/goslogx." to handle different import paths
-		if strings.Contains(funcName, "github.com/muhammadluth/goslogx.") ||
-			strings.Contains(funcName, "/goslogx.") {
-			continue
+		if !more {
+			break
 		}
-
-		// Found the first caller outside goslogx package
-		// Return i-1 because zap.AddCallerSkip counts from the logger call
-		return i - 1
 	}
-
 	// Fallback to default skip if detection fails
 	return 2
 }
@@ -1,7 +1,13 @@
 package goslogx_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/muhammadluth/goslogx"
@@ -79,3 +85,200 @@ func TestAllLogLevels(t *testing.T) {
 	// Debug - menggunakan caller detection
 	goslogx.Debug("trace-008", "test", goslogx.MESSSAGE_TYPE_EVENT, "debug message", nil)
 }
+
+// callerLine returns the line number of its caller.
+func callerLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func WarnWrapper(traceID string) int {
+	line := callerLine() + 1
+	goslogx.Warning(traceID, "wrapper", "wrapped warning", nil)
+	return line
+}
+
+// TestCallerSource verifies the source reported for each level points at the call site
+func TestCallerSource(t *testing.T) {
+	buf := &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf), goslogx.WithDebug(true), goslogx.WithSource(true))()
+
+	tests := []struct {
+		name string
+		log  func() int // Logs one entry and returns the expected line
+	}{
+		{"Warning", func() int {
+			line := callerLine() + 1
+			goslogx.Warning("trace-101", "test", "warning message", nil)
+			return line
+		}},
+		{"WarningWrapper", func() int {
+			return WarnWrapper("trace-102")
+		}},
+		{"WarningErr", func() int {
+			line := callerLine() + 1
+			goslogx.WarningErr("trace-110", "test", "warning with error", errors.New("recoverable error"))
+			return line
+		}},
+		{"Error", func() int {
+			line := callerLine() + 1
+			goslogx.Error("trace-103", "test", errors.New("error message"))
+			return line
+		}},
+		{"ErrorCtx", func() int {
+			line := callerLine() + 1
+			goslogx.ErrorCtx(context.Background(), "trace-111", "test", errors.New("context error"))
+			return line
+		}},
+		{"Info", func() int {
+			line := callerLine() + 1
+			goslogx.Info("trace-104", "test", goslogx.MESSSAGE_TYPE_EVENT, "info message", nil)
+			return line
+		}},
+		{"Debug", func() int {
+			line := callerLine() + 1
+			goslogx.Debug("trace-105", "test", goslogx.MESSSAGE_TYPE_EVENT, "debug message", nil)
+			return line
+		}},
+		{"AnonymousWrapper", func() int {
+			var line int
+			wrap := func(msg string) {
+				line = callerLine() + 1
+				goslogx.Warning("trace-106", "test", msg, nil)
+			}
+			wrap("closure warning")
+			return line
+		}},
+		{"NestedAnonymousWrapper", func() int {
+			var line int
+			func() {
+				func() {
+					line = callerLine() + 1
+					goslogx.Error("trace-107", "test", errors.New("nested closure error"))
+				}()
+			}()
+			return line
+		}},
+		{"MethodValue", func() int {
+			warn := goslogx.With(map[string]any{"scope": "method-value"}).Warning
+			line := callerLine() + 1
+			warn("trace-108", "test", "method value warning", nil)
+			return line
+		}},
+		{"FunctionValue", func() int {
+			logError := goslogx.Error
+			line := callerLine() + 1
+			logError("trace-109", "test", errors.New("function value error"))
+			return line
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			want := fmt.Sprintf("caller_test.go:%d", tt.log())
+
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("Failed to unmarshal log entry: %v (%s)", err, buf.String())
+			}
+			source, _ := entry["source"].(string)
+			if !strings.HasSuffix(source, want) {
+				t.Errorf("Expected source ending in %s, got %q", want, source)
+			}
+		})
+	}
+}
+
+// TestCallerSourceDefault verifies Info and Debug omit the source unless WithSource is set
+func TestCallerSourceDefault(t *testing.T) {
+	buf := &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf), goslogx.WithDebug(true))()
+
+	goslogx.Info("trace-201", "test", goslogx.MESSSAGE_TYPE_EVENT, "info message", nil)
+	goslogx.Debug("trace-202", "test", goslogx.MESSSAGE_TYPE_EVENT, "debug message", nil)
+	goslogx.Warning("trace-203", "test", "warning message", nil)
+
+	dec := json.NewDecoder(buf)
+	for _, wantSource := range []bool{false, false, true} {
+		var entry map[string]any
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatalf("Failed to decode log entry: %v", err)
+		}
+		if _, ok := entry["source"]; ok != wantSource {
+			t.Errorf("Expected source present=%v for %v entry, got %v", wantSource, entry["level"], entry["source"])
+		}
+	}
+}
+
+// errorWrapper logs through goslogx.Error, one frame above the call site.
+func errorWrapper(traceID string) {
+	goslogx.Error(traceID, "wrapper", errors.New("wrapped error"))
+}
+
+// TestCallerSkip verifies WithCallerSkip reports the same call sites as detection
+func TestCallerSkip(t *testing.T) {
+	tests := []struct {
+		name string
+		skip int
+		log  func() int // Logs one entry and returns the expected line
+	}{
+		{"LoggerMethod", 1, func() int {
+			logger := goslogx.With(map[string]any{"scope": "method"})
+			line := callerLine() + 1
+			logger.Error("trace-301", "test", errors.New("method error"))
+			return line
+		}},
+		{"PackageFunction", 2, func() int {
+			line := callerLine() + 1
+			goslogx.Warning("trace-302", "test", "function warning", nil)
+			return line
+		}},
+		{"LoggerCtxMethod", 2, func() int {
+			logger := goslogx.With(map[string]any{"scope": "ctx-method"})
+			line := callerLine() + 1
+			logger.ErrorCtx(context.Background(), "trace-303", "test", errors.New("ctx method error"))
+			return line
+		}},
+		{"PackageCtxFunction", 3, func() int {
+			line := callerLine() + 1
+			goslogx.InfoCtx(context.Background(), "trace-304", "test", goslogx.MESSAGE_TYPE_EVENT, "ctx info", nil)
+			return line
+		}},
+	}
+	for _, mode := range []string{"Detect", "Fixed"} {
+		for _, tt := range tests {
+			t.Run(mode+"/"+tt.name, func(t *testing.T) {
+				buf := &bytes.Buffer{}
+				opts := []goslogx.Option{goslogx.WithOutput(buf), goslogx.WithSource(true)}
+				if mode == "Fixed" {
+					opts = append(opts, goslogx.WithCallerSkip(tt.skip))
+				}
+				defer goslogx.ReplaceGlobal(opts...)()
+
+				want := fmt.Sprintf("caller_test.go:%d", tt.log())
+				var entry map[string]any
+				if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+					t.Fatalf("Failed to unmarshal log entry: %v (%s)", err, buf.String())
+				}
+				if source, _ := entry["source"].(string); !strings.HasSuffix(source, want) {
+					t.Errorf("Expected source ending in %s, got %q", want, source)
+				}
+			})
+		}
+	}
+
+	t.Run("FixedSkipsOwnWrapper", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf), goslogx.WithCallerSkip(3))()
+
+		line := callerLine() + 1
+		errorWrapper("trace-305")
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to unmarshal log entry: %v (%s)", err, buf.String())
+		}
+		if want := fmt.Sprintf("caller_test.go:%d", line); !strings.HasSuffix(entry["source"].(string), want) {
+			t.Errorf("Expected source ending in %s, got %v", want, entry["source"])
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package goslogx
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// Supported values for Config.Encoding.
+const (
+	// EncodingJSON emits one JSON object per line (default).
+	EncodingJSON = "json"
+	// EncodingConsole emits colorized, human-readable key=value lines for local development.
+	EncodingConsole = "console"
+	// EncodingLogfmt emits one logfmt line per entry, key=value pairs with
+	// nested data flattened into dot-separated keys.
+	EncodingLogfmt = "logfmt"
+)
+
+// ANSI color codes used for level names in console output.
+const (
+	colorReset   = "\x1b[0m"
+	colorRed     = "\x1b[31m"
+	colorYellow  = "\x1b[33m"
+	colorBlue    = "\x1b[34m"
+	colorMagenta = "\x1b[35m"
+)
+
+// consolePool reuses output buffers for console entries.
+var consolePool = buffer.NewPool()
+
+// consoleEncoder renders entries as "time LEVEL message key=value ..." lines.
+// Field encoding is delegated to an embedded JSON encoder that only emits
+// context fields; the resulting object is then rewritten as key=value pairs
+// in insertion order. Because fields go through the same zap.Field builders,
+// masking applies exactly as it does for JSON output.
+type consoleEncoder struct {
+	zapcore.Encoder        // JSON encoder that accumulates context fields
+	timeKey         string // Empty to omit the timestamp
+	stackKey        string // Key whose value is compacted as a stack trace
+	multilineStack  bool   // Write the stack trace on its own lines after the entry
+	color           bool   // Colorize level names
+}
+
+// newConsoleEncoder creates a consoleEncoder from the logger's encoder config.
+// With multilineStack, stack traces are written below the entry line
+// instead of being compacted into a key=value pair.
+func newConsoleEncoder(cfg zapcore.EncoderConfig, multilineStack bool) *consoleEncoder {
+	timeKey := cfg.TimeKey
+	// The embedded JSON encoder only emits fields, caller, and stack trace
+	cfg.TimeKey = ""
+	cfg.LevelKey = ""
+	cfg.MessageKey = ""
+	cfg.NameKey = ""
+	cfg.LineEnding = ""
+	return &consoleEncoder{
+		Encoder:        zapcore.NewJSONEncoder(cfg),
+		timeKey:        timeKey,
+		stackKey:       cfg.StacktraceKey,
+		multilineStack: multilineStack,
+		color:          true,
+	}
+}
+
+// Clone implements zapcore.Encoder.
+func (e *consoleEncoder) Clone() zapcore.Encoder {
+	return &consoleEncoder{
+		Encoder:        e.Encoder.Clone(),
+		timeKey:        e.timeKey,
+		stackKey:       e.stackKey,
+		multilineStack: e.multilineStack,
+		color:          e.color,
+	}
+}
+
+// EncodeEntry implements zapcore.Encoder.
+func (e *consoleEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	inner, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer inner.Free()
+
+	line := consolePool.Get()
+	if e.timeKey != "" {
+		line.AppendString(ent.Time.Format(time.RFC3339))
+		line.AppendByte('\t')
+	}
+	e.appendLevel(line, ent.Level)
+	line.AppendByte('\t')
+	line.AppendString(ent.Message)
+	stack, err := appendKeyValues(line, inner.Bytes(), e.stackKey, e.multilineStack)
+	if err != nil {
+		line.Free()
+		return nil, err
+	}
+	if stack != "" {
+		line.AppendByte('\n')
+		line.AppendString(stack)
+	}
+	line.AppendByte('\n')
+	return line, nil
+}
+
+// appendLevel writes the capitalized level name, colorized when enabled.
+func (e *consoleEncoder) appendLevel(line *buffer.Buffer, lvl zapcore.Level) {
+	name := lvl.CapitalString()
+	if !e.color {
+		line.AppendString(name)
+		return
+	}
+	color := colorRed
+	switch lvl {
+	case zapcore.DebugLevel:
+		color = colorMagenta
+	case zapcore.InfoLevel:
+		color = colorBlue
+	case zapcore.WarnLevel:
+		color = colorYellow
+	}
+	line.AppendString(color)
+	line.AppendString(name)
+	line.AppendString(colorReset)
+}
+
+// appendKeyValues rewrites a flat JSON object as space-separated key=value pairs,
+// preserving key order. Nested objects and arrays are kept as compact JSON.
+// The stackKey value is compacted with formatStackTraceBytes, or, when
+// multilineStack is set, left out and returned so the caller can write it as-is.
+func appendKeyValues(line *buffer.Buffer, obj []byte, stackKey string, multilineStack bool) (stack string, err error) {
+	dec := json.NewDecoder(bytes.NewReader(obj))
+	dec.UseNumber()
+	// Opening brace
+	if _, err := dec.Token(); err != nil {
+		return "", err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		key := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return "", err
+		}
+		if key == stackKey && multilineStack && json.Unmarshal(raw, &stack) == nil {
+			continue
+		}
+		line.AppendByte(' ')
+		line.AppendString(key)
+		line.AppendByte('=')
+		appendConsoleValue(line, raw, key == stackKey)
+	}
+	return stack, nil
+}
+
+// appendConsoleValue writes a single JSON value in console form.
+// Strings are unquoted unless they contain whitespace, quotes, or '=',
+// and every other value is written as raw JSON.
+func appendConsoleValue(line *buffer.Buffer, raw json.RawMessage, isStack bool) {
+	if len(raw) == 0 || raw[0] != '"' {
+		line.Write(raw)
+		return
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		line.Write(raw)
+		return
+	}
+	if isStack {
+		var buf bytes.Buffer
+		formatStackTraceBytes(&buf, s)
+		s = buf.String()
+	}
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		quoted, _ := json.Marshal(s)
+		line.Write(quoted)
+		return
+	}
+	line.AppendString(s)
+}
@@ -0,0 +1,79 @@
+package goslogx
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConsoleEncoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := setupLog(WithOutput(buf), WithServiceName("console-service"), WithConsoleEncoder(true))
+
+	t.Run("Info", func(t *testing.T) {
+		buf.Reset()
+		logger.Info("trace-123", "user-module", MESSSAGE_TYPE_EVENT, "user created", nil)
+		out := buf.String()
+		for _, want := range []string{"INFO", "user created", "module=user-module", "trace_id=trace-123", "msg_type=EVENT"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("Expected console output to contain %q, got %s", want, out)
+			}
+		}
+		if strings.HasPrefix(strings.TrimSpace(out), "{") {
+			t.Errorf("Expected plaintext output, got JSON: %s", out)
+		}
+	})
+
+	t.Run("Masking", func(t *testing.T) {
+		buf.Reset()
+		type Login struct {
+			Username string `json:"username" log:"masked:partial"`
+			Password string `json:"password" log:"masked:full"`
+		}
+		logger.Info("trace-123", "auth", MESSSAGE_TYPE_IN, "login", Login{Username: "johndoe123", Password: "supersecret"})
+		out := buf.String()
+		if strings.Contains(out, "supersecret") || strings.Contains(out, "johndoe123") {
+			t.Errorf("Sensitive data leaked in console output: %s", out)
+		}
+		if !strings.Contains(out, `"password":"****"`) {
+			t.Errorf("Expected masked password in console output, got %s", out)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		buf.Reset()
+		logger.Error("trace-123", "db", errors.New("connection refused"))
+		out := buf.String()
+		if !strings.Contains(out, "ERROR") || !strings.Contains(out, `error="connection refused"`) {
+			t.Errorf("Expected level and quoted error in console output, got %s", out)
+		}
+	})
+}
+
+func TestAppendConsoleValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		isStack  bool
+		expected string
+	}{
+		{"PlainString", `"value"`, false, "value"},
+		{"StringWithSpace", `"two words"`, false, `"two words"`},
+		{"EmptyString", `""`, false, `""`},
+		{"Number", `42`, false, "42"},
+		{"Object", `{"a":1}`, false, `{"a":1}`},
+		{"StackTrace", `"main.main\n\tmain.go:1"`, true, `"[main.main | main.go:1]"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := consolePool.Get()
+			defer line.Free()
+			appendConsoleValue(line, []byte(tt.raw), tt.isStack)
+			if line.String() != tt.expected {
+				t.Errorf("appendConsoleValue(%s) = %s, want %s", tt.raw, line.String(), tt.expected)
+			}
+		})
+	}
+}
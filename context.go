@@ -0,0 +1,153 @@
+package goslogx
+
+import (
+	"context"
+	"maps"
+
+	"go.uber.org/zap"
+)
+
+// TraceExtractor returns the trace and span IDs of the trace active in ctx,
+// hex-encoded, and whether one is active. See WithTraceExtractor.
+type TraceExtractor func(ctx context.Context) (traceID string, spanID string, ok bool)
+
+// contextFieldsKey is the context key for fields added with ContextWithFields.
+type contextFieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx carrying fields, such as a user or
+// tenant ID, that the Ctx logging functions and the slog handler add to every
+// entry logged with it, masked like fields bound with With. Fields already in
+// ctx are kept unless fields replaces them.
+//
+// Example:
+//
+//	func middleware(next http.Handler) http.Handler {
+//	    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	        ctx := goslogx.ContextWithFields(r.Context(), map[string]any{
+//	            "user_id": userID(r),
+//	            "tenant":  tenant(r),
+//	        })
+//	        next.ServeHTTP(w, r.WithContext(ctx))
+//	    })
+//	}
+//
+//	// Later, in any function handed the request context
+//	goslogx.InfoCtx(ctx, traceID, "orders", goslogx.MESSAGE_TYPE_EVENT, "order placed", nil)
+//	// {"msg":"order placed","tenant":"acme","user_id":"u-42",...}
+func ContextWithFields(ctx context.Context, fields map[string]any) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	prev := contextFields(ctx)
+	merged := make(map[string]any, len(prev)+len(fields))
+	maps.Copy(merged, prev)
+	maps.Copy(merged, fields)
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+// contextFields returns the fields added to ctx with ContextWithFields.
+// The returned map must not be modified.
+func contextFields(ctx context.Context) map[string]any {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(contextFieldsKey{}).(map[string]any)
+	return fields
+}
+
+// fromContext returns the logger and trace ID to use for a log call made with
+// ctx, or with the configured BaseContext if ctx is nil. When the configured
+// TraceExtractor finds an active trace, its trace ID replaces traceID and its
+// span ID is bound to the returned logger, and fields added with
+// ContextWithFields are bound to it too.
+func (l *Logger) fromContext(ctx context.Context, traceID string) (*Logger, string) {
+	if ctx == nil {
+		ctx = l.config.BaseContext
+	}
+	if ctx == nil {
+		return l, traceID
+	}
+	if l.config.BaseContext != nil {
+		// The method logging the entry must not apply BaseContext over ctx
+		child := *l
+		child.inContext = true
+		l = &child
+	}
+	l = l.With(contextFields(ctx))
+	if l.config.TraceExtractor == nil {
+		return l, traceID
+	}
+	spanTraceID, spanID, ok := l.config.TraceExtractor(ctx)
+	if !ok {
+		return l, traceID
+	}
+	child := *l
+	child.logger = l.logger.With(zap.String(l.config.FieldKeys.SpanID, spanID))
+	return &child, spanTraceID
+}
+
+// FatalCtx is Fatal with the trace correlation found in ctx, see WithTraceExtractor.
+func (l *Logger) FatalCtx(ctx context.Context, traceID string, module string, err error) {
+	l, traceID = l.fromContext(ctx, traceID)
+	l.Fatal(traceID, module, err)
+}
+
+// FatalCtx is Fatal on the global logger with the trace correlation found in ctx.
+func FatalCtx(ctx context.Context, traceID string, module string, err error) {
+	globalLog.Load().FatalCtx(ctx, traceID, module, err)
+}
+
+// ErrorCtx is Error with the trace correlation found in ctx, see WithTraceExtractor.
+func (l *Logger) ErrorCtx(ctx context.Context, traceID string, module string, err error, extra ...Field) {
+	l, traceID = l.fromContext(ctx, traceID)
+	l.Error(traceID, module, err, extra...)
+}
+
+// ErrorCtx is Error on the global logger with the trace correlation found in ctx.
+func ErrorCtx(ctx context.Context, traceID string, module string, err error, extra ...Field) {
+	globalLog.Load().ErrorCtx(ctx, traceID, module, err, extra...)
+}
+
+// WarningCtx is Warning with the trace correlation found in ctx, see WithTraceExtractor.
+func (l *Logger) WarningCtx(ctx context.Context, traceID string, module string, msg string, data any) {
+	l, traceID = l.fromContext(ctx, traceID)
+	l.Warning(traceID, module, msg, data)
+}
+
+// WarningCtx is Warning on the global logger with the trace correlation found in ctx.
+func WarningCtx(ctx context.Context, traceID string, module string, msg string, data any) {
+	globalLog.Load().WarningCtx(ctx, traceID, module, msg, data)
+}
+
+// WarningErrCtx is WarningErr with the trace correlation found in ctx, see WithTraceExtractor.
+func (l *Logger) WarningErrCtx(ctx context.Context, traceID string, module string, msg string, err error) {
+	l, traceID = l.fromContext(ctx, traceID)
+	l.WarningErr(traceID, module, msg, err)
+}
+
+// WarningErrCtx is WarningErr on the global logger with the trace correlation found in ctx.
+func WarningErrCtx(ctx context.Context, traceID string, module string, msg string, err error) {
+	globalLog.Load().WarningErrCtx(ctx, traceID, module, msg, err)
+}
+
+// InfoCtx is Info with the trace correlation found in ctx, see WithTraceExtractor.
+func (l *Logger) InfoCtx(ctx context.Context, traceID string, module string, msgType MsgType, msg string, data any) {
+	l, traceID = l.fromContext(ctx, traceID)
+	l.Info(traceID, module, msgType, msg, data)
+}
+
+// InfoCtx is Info on the global logger with the trace correlation found in ctx.
+func InfoCtx(ctx context.Context, traceID string, module string, msgType MsgType, msg string, data any) {
+	globalLog.Load().InfoCtx(ctx, traceID, module, msgType, msg, data)
+}
+
+// DebugCtx is Debug with the trace correlation found in ctx, see WithTraceExtractor.
+func (l *Logger) DebugCtx(ctx context.Context, traceID string, module string, msgType MsgType, msg string, data any) {
+	l, traceID = l.fromContext(ctx, traceID)
+	l.Debug(traceID, module, msgType, msg, data)
+}
+
+// DebugCtx is Debug on the global logger with the trace correlation found in ctx.
+func DebugCtx(ctx context.Context, traceID string, module string, msgType MsgType, msg string, data any) {
+	globalLog.Load().DebugCtx(ctx, traceID, module, msgType, msg, data)
+}
@@ -0,0 +1,192 @@
+package goslogx_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/muhammadluth/goslogx"
+)
+
+type spanKey struct{}
+
+// testSpan is a minimal stand-in for a tracing library's span context
+type testSpan struct {
+	traceID, spanID string
+}
+
+func extractTestSpan(ctx context.Context) (string, string, bool) {
+	span, ok := ctx.Value(spanKey{}).(testSpan)
+	return span.traceID, span.spanID, ok
+}
+
+// TestContextTrace verifies the Ctx functions take the trace ID from the active span
+func TestContextTrace(t *testing.T) {
+	buf := &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf), goslogx.WithDebug(true), goslogx.WithTraceExtractor(extractTestSpan))()
+
+	span := testSpan{traceID: "4bf92f3577b34da6a3ce929d0e0e4736", spanID: "00f067aa0ba902b7"}
+	spanCtx := context.WithValue(context.Background(), spanKey{}, span)
+
+	tests := []struct {
+		name string
+		log  func(ctx context.Context)
+	}{
+		{"Error", func(ctx context.Context) { goslogx.ErrorCtx(ctx, "manual-trace", "test", errors.New("boom")) }},
+		{"Warning", func(ctx context.Context) { goslogx.WarningCtx(ctx, "manual-trace", "test", "careful", nil) }},
+		{"WarningErr", func(ctx context.Context) {
+			goslogx.WarningErrCtx(ctx, "manual-trace", "test", "retrying", errors.New("boom"))
+		}},
+		{"Info", func(ctx context.Context) {
+			goslogx.InfoCtx(ctx, "manual-trace", "test", goslogx.MESSSAGE_TYPE_EVENT, "hello", nil)
+		}},
+		{"Debug", func(ctx context.Context) {
+			goslogx.DebugCtx(ctx, "manual-trace", "test", goslogx.MESSSAGE_TYPE_EVENT, "hello", nil)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.log(spanCtx)
+			entry := decode(t, buf)
+			if entry["trace_id"] != span.traceID || entry["span_id"] != span.spanID {
+				t.Errorf("Expected span trace_id/span_id, got %v/%v", entry["trace_id"], entry["span_id"])
+			}
+
+			buf.Reset()
+			tt.log(context.Background())
+			entry = decode(t, buf)
+			if entry["trace_id"] != "manual-trace" {
+				t.Errorf("Expected manual trace_id without a span, got %v", entry["trace_id"])
+			}
+			if _, ok := entry["span_id"]; ok {
+				t.Errorf("Expected no span_id without a span, got %v", entry["span_id"])
+			}
+		})
+	}
+
+	t.Run("Slog", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		logger := slog.New(goslogx.NewSlogHandler(goslogx.WithOutput(out), goslogx.WithTraceExtractor(extractTestSpan)))
+		logger.InfoContext(spanCtx, "hello")
+		entry := decode(t, out)
+		if entry["trace_id"] != span.traceID || entry["span_id"] != span.spanID {
+			t.Errorf("Expected span trace_id/span_id, got %v/%v", entry["trace_id"], entry["span_id"])
+		}
+	})
+}
+
+// decode unmarshals the single log entry written to buf.
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to unmarshal log entry: %v (%s)", err, buf.String())
+	}
+	return entry
+}
+
+// TestContextWithFields verifies fields stored in a context appear on later Ctx log calls
+func TestContextWithFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf), goslogx.WithTraceExtractor(extractTestSpan))()
+
+	ctx := goslogx.ContextWithFields(context.Background(), map[string]any{"user_id": "u-42", "tenant": "acme"})
+	ctx = goslogx.ContextWithFields(ctx, map[string]any{"tenant": "globex", "customer": map[string]any{"email": "jane.doe@example.com"}})
+	handle := func(ctx context.Context) {
+		goslogx.InfoCtx(ctx, "trace-1", "orders", goslogx.MESSAGE_TYPE_EVENT, "order placed", nil)
+	}
+	handle(ctx)
+
+	entry := decode(t, buf)
+	if entry["user_id"] != "u-42" || entry["tenant"] != "globex" {
+		t.Errorf("Expected context fields with later values winning, got %v", entry)
+	}
+	if customer, _ := entry["customer"].(map[string]any); customer["email"] != "ja****om" {
+		t.Errorf("Expected context fields masked, got %v", entry["customer"])
+	}
+
+	buf.Reset()
+	span := testSpan{traceID: "4bf92f3577b34da6a3ce929d0e0e4736", spanID: "00f067aa0ba902b7"}
+	goslogx.ErrorCtx(context.WithValue(ctx, spanKey{}, span), "trace-2", "orders", errors.New("boom"))
+	entry = decode(t, buf)
+	if entry["user_id"] != "u-42" || entry["span_id"] != span.spanID {
+		t.Errorf("Expected context fields alongside the span, got %v", entry)
+	}
+
+	buf.Reset()
+	handle(context.Background())
+	if entry := decode(t, buf); entry["user_id"] != nil {
+		t.Errorf("Expected no context fields without them, got %v", entry["user_id"])
+	}
+
+	t.Run("Slog", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		slog.New(goslogx.NewSlogHandler(goslogx.WithOutput(out))).InfoContext(ctx, "hello")
+		if entry := decode(t, out); entry["user_id"] != "u-42" {
+			t.Errorf("Expected context fields on slog entries, got %v", entry)
+		}
+	})
+}
+
+// TestBaseContext verifies the non-ctx functions log with the base context
+func TestBaseContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	span := testSpan{traceID: "4bf92f3577b34da6a3ce929d0e0e4736", spanID: "00f067aa0ba902b7"}
+	base := goslogx.ContextWithFields(context.WithValue(context.Background(), spanKey{}, span), map[string]any{"region": "ap-southeast-1"})
+
+	var seen []context.Context
+	extract := func(ctx context.Context) (string, string, bool) {
+		seen = append(seen, ctx)
+		return extractTestSpan(ctx)
+	}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf), goslogx.WithDebug(true),
+		goslogx.WithTraceExtractor(extract), goslogx.WithBaseContext(base))()
+
+	tests := []struct {
+		name string
+		log  func()
+	}{
+		{"Error", func() { goslogx.Error("manual-trace", "test", errors.New("boom")) }},
+		{"Warning", func() { goslogx.Warning("manual-trace", "test", "careful", nil) }},
+		{"WarningErr", func() { goslogx.WarningErr("manual-trace", "test", "retrying", errors.New("boom")) }},
+		{"Info", func() { goslogx.Info("manual-trace", "test", goslogx.MESSAGE_TYPE_EVENT, "hello", nil) }},
+		{"Debug", func() { goslogx.Debug("manual-trace", "test", goslogx.MESSAGE_TYPE_EVENT, "hello", nil) }},
+		{"NilCtx", func() {
+			var ctx context.Context // A nil context falls back to the base context
+			goslogx.InfoCtx(ctx, "manual-trace", "test", goslogx.MESSAGE_TYPE_EVENT, "hello", nil)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			seen = nil
+			tt.log()
+			if len(seen) != 1 || seen[0] != base {
+				t.Fatalf("Expected the extractor to be called once with the base context, got %d calls", len(seen))
+			}
+			entry := decode(t, buf)
+			if entry["trace_id"] != span.traceID || entry["span_id"] != span.spanID || entry["region"] != "ap-southeast-1" {
+				t.Errorf("Expected the base context's span and fields, got %v", entry)
+			}
+		})
+	}
+
+	t.Run("CtxOverrides", func(t *testing.T) {
+		buf.Reset()
+		seen = nil
+		other := testSpan{traceID: "0af7651916cd43dd8448eb211c80319c", spanID: "b7ad6b7169203331"}
+		goslogx.InfoCtx(context.WithValue(context.Background(), spanKey{}, other), "manual-trace", "test",
+			goslogx.MESSAGE_TYPE_EVENT, "hello", nil)
+		if len(seen) != 1 {
+			t.Fatalf("Expected the extractor to be called once, got %d calls", len(seen))
+		}
+		entry := decode(t, buf)
+		if entry["trace_id"] != other.traceID || entry["span_id"] != other.spanID || entry["region"] != nil {
+			t.Errorf("Expected the given context to replace the base context, got %v", entry)
+		}
+	})
+}
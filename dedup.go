@@ -0,0 +1,156 @@
+package goslogx
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// dedupCore wraps a zapcore.Core to coalesce entries that repeat the same
+// error field value within a window, see WithErrorDedup.
+// Cores derived with With share the state of the core they came from.
+type dedupCore struct {
+	zapcore.Core
+	state *dedupState
+}
+
+// dedupState tracks the last written error entry and how many repeats of it
+// were suppressed since.
+type dedupState struct {
+	mu          sync.Mutex
+	window      time.Duration
+	clock       zapcore.Clock // Timestamps summary entries
+	errorKey    string
+	repeatedKey string
+
+	last   string       // error value of the last written entry
+	start  time.Time    // time of the last written entry
+	core   zapcore.Core // core that wrote the last entry; nil when none is pending
+	ent    zapcore.Entry
+	fields []zapcore.Field
+	count  int         // repeats of last suppressed since start
+	timer  *time.Timer // flushes the summary when the window elapses
+	gen    uint64      // invalidates timers of windows already flushed
+}
+
+// newDedupCore wraps core so that repeated error values are coalesced.
+func newDedupCore(core zapcore.Core, window time.Duration, keys *FieldKeys, clock zapcore.Clock) zapcore.Core {
+	return &dedupCore{
+		Core: core,
+		state: &dedupState{
+			window:      window,
+			clock:       clock,
+			errorKey:    keys.Error,
+			repeatedKey: keys.Repeated,
+		},
+	}
+}
+
+// With implements zapcore.Core.
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{Core: c.Core.With(fields), state: c.state}
+}
+
+// Check implements zapcore.Core.
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core. Entries without an error field, and fatal
+// or panic entries, are always written.
+func (c *dedupCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level > zapcore.ErrorLevel {
+		writeChecked(c.Core, ent, fields)
+		return nil
+	}
+	msg, ok := c.state.errorValue(fields)
+	if !ok {
+		writeChecked(c.Core, ent, fields)
+		return nil
+	}
+
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.core != nil && msg == s.last && ent.Time.Sub(s.start) < s.window {
+		s.count++
+		if s.timer == nil {
+			gen := s.gen
+			s.timer = time.AfterFunc(s.window-ent.Time.Sub(s.start), func() { s.expire(gen) })
+		}
+		return nil
+	}
+
+	s.flushLocked()
+	s.last, s.start, s.core, s.ent = msg, ent.Time, c.Core, ent
+	// Fields are copied: the caller reuses its slice once Write returns
+	s.fields = append(s.fields[:0], fields...)
+	writeChecked(c.Core, ent, fields)
+	return nil
+}
+
+// Sync implements zapcore.Core, writing the summary of pending repeats first.
+func (c *dedupCore) Sync() error {
+	c.state.mu.Lock()
+	c.state.flushLocked()
+	c.state.mu.Unlock()
+	return c.Core.Sync()
+}
+
+// errorValue returns the value of the error field, if fields have one.
+func (s *dedupState) errorValue(fields []zapcore.Field) (string, bool) {
+	for i := range fields {
+		if fields[i].Key == s.errorKey && fields[i].Type == zapcore.StringType {
+			return fields[i].String, true
+		}
+	}
+	return "", false
+}
+
+// expire flushes the summary of the window gen when it elapses.
+func (s *dedupState) expire(gen uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.gen == gen {
+		s.flushLocked()
+	}
+}
+
+// flushLocked writes the last entry again with a repeated count if repeats
+// were suppressed, and closes the current window. s.mu must be held.
+func (s *dedupState) flushLocked() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.gen++
+	core, count := s.core, s.count
+	s.core, s.count = nil, 0
+	if core == nil || count == 0 {
+		return
+	}
+	ent := s.ent
+	ent.Time = s.clock.Now()
+	writeChecked(core, ent, append(s.fields, zap.Int(s.repeatedKey, count)))
+}
+
+// writeChecked writes ent to the cores of core that accept it, for wrappers
+// that decide in Write whether and what to write. core.Write would not do:
+// with WithErrorOutput, core is a tee whose Write goes to every output
+// regardless of level. Write errors are reported on stderr, as zap does.
+func writeChecked(core zapcore.Core, ent zapcore.Entry, fields []zapcore.Field) {
+	if ce := core.Check(ent, nil); ce != nil {
+		ce.ErrorOutput = stderr
+		ce.Write(fields...)
+	}
+}
+
+// stderr receives the errors of entries written by writeChecked.
+var stderr = zapcore.Lock(os.Stderr)
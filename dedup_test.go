@@ -0,0 +1,104 @@
+package goslogx
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestErrorDedup(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := setupLog(WithOutput(buf), WithErrorDedup(time.Minute))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Error("trace-1", "db", errors.New("connection refused"))
+		}()
+	}
+	wg.Wait()
+	if lines := strings.Count(buf.String(), "\n"); lines != 1 {
+		t.Fatalf("Expected repeats to be held back, got %d lines", lines)
+	}
+
+	_ = logger.Sync()
+	entries := decodeEntries(t, buf.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("Expected first entry plus summary, got %d entries", len(entries))
+	}
+	if _, ok := entries[0][KeyRepeated]; ok {
+		t.Errorf("Expected no repeated count on the first entry, got %v", entries[0][KeyRepeated])
+	}
+	summary := entries[1]
+	if summary[KeyRepeated] != float64(49) || summary[KeyError] != "connection refused" {
+		t.Errorf("Expected summary with repeated=49, got %v", summary)
+	}
+
+	t.Run("MessageChange", func(t *testing.T) {
+		buf.Reset()
+		for i := 0; i < 3; i++ {
+			logger.Error("trace-2", "db", errors.New("timeout"))
+		}
+		logger.Error("trace-3", "db", errors.New("connection reset"))
+
+		entries := decodeEntries(t, buf.Bytes())
+		if len(entries) != 3 {
+			t.Fatalf("Expected timeout, its summary and the new error, got %d entries", len(entries))
+		}
+		if entries[1][KeyError] != "timeout" || entries[1][KeyRepeated] != float64(2) {
+			t.Errorf("Expected timeout summary with repeated=2, got %v", entries[1])
+		}
+		if entries[2][KeyError] != "connection reset" {
+			t.Errorf("Expected new error to be written, got %v", entries[2])
+		}
+	})
+
+	t.Run("WindowElapsed", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf), WithErrorDedup(time.Nanosecond))
+		logger.Error("trace-4", "db", errors.New("timeout"))
+		time.Sleep(time.Millisecond)
+		logger.Error("trace-5", "db", errors.New("timeout"))
+		if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+			t.Errorf("Expected a repeat after the window to be written, got %d lines", lines)
+		}
+	})
+
+	t.Run("OtherEntries", func(t *testing.T) {
+		buf.Reset()
+		for i := 0; i < 3; i++ {
+			logger.Info("trace-6", "db", MESSSAGE_TYPE_EVENT, "polling", nil)
+		}
+		if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+			t.Errorf("Expected entries without an error to pass through, got %d lines", lines)
+		}
+	})
+}
+
+func TestErrorDedupErrorOutput(t *testing.T) {
+	// Entries must still be routed to the output matching their level
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	logger := setupLog(WithOutput(out), WithErrorOutput(errOut), WithErrorDedup(time.Minute))
+
+	logger.Info("trace-1", "db", MESSSAGE_TYPE_EVENT, "polling", nil)
+	for i := 0; i < 3; i++ {
+		logger.Error("trace-2", "db", errors.New("timeout"))
+	}
+	_ = logger.Sync()
+
+	if entries := decodeEntries(t, out.Bytes()); len(entries) != 1 || entries[0][KeyMessage] != "polling" {
+		t.Errorf("Expected only the info entry in the output, got %s", out.String())
+	}
+	entries := decodeEntries(t, errOut.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("Expected the error and its summary in the error output, got %s", errOut.String())
+	}
+	if entries[1][KeyError] != "timeout" || entries[1][KeyRepeated] != float64(2) {
+		t.Errorf("Expected summary with repeated=2, got %v", entries[1])
+	}
+}
@@ -1,8 +1,21 @@
 package goslogx
 
+import (
+	"maps"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
 // HTTPData captures context for HTTP interactions.
 // It provides a structured schema for logging request/response and client metadata.
-// Sensitive fields in Body (JSON) and Headers are automatically masked.
+// Set Duration and DurationMs with FormatDuration and DurationMillis; the
+// numeric duration_ms is what dashboards should aggregate.
+// Sensitive fields in Body (JSON) and Headers are automatically masked, so pass
+// the raw request values; running them through MaskingLogJSONBytes or
+// MaskingLogHttpHeaders first only repeats the work. Headers listed with
+// WithRedactHeaders are dropped, and ClientIP is anonymized when
+// WithAnonymizeIP is set.
 //
 // Example:
 //
@@ -10,21 +23,47 @@ package goslogx
 //		Method:     "POST",
 //		URL:        "https://example.com/api/v1/users",
 //		StatusCode: 201,
+//		Duration:   goslogx.FormatDuration(elapsed),
+//		DurationMs: goslogx.DurationMillis(elapsed),
 //		ClientIP:   "192.168.1.1",
 //	}
-//	goslogx.Info("trace-001", "http", goslogx.MESSSAGE_TYPE_REQUEST, "request completed", data)
+//	goslogx.Info("trace-001", "http", goslogx.MESSAGE_TYPE_REQUEST, "request completed", data)
 type HTTPData struct {
 	Method     string              `json:"method,omitempty"`
 	URL        string              `json:"url,omitempty"`
 	StatusCode int                 `json:"status_code,omitempty"`
-	Headers    map[string][]string `json:"headers,omitempty"`
+	Headers    map[string][]string `json:"headers,omitempty" log:"masked:headers"`
 	Body       any                 `json:"body,omitempty"`
-	Duration   string              `json:"duration,omitempty"`
-	ClientIP   string              `json:"client_ip,omitempty"`
+	Duration   string              `json:"duration,omitempty"`    // Human-readable, e.g. "125.3ms"
+	DurationMs float64             `json:"duration_ms,omitempty"` // Numeric, for aggregation
+	ClientIP   string              `json:"client_ip,omitempty" log:"masked:ip"`
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler, masking d with the
+// global logger's configuration, so it can also be logged with zap.Object.
+// Logged as data by a Logger, d is masked with that logger's configuration.
+func (d HTTPData) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return d.marshalMasked(enc, maskedObject{cfg: globalMaskingConfig()})
+}
+
+func (d HTTPData) marshalMasked(enc zapcore.ObjectEncoder, m maskedObject) error {
+	m.addString(enc, "method", d.Method)
+	m.addString(enc, "url", d.URL)
+	m.addInt(enc, "status_code", d.StatusCode)
+	addDTOMap(m, enc, "headers", d.Headers, true)
+	m.addAny(enc, "body", d.Body)
+	m.addString(enc, "duration", d.Duration)
+	m.addFloat(enc, "duration_ms", d.DurationMs)
+	m.addIP(enc, "client_ip", d.ClientIP)
+	return nil
 }
 
 // DBData captures context for database or cache operations.
 // It tracks the driver, operation, and execution duration.
+// Args that look like emails, card numbers, or phone numbers are masked,
+// NamedArgs, as bound with sqlx or gorm, are masked by parameter name, and
+// string literals in Statement are scrubbed when WithSQLLiteralMasking is set.
+// WithSQLNormalization adds a statement_normalized field with literals replaced by ?.
 //
 // Example:
 //
@@ -34,39 +73,135 @@ type HTTPData struct {
 //		Database:   "postgres",
 //		Table:      "users",
 //		Statement:  "SELECT * FROM users WHERE id = $1",
-//		Duration:   "45ms",
+//		Args:       []any{42},
+//		Duration:   goslogx.FormatDuration(elapsed),
+//		DurationMs: goslogx.DurationMillis(elapsed),
 //	}
-//	goslogx.Info("trace-001", "database", goslogx.MESSSAGE_TYPE_IN, "query executed", data)
+//	goslogx.Info("trace-001", "database", goslogx.MESSAGE_TYPE_IN, "query executed", data)
 type DBData struct {
-	Driver    string `json:"driver,omitempty"`
-	Operation string `json:"operation,omitempty"`
-	Database  string `json:"database,omitempty"`
-	Table     string `json:"table,omitempty"`
-	Statement string `json:"statement,omitempty"`
-	Duration  string `json:"duration,omitempty"`
-	Payload   any    `json:"payload,omitempty"`
+	Driver     string         `json:"driver,omitempty"`
+	Operation  string         `json:"operation,omitempty"`
+	Database   string         `json:"database,omitempty"`
+	Table      string         `json:"table,omitempty"`
+	Statement  string         `json:"statement,omitempty" log:"masked:sql"`
+	Args       []any          `json:"args,omitempty" log:"masked:scan"` // Bind parameters, masked by value
+	NamedArgs  map[string]any `json:"named_args,omitempty"`             // Named bind parameters, masked by name
+	Duration   string         `json:"duration,omitempty"`               // Human-readable, e.g. "45ms"
+	DurationMs float64        `json:"duration_ms,omitempty"`            // Numeric, for aggregation
+	Payload    any            `json:"payload,omitempty"`
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler, masking d with the
+// global logger's configuration, so it can also be logged with zap.Object.
+// Logged as data by a Logger, d is masked with that logger's configuration.
+func (d DBData) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return d.marshalMasked(enc, maskedObject{cfg: globalMaskingConfig()})
+}
+
+func (d DBData) marshalMasked(enc zapcore.ObjectEncoder, m maskedObject) error {
+	m.addString(enc, "driver", d.Driver)
+	m.addString(enc, "operation", d.Operation)
+	m.addString(enc, "database", d.Database)
+	m.addString(enc, "table", d.Table)
+	m.addSQL(enc, "statement", d.Statement, d.Driver)
+	m.addScanned(enc, "args", d.Args)
+	addDTOMap(m, enc, "named_args", d.NamedArgs, false)
+	m.addString(enc, "duration", d.Duration)
+	m.addFloat(enc, "duration_ms", d.DurationMs)
+	m.addAny(enc, "payload", d.Payload)
+	return nil
 }
 
 // MQData captures context for Message Queue interactions.
 // It is compatible with Kafka, RabbitMQ, NATS, etc.
+// Sensitive fields in a JSON or structured Payload are automatically masked,
+// and WithMaxPayloadBytes caps its logged size.
 //
 // Example:
 //
 //	data := goslogx.MQData{
-//		Driver:    "kafka",
-//		Operation: "consume",
-//		Topic:     "user-events",
-//		Group:     "notification-service",
-//		MessageID: "msg-123",
+//		Driver:     "kafka",
+//		Operation:  "consume",
+//		Topic:      "user-events",
+//		Group:      "notification-service",
+//		MessageID:  "msg-123",
+//		Duration:   goslogx.FormatDuration(elapsed),
+//		DurationMs: goslogx.DurationMillis(elapsed),
 //	}
-//	goslogx.Info("trace-001", "messaging", goslogx.MESSSAGE_TYPE_IN, "message received", data)
+//	goslogx.Info("trace-001", "messaging", goslogx.MESSAGE_TYPE_IN, "message received", data)
 type MQData struct {
-	Driver    string `json:"driver,omitempty"`
-	Operation string `json:"operation,omitempty"`
-	Topic     string `json:"topic,omitempty"`
-	Group     string `json:"group,omitempty"`
-	MessageID string `json:"message_id,omitempty"`
-	Payload   any    `json:"payload,omitempty"`
+	Driver     string  `json:"driver,omitempty"`
+	Operation  string  `json:"operation,omitempty"`
+	Topic      string  `json:"topic,omitempty"`
+	Group      string  `json:"group,omitempty"`
+	MessageID  string  `json:"message_id,omitempty"`
+	Duration   string  `json:"duration,omitempty"`    // Human-readable, e.g. "3.2ms"
+	DurationMs float64 `json:"duration_ms,omitempty"` // Numeric, for aggregation
+	Payload    any     `json:"payload,omitempty"`
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler, masking d with the
+// global logger's configuration, so it can also be logged with zap.Object.
+// Logged as data by a Logger, d is masked with that logger's configuration.
+func (d MQData) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return d.marshalMasked(enc, maskedObject{cfg: globalMaskingConfig()})
+}
+
+func (d MQData) marshalMasked(enc zapcore.ObjectEncoder, m maskedObject) error {
+	m.addString(enc, "driver", d.Driver)
+	m.addString(enc, "operation", d.Operation)
+	m.addString(enc, "topic", d.Topic)
+	m.addString(enc, "group", d.Group)
+	m.addString(enc, "message_id", d.MessageID)
+	m.addString(enc, "duration", d.Duration)
+	m.addFloat(enc, "duration_ms", d.DurationMs)
+	m.addAny(enc, "payload", d.Payload)
+	return nil
+}
+
+// Supported values for MaskingConfig.DurationFormat.
+const (
+	// DurationFormatString logs time.Duration fields as FormatDuration
+	// strings, e.g. "125ms" (default).
+	DurationFormatString = "string"
+	// DurationFormatMillis logs time.Duration fields as DurationMillis
+	// numbers, e.g. 125.
+	DurationFormatMillis = "millis"
+	// DurationFormatNanos logs time.Duration fields as integer nanoseconds,
+	// e.g. 125000000.
+	DurationFormatNanos = "nanos"
+)
+
+// FormatDuration formats d for the human-readable Duration fields, rounded
+// to a precision that suits its size: microseconds below 1ms, hundredths of
+// a millisecond below 1s, and milliseconds above.
+//
+// Example:
+//
+//	goslogx.FormatDuration(850400 * time.Nanosecond)  // "850µs"
+//	goslogx.FormatDuration(125432 * time.Microsecond) // "125.43ms"
+//	goslogx.FormatDuration(2345678 * time.Microsecond) // "2.346s"
+func FormatDuration(d time.Duration) string {
+	abs := d.Abs()
+	switch {
+	case abs < time.Microsecond:
+		return d.String()
+	case abs < time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	case abs < time.Second:
+		return d.Round(10 * time.Microsecond).String()
+	}
+	return d.Round(time.Millisecond).String()
+}
+
+// DurationMillis returns d in milliseconds, with sub-millisecond precision,
+// for the numeric DurationMs fields.
+//
+// Example:
+//
+//	goslogx.DurationMillis(1500 * time.Microsecond) // 1.5
+func DurationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
 }
 
 // GenericData provides a flexible schema for logging interactions with external
@@ -82,9 +217,80 @@ type MQData struct {
 //			"currency": "USD",
 //		},
 //	}
-//	goslogx.Info("trace-001", "payment", goslogx.MESSSAGE_TYPE_REQUEST, "charge initiated", data)
+//	goslogx.Info("trace-001", "payment", goslogx.MESSAGE_TYPE_REQUEST, "charge initiated", data)
 type GenericData struct {
-	Service string `json:"service,omitempty"`
-	Action  string `json:"action,omitempty"`
-	Payload any    `json:"payload,omitempty"`
+	Service string         `json:"service,omitempty"`
+	Action  string         `json:"action,omitempty"`
+	Payload any            `json:"payload,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"` // Extra context, masked by key name
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler, masking d with the
+// global logger's configuration, so it can also be logged with zap.Object.
+// Logged as data by a Logger, d is masked with that logger's configuration.
+func (d GenericData) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return d.marshalMasked(enc, maskedObject{cfg: globalMaskingConfig()})
+}
+
+func (d GenericData) marshalMasked(enc zapcore.ObjectEncoder, m maskedObject) error {
+	m.addString(enc, "service", d.Service)
+	m.addString(enc, "action", d.Action)
+	m.addAny(enc, "payload", d.Payload)
+	addDTOMap(m, enc, "fields", d.Fields, false)
+	return nil
+}
+
+// GenericDataBuilder builds a GenericData with a fluent API.
+// The zero value is an empty builder ready to use, and a *GenericDataBuilder
+// can be logged directly in place of its Build result.
+//
+// Example:
+//
+//	data := goslogx.NewGenericData("Stripe").
+//		Action("Charge").
+//		Payload(charge).
+//		Field("customer_email", email). // Masked by key name
+//		Build()
+//	goslogx.Info("trace-001", "payment", goslogx.MESSAGE_TYPE_REQUEST, "charge initiated", data)
+type GenericDataBuilder struct {
+	data GenericData
+}
+
+// NewGenericData returns a builder for a GenericData about service.
+func NewGenericData(service string) *GenericDataBuilder {
+	return &GenericDataBuilder{data: GenericData{Service: service}}
+}
+
+// Service sets the external service name.
+func (b *GenericDataBuilder) Service(service string) *GenericDataBuilder {
+	b.data.Service = service
+	return b
+}
+
+// Action sets the operation performed on the service.
+func (b *GenericDataBuilder) Action(action string) *GenericDataBuilder {
+	b.data.Action = action
+	return b
+}
+
+// Payload sets the request or response payload.
+func (b *GenericDataBuilder) Payload(payload any) *GenericDataBuilder {
+	b.data.Payload = payload
+	return b
+}
+
+// Field adds an extra field, replacing any earlier value for key.
+func (b *GenericDataBuilder) Field(key string, value any) *GenericDataBuilder {
+	if b.data.Fields == nil {
+		b.data.Fields = make(map[string]any)
+	}
+	b.data.Fields[key] = value
+	return b
+}
+
+// Build returns the GenericData. Later calls on the builder do not affect it.
+func (b *GenericDataBuilder) Build() GenericData {
+	data := b.data
+	data.Fields = maps.Clone(b.data.Fields)
+	return data
 }
@@ -0,0 +1,200 @@
+package goslogx
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// The reflected* types share the fields and tags of the DTOs but not their
+// methods, so they are marshaled by reflection.
+type (
+	reflectedHTTPData    HTTPData
+	reflectedDBData      DBData
+	reflectedMQData      MQData
+	reflectedGenericData GenericData
+)
+
+func TestDTOMarshalersMatchReflection(t *testing.T) {
+	dtos := []struct {
+		name      string
+		dto       any
+		reflected any
+	}{
+		{"HTTPData", HTTPData{
+			Method:     "POST",
+			URL:        "/api/v1/login",
+			StatusCode: 200,
+			Headers:    map[string][]string{"Authorization": {"Bearer abc123"}, "Cookie": {"sid=xyz; theme=dark"}, "Accept": {"a", "b"}},
+			Body:       `{"username":"jane","password":"hunter2"}`,
+			Duration:   "1.5ms",
+			DurationMs: 1.5,
+			ClientIP:   "203.0.113.42",
+		}, nil},
+		{"HTTPDataEmpty", HTTPData{}, nil},
+		{"DBData", DBData{
+			Driver:     "postgres",
+			Operation:  "SELECT",
+			Database:   "app",
+			Table:      "users",
+			Statement:  "SELECT id FROM users WHERE email = 'a@b.co' AND id IN (1, 2)",
+			Args:       []any{"jane.doe@example.com", 42, nil, []any{"+6281234567890"}},
+			NamedArgs:  map[string]any{"password": "hunter2", "id": 42, "user": map[string]any{"email": "a@b.co"}},
+			Duration:   "45ms",
+			DurationMs: 45,
+			Payload:    map[string]any{"token": "abc", "rows": 2},
+		}, nil},
+		{"MQData", MQData{
+			Driver:    "kafka",
+			Operation: "consume",
+			Topic:     "users",
+			Group:     "mailer",
+			MessageID: "msg-1",
+			Payload:   []byte(`{"email":"jane.doe@example.com"}`),
+		}, nil},
+		{"GenericData", GenericData{
+			Service: "Stripe",
+			Action:  "Charge",
+			Payload: struct {
+				Card string `json:"card" log:"masked:full"`
+			}{"4111111111111111"},
+			Fields: map[string]any{"customer_email": "jane.doe@example.com", "amount": 100},
+		}, nil},
+	}
+	for i, d := range dtos {
+		switch v := d.dto.(type) {
+		case HTTPData:
+			dtos[i].reflected = reflectedHTTPData(v)
+		case DBData:
+			dtos[i].reflected = reflectedDBData(v)
+		case MQData:
+			dtos[i].reflected = reflectedMQData(v)
+		case GenericData:
+			dtos[i].reflected = reflectedGenericData(v)
+		}
+	}
+
+	configs := []struct {
+		name string
+		opts []Option
+	}{
+		{"Defaults", nil},
+		{"MaskingDisabled", []Option{WithMasking(false)}},
+		{"AutoMaskByName", []Option{WithAutoMaskByName(true)}},
+		{"Allowlist", []Option{WithAllowFields([]string{"method", "url", "topic"})}},
+		{"IPAndSQL", []Option{WithAnonymizeIP(true), WithSQLLiteralMasking(true), WithSQLNormalization(true)}},
+		{"Redact", []Option{WithRedactFields([]string{"url", "args", "group", "fields"}), WithRedactHeaders([]string{"cookie"})}},
+		{"FieldMasker", []Option{WithFieldMasker(func(name string, value any) (any, bool) {
+			if name == "duration" || name == "payload" {
+				return "<hidden>", true
+			}
+			return nil, false
+		})}},
+		{"FlattenHeaders", []Option{WithHeaderFlatten(true), WithMaxPayloadBytes(16)}},
+		{"MaxDepth", []Option{WithMaskingMaxDepth(1)}},
+	}
+	encode := func(t *testing.T, cfg *MaskingConfig, v any) (string, int) {
+		t.Helper()
+		count := &maskCounter{key: KeyMaskedCount}
+		enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+		buf, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{zap.Object("data", maskedObject{v: v, cfg: cfg, count: count})})
+		if err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		return buf.String(), count.n
+	}
+	for _, c := range configs {
+		cfg := setupLog(c.opts...).config.Masking
+		for _, d := range dtos {
+			t.Run(c.name+"/"+d.name, func(t *testing.T) {
+				got, gotCount := encode(t, &cfg, d.dto)
+				want, wantCount := encode(t, &cfg, d.reflected)
+				if got != want {
+					t.Errorf("Hand-written marshaler differs from reflection\ngot:  %s\nwant: %s", got, want)
+				}
+				if gotCount != wantCount {
+					t.Errorf("Expected %d masked values, got %d", wantCount, gotCount)
+				}
+
+				// Logged by a standalone logger, masked with its configuration
+				buf := &bytes.Buffer{}
+				logger := setupLog(append([]Option{WithOutput(buf)}, c.opts...)...)
+				logger.Info("trace-1", "dto", MESSSAGE_TYPE_EVENT, "hand-written", d.dto)
+				logger.Info("trace-2", "dto", MESSSAGE_TYPE_EVENT, "reflected", d.reflected)
+				entries := decodeEntries(t, buf.Bytes())
+				if !reflect.DeepEqual(entries[0]["data"], entries[1]["data"]) {
+					t.Errorf("Logged hand-written DTO differs from reflection\ngot:  %v\nwant: %v", entries[0]["data"], entries[1]["data"])
+				}
+			})
+		}
+	}
+}
+
+func TestDTOMarshalLogObject(t *testing.T) {
+	prev := globalLog.Swap(setupLog(WithAnonymizeIP(true)))
+	defer globalLog.Store(prev)
+
+	enc := zapcore.NewMapObjectEncoder()
+	data := HTTPData{
+		Headers:  map[string][]string{"Authorization": {"Bearer abc123"}},
+		ClientIP: "203.0.113.42",
+	}
+	if err := enc.AddObject("data", data); err != nil {
+		t.Fatalf("AddObject: %v", err)
+	}
+	got := enc.Fields["data"].(map[string]any)
+	if ip := got["client_ip"]; ip != "203.0.113.0" {
+		t.Errorf("Expected the global configuration to apply, got client_ip %v", ip)
+	}
+	if auth := got["headers"].(map[string]any)["Authorization"]; auth.([]any)[0] != "****" {
+		t.Errorf("Expected Authorization to be masked, got %v", auth)
+	}
+
+	t.Run("UnmaskedLevels", func(t *testing.T) {
+		f := unmaskedField("data", data, &MaskingConfig{})
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		if ip := enc.Fields["data"].(HTTPData).ClientIP; ip != data.ClientIP {
+			t.Errorf("Expected unmasked data, got client_ip %v", ip)
+		}
+	})
+}
+
+func BenchmarkHTTPData_Marshal(b *testing.B) {
+	data := HTTPData{
+		Method:     "POST",
+		URL:        "/api/v1/auth/login",
+		StatusCode: 200,
+		Headers: map[string][]string{
+			"Authorization": {"Bearer token123"},
+			"Content-Type":  {"application/json"},
+		},
+		Body:     `{"username":"admin","password":"secret"}`,
+		Duration: "125ms",
+		ClientIP: "203.0.113.42",
+	}
+	plain := HTTPData{Method: "GET", URL: "/health", StatusCode: 200, Duration: "45ms", DurationMs: 45}
+	cfg := setupLog().config.Masking
+	for _, bm := range []struct {
+		name string
+		v    any
+	}{
+		{"HandWritten", data},
+		{"Reflection", reflectedHTTPData(data)},
+		{"HandWrittenPlain", plain},
+		{"ReflectionPlain", reflectedHTTPData(plain)},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+			obj := maskedObject{v: bm.v, cfg: &cfg}
+			b.ReportAllocs()
+			for b.Loop() {
+				buf, _ := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{zap.Object("data", obj)})
+				buf.Free()
+			}
+		})
+	}
+}
@@ -6,14 +6,40 @@ package goslogx
 type MsgType string
 
 const (
-	// MESSSAGE_TYPE_IN indicates an incoming message or data
-	MESSSAGE_TYPE_IN MsgType = "IN"
-	// MESSSAGE_TYPE_OUT indicates an outgoing message or data
-	MESSSAGE_TYPE_OUT MsgType = "OUT"
-	// MESSSAGE_TYPE_REQUEST indicates an outgoing request to external service
-	MESSSAGE_TYPE_REQUEST MsgType = "REQUEST"
-	// MESSSAGE_TYPE_RESPONSE indicates an incoming response from external service
-	MESSSAGE_TYPE_RESPONSE MsgType = "RESPONSE"
-	// MESSSAGE_TYPE_EVENT indicates an application event
-	MESSSAGE_TYPE_EVENT MsgType = "EVENT"
+	// MESSAGE_TYPE_IN indicates an incoming message or data
+	MESSAGE_TYPE_IN MsgType = "IN"
+	// MESSAGE_TYPE_OUT indicates an outgoing message or data
+	MESSAGE_TYPE_OUT MsgType = "OUT"
+	// MESSAGE_TYPE_REQUEST indicates an outgoing request to external service
+	MESSAGE_TYPE_REQUEST MsgType = "REQUEST"
+	// MESSAGE_TYPE_RESPONSE indicates an incoming response from external service
+	MESSAGE_TYPE_RESPONSE MsgType = "RESPONSE"
+	// MESSAGE_TYPE_EVENT indicates an application event
+	MESSAGE_TYPE_EVENT MsgType = "EVENT"
+	// MESSAGE_TYPE_UNKNOWN replaces unrecognized message types, see WithStrictMsgType
+	MESSAGE_TYPE_UNKNOWN MsgType = "UNKNOWN"
 )
+
+// Misspelled names kept so existing code keeps compiling.
+const (
+	// Deprecated: Use MESSAGE_TYPE_IN.
+	MESSSAGE_TYPE_IN = MESSAGE_TYPE_IN
+	// Deprecated: Use MESSAGE_TYPE_OUT.
+	MESSSAGE_TYPE_OUT = MESSAGE_TYPE_OUT
+	// Deprecated: Use MESSAGE_TYPE_REQUEST.
+	MESSSAGE_TYPE_REQUEST = MESSAGE_TYPE_REQUEST
+	// Deprecated: Use MESSAGE_TYPE_RESPONSE.
+	MESSSAGE_TYPE_RESPONSE = MESSAGE_TYPE_RESPONSE
+	// Deprecated: Use MESSAGE_TYPE_EVENT.
+	MESSSAGE_TYPE_EVENT = MESSAGE_TYPE_EVENT
+)
+
+// IsValidMsgType reports whether t is one of the defined message types.
+func IsValidMsgType(t MsgType) bool {
+	switch t {
+	case MESSAGE_TYPE_IN, MESSAGE_TYPE_OUT, MESSAGE_TYPE_REQUEST,
+		MESSAGE_TYPE_RESPONSE, MESSAGE_TYPE_EVENT, MESSAGE_TYPE_UNKNOWN:
+		return true
+	}
+	return false
+}
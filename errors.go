@@ -0,0 +1,230 @@
+package goslogx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	pkgerrors "github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// stackTracer is implemented by errors from github.com/pkg/errors
+// (New, Errorf, Wrap, WithStack, ...), which record where they were created.
+type stackTracer interface {
+	StackTrace() pkgerrors.StackTrace
+}
+
+// errorCoder is implemented by domain errors that carry a machine-readable
+// code, logged as its own field so it can be indexed.
+type errorCoder interface {
+	Code() string
+}
+
+// errorFielder is implemented by domain errors that carry structured context,
+// merged into the entry next to the error.
+type errorFielder interface {
+	Fields() map[string]any
+}
+
+// errorField logs the error message only. zap.NamedError would also add an
+// "<key>Verbose" field for errors implementing fmt.Formatter, repeating the
+// message and stack of pkg/errors errors; the stack is logged separately.
+func errorField(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.String(key, err.Error())
+}
+
+// appendErrorDetails appends the optional causes array and the code and fields
+// of structured errors in err's chain. Fields are added in sorted key order and
+// masked like the entries of a map passed as data.
+func (l *Logger) appendErrorDetails(fields []zap.Field, err error) []zap.Field {
+	if err == nil {
+		return fields
+	}
+	keys := &l.config.FieldKeys
+	if l.config.ErrorCauses {
+		fields = append(fields, zap.Strings(keys.Causes, errorCauses(err)))
+	}
+	var coder errorCoder
+	if errors.As(err, &coder) {
+		fields = append(fields, zap.String(keys.ErrorCode, coder.Code()))
+	}
+	var fielder errorFielder
+	if errors.As(err, &fielder) {
+		extra := fielder.Fields()
+		names := make([]string, 0, len(extra))
+		for k := range extra {
+			if !l.config.Masking.redacts(k) {
+				names = append(names, k)
+			}
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			fields = append(fields, l.extraField(k, extra[k]))
+		}
+	}
+	return fields
+}
+
+// Field is a key-value pair of extra context for an Error entry, masked like
+// the entries of a map passed as data.
+//
+// Example:
+//
+//	goslogx.Error(traceID, "payments", err,
+//	    goslogx.Field{Key: "order_id", Val: orderID},
+//	    goslogx.Field{Key: "card_number", Val: card}, // Masked by name
+//	)
+type Field struct {
+	Key string
+	Val any
+}
+
+// appendFields appends extra, in order, leaving out the keys listed in
+// MaskingConfig.RedactFields.
+func (l *Logger) appendFields(fields []zap.Field, extra []Field) []zap.Field {
+	for _, f := range extra {
+		if !l.config.Masking.redacts(f.Key) {
+			fields = append(fields, l.extraField(f.Key, f.Val))
+		}
+	}
+	return fields
+}
+
+// extraField returns the field for an error's extra context, masking
+// sensitive names like the keys of a logged map.
+func (l *Logger) extraField(key string, v any) zap.Field {
+	if str, ok := v.(string); ok {
+		return zap.String(key, maskString(str, l.config.Masking.fieldMask(key)))
+	}
+	return maskedField(key, v, &l.config.Masking)
+}
+
+// errorCauses returns the messages of err and each error it wraps, from the
+// outermost to the root cause, following Unwrap like errors.Is does.
+// Consecutive equal messages are collapsed, since pkg/errors.WithStack and
+// similar wrappers add a stack without changing the message.
+func errorCauses(err error) []string {
+	var causes []string
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		msg := e.Error()
+		if n := len(causes); n > 0 && causes[n-1] == msg {
+			continue
+		}
+		causes = append(causes, msg)
+	}
+	return causes
+}
+
+// appendCompactError appends the root cause of err and the file:line of the
+// top frame of stack, where err originated, see WithCompactErrors.
+func (l *Logger) appendCompactError(fields []zap.Field, err error, stack string) []zap.Field {
+	if err == nil {
+		return fields
+	}
+	keys := &l.config.FieldKeys
+	fields = append(fields, zap.String(keys.ErrorCause, rootCause(err)))
+	if origin := stackOrigin(stack); origin != "" {
+		fields = append(fields, zap.String(keys.ErrorOrigin, origin))
+	}
+	return fields
+}
+
+// rootCause returns the message of the innermost error in err's Unwrap chain,
+// or of err itself if it wraps none.
+func rootCause(err error) string {
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err.Error()
+		}
+		err = next
+	}
+}
+
+// stackOrigin returns the file:line of the top frame of stack, formatted as
+// "function\n\tfile:line" pairs like errorStack returns, with the file
+// trimmed to its package directory like the source field. Returns "" if
+// stack holds no frame.
+//
+// Example: stackOrigin("main.run\n\t/src/app/main.go:42\n...") -> "app/main.go:42"
+func stackOrigin(stack string) string {
+	_, rest, ok := strings.Cut(stack, "\n\t")
+	if !ok {
+		return ""
+	}
+	line, _, _ := strings.Cut(rest, "\n")
+	// Runtime stacks follow the line with the frame's PC offset, " +0x1d"
+	if i := strings.LastIndex(line, " +0x"); i >= 0 {
+		line = line[:i]
+	}
+	if i := strings.LastIndexByte(line, '/'); i >= 0 {
+		if j := strings.LastIndexByte(line[:i], '/'); j >= 0 {
+			line = line[j+1:]
+		}
+	}
+	return line
+}
+
+// errorStack returns the stack trace recorded by the innermost error in err's
+// chain that carries one, one "function\n\tfile:line" pair per frame like
+// runtime stacks. ok is false when no error in the chain carries a stack,
+// e.g. errors.New or fmt.Errorf without a pkg/errors error underneath.
+func errorStack(err error) (stack string, ok bool) {
+	var st stackTracer
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		// Keep going: the innermost stack points at the error's origin
+		if s, is := e.(stackTracer); is {
+			st = s
+		}
+	}
+	if st == nil {
+		return "", false
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%+v", st.StackTrace()), "\n"), true
+}
+
+// KeyValidationErrors is the conventional data key for a []FieldError.
+const KeyValidationErrors = "validation_errors"
+
+// FieldError describes why one input field failed validation. Emails, card
+// numbers, phone numbers, and "key=value" secrets in Message are masked, since
+// validation messages often quote the rejected value.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message" log:"masked:text"`
+}
+
+// ValidationErrors returns errs as a zapcore.ArrayMarshaler of
+// {"field","message"} objects, for logging with zap.Array, where they are
+// masked with the global logger's configuration. Logged as data, they are
+// masked with the configuration of the Logger, like the slice itself, which
+// can also be passed as data, or under KeyValidationErrors in a data map.
+//
+// Example:
+//
+//	errs := []goslogx.FieldError{
+//		{Field: "email", Message: "john.doe@example is not a valid email"},
+//		{Field: "age", Message: "must be at least 18"},
+//	}
+//	goslogx.Warning("trace-001", "signup", "invalid request",
+//		map[string]any{goslogx.KeyValidationErrors: errs})
+//	zapLogger.Warn("invalid request", zap.Array(goslogx.KeyValidationErrors, goslogx.ValidationErrors(errs)))
+func ValidationErrors(errs []FieldError) zapcore.ArrayMarshaler {
+	return validationErrors(errs)
+}
+
+// validationErrors is the ArrayMarshaler returned by ValidationErrors.
+// countedField masks it with the logger's configuration.
+type validationErrors []FieldError
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (errs validationErrors) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return maskedArray{v: reflect.ValueOf(errs), cfg: globalMaskingConfig()}.MarshalLogArray(enc)
+}
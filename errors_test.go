@@ -0,0 +1,460 @@
+package goslogx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/muhammadluth/goslogx"
+	pkgerrors "github.com/pkg/errors"
+)
+
+func newPkgError() error {
+	return pkgerrors.New("pkg error")
+}
+
+// TestErrorStack verifies the error and stack_trace fields for each kind of error
+func TestErrorStack(t *testing.T) {
+	buf := &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf))()
+
+	tests := []struct {
+		name      string
+		err       error
+		wantError string
+		wantStack string // Function expected at the top of the stack; empty means no stack
+	}{
+		{"Stdlib", errors.New("stdlib error"), "stdlib error", ""},
+		{"StdlibWrapped", fmt.Errorf("load config: %w", errors.New("stdlib error")), "load config: stdlib error", ""},
+		{"PkgErrors", newPkgError(), "pkg error", "goslogx_test.newPkgError"},
+		{"PkgErrorsWrapped", pkgerrors.Wrap(newPkgError(), "query"), "query: pkg error", "goslogx_test.newPkgError"},
+		{"PkgErrorsInFmtChain", fmt.Errorf("handler: %w", fmt.Errorf("service: %w", newPkgError())), "handler: service: pkg error", "goslogx_test.newPkgError"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			goslogx.Error("trace-001", "test", tt.err)
+
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("Failed to unmarshal log entry: %v (%s)", err, buf.String())
+			}
+			if entry["error"] != tt.wantError {
+				t.Errorf("Expected error %q, got %v", tt.wantError, entry["error"])
+			}
+			if _, ok := entry["errorVerbose"]; ok {
+				t.Errorf("Expected no errorVerbose field, got %v", entry["errorVerbose"])
+			}
+
+			stack, ok := entry["stack_trace"].(string)
+			if tt.wantStack == "" {
+				if ok {
+					t.Errorf("Expected no stack trace, got %q", stack)
+				}
+				return
+			}
+			if !strings.HasPrefix(stack, "[github.com/muhammadluth/"+tt.wantStack+" | ") {
+				t.Errorf("Expected stack starting at %s, got %q", tt.wantStack, stack)
+			}
+			if strings.Contains(stack, tt.wantError) {
+				t.Errorf("Expected stack without the error message, got %q", stack)
+			}
+		})
+	}
+}
+
+// deepPkgError returns a pkg/errors error created depth calls deep.
+func deepPkgError(depth int) error {
+	if depth == 0 {
+		return pkgerrors.New("deep error")
+	}
+	return deepPkgError(depth - 1)
+}
+
+// deepCall calls fn depth calls deep.
+func deepCall(depth int, fn func()) {
+	if depth == 0 {
+		fn()
+		return
+	}
+	deepCall(depth-1, fn)
+}
+
+// TestErrorStackDepth verifies WithErrorStackDepth keeps only the top frames in both formats
+func TestErrorStackDepth(t *testing.T) {
+	err := deepPkgError(50)
+
+	for _, format := range []string{goslogx.StackTraceInline, goslogx.StackTraceMultiline} {
+		t.Run(format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			defer goslogx.ReplaceGlobal(
+				goslogx.WithOutput(buf),
+				goslogx.WithStackTraceFormat(format),
+				goslogx.WithErrorStackDepth(3),
+			)()
+
+			goslogx.Error("trace-001", "test", err)
+			deepCall(50, func() {
+				goslogx.WarningErr("trace-002", "test", "retrying", errors.New("timeout"))
+			})
+			deepCall(50, func() {
+				defer goslogx.RecoverAndLog("trace-003", "test")()
+				panic("boom")
+			})
+
+			dec := json.NewDecoder(buf)
+			for i := 0; dec.More(); i++ {
+				var entry map[string]any
+				if err := dec.Decode(&entry); err != nil {
+					t.Fatalf("Failed to decode entry: %v", err)
+				}
+				stack := entry["stack_trace"].(string)
+				var frames []string
+				if format == goslogx.StackTraceInline {
+					frames = strings.Split(strings.Trim(stack, "[]"), " | ")
+				} else {
+					frames = strings.Split(stack, "\n")
+				}
+				last := frames[len(frames)-1]
+				if !strings.HasPrefix(last, "... (") || !strings.HasSuffix(last, " more)") {
+					t.Errorf("Entry %d: expected a trailing \"... (M more)\", got %q", i, stack)
+				}
+				var funcs int
+				for _, frame := range frames[:len(frames)-1] {
+					if !strings.HasPrefix(frame, "\t") && !strings.HasPrefix(frame, "/") && !strings.HasPrefix(frame, "goroutine ") {
+						funcs++
+					}
+				}
+				if funcs != 3 {
+					t.Errorf("Entry %d: expected 3 frames, got %d in %q", i, funcs, stack)
+				}
+			}
+		})
+	}
+
+	t.Run("Unlimited", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf))()
+		goslogx.Error("trace-001", "test", err)
+		if strings.Contains(buf.String(), " more)") {
+			t.Errorf("Expected the full stack by default, got %s", buf.String())
+		}
+		// pkg/errors records up to 32 frames
+		if n := strings.Count(buf.String(), "goslogx_test.deepPkgError"); n < 30 {
+			t.Errorf("Expected every recorded deepPkgError frame, got %d", n)
+		}
+	})
+}
+
+// TestErrorCauses verifies the causes array lists a %w chain down to the root cause
+func TestErrorCauses(t *testing.T) {
+	buf := &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf), goslogx.WithErrorCauses(true))()
+
+	root := errors.New("connection refused")
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{"Single", root, []string{"connection refused"}},
+		{"ThreeLevels", fmt.Errorf("handler: %w", fmt.Errorf("repository: %w", fmt.Errorf("dial: %w", root))), []string{
+			"handler: repository: dial: connection refused",
+			"repository: dial: connection refused",
+			"dial: connection refused",
+			"connection refused",
+		}},
+		{"PkgErrors", fmt.Errorf("handler: %w", pkgerrors.Wrap(root, "dial")), []string{
+			"handler: dial: connection refused",
+			"dial: connection refused",
+			"connection refused",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			goslogx.Error("trace-001", "test", tt.err)
+
+			var entry struct {
+				Error  string   `json:"error"`
+				Causes []string `json:"causes"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("Failed to unmarshal log entry: %v (%s)", err, buf.String())
+			}
+			if entry.Error != tt.want[0] {
+				t.Errorf("Expected error %q, got %q", tt.want[0], entry.Error)
+			}
+			if !reflect.DeepEqual(entry.Causes, tt.want) {
+				t.Errorf("Expected causes %q, got %q", tt.want, entry.Causes)
+			}
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf))()
+		buf.Reset()
+		goslogx.Error("trace-002", "test", fmt.Errorf("handler: %w", root))
+		if strings.Contains(buf.String(), `"causes"`) {
+			t.Errorf("Expected no causes by default, got %s", buf.String())
+		}
+	})
+}
+
+// TestCompactErrors verifies WithCompactErrors replaces the stack with the root cause and origin
+func TestCompactErrors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf), goslogx.WithCompactErrors(true))()
+
+	root := errors.New("connection refused")
+	pkgErr, pkgLine := pkgerrors.Wrap(root, "dial"), callerLine()
+	tests := []struct {
+		name      string
+		err       error
+		wantCause string
+		wantLine  int // Line of errors_test.go expected as the origin; 0 means the Error call
+	}{
+		{"Stdlib", root, "connection refused", 0},
+		{"Wrapped", fmt.Errorf("handler: %w", fmt.Errorf("repository: %w", root)), "connection refused", 0},
+		{"PkgErrors", fmt.Errorf("handler: %w", pkgErr), "connection refused", pkgLine},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			goslogx.Error("trace-001", "test", tt.err)
+			logLine := callerLine() - 1
+
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("Failed to unmarshal log entry: %v (%s)", err, buf.String())
+			}
+			if entry[goslogx.KeyError] != tt.err.Error() {
+				t.Errorf("Expected error %q, got %v", tt.err.Error(), entry[goslogx.KeyError])
+			}
+			if entry[goslogx.KeyErrorCause] != tt.wantCause {
+				t.Errorf("Expected error_cause %q, got %v", tt.wantCause, entry[goslogx.KeyErrorCause])
+			}
+			wantLine := tt.wantLine
+			if wantLine == 0 {
+				wantLine = logLine
+			}
+			want := "/errors_test.go:" + strconv.Itoa(wantLine)
+			if origin, _ := entry[goslogx.KeyErrorOrigin].(string); !strings.HasSuffix(origin, want) || strings.Count(origin, "/") != 1 {
+				t.Errorf("Expected error_origin ending in %q, got %q", want, origin)
+			}
+			if stack, ok := entry[goslogx.KeyStackTrace]; ok {
+				t.Errorf("Expected no stack trace, got %v", stack)
+			}
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf))()
+		buf.Reset()
+		goslogx.Error("trace-002", "test", pkgErr)
+		if strings.Contains(buf.String(), `"error_cause"`) || !strings.Contains(buf.String(), `"stack_trace"`) {
+			t.Errorf("Expected the full stack trace by default, got %s", buf.String())
+		}
+	})
+}
+
+// TestCompactErrorsFatal runs Fatal in a separate process and checks its compact entry
+func TestCompactErrorsFatal(t *testing.T) {
+	if os.Getenv("BE_COMPACT_CRASHER") == "1" {
+		goslogx.ReplaceGlobal(goslogx.WithOutput(os.Stdout), goslogx.WithCompactErrors(true))
+		goslogx.Fatal("crash-trace", "main", fmt.Errorf("startup: %w", errors.New("critical failure")))
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestCompactErrorsFatal")
+	cmd.Env = append(os.Environ(), "BE_COMPACT_CRASHER=1")
+	out, err := cmd.Output()
+	if e, ok := err.(*exec.ExitError); !ok || e.Success() {
+		t.Fatalf("process ran with err %v, want exit status 1", err)
+	}
+	start := bytes.IndexByte(out, '{')
+	if start < 0 {
+		t.Fatalf("Expected a log entry, got %q", out)
+	}
+	line, _, _ := strings.Cut(string(out[start:]), "\n")
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("Failed to unmarshal log entry: %v (%s)", err, out)
+	}
+	if entry[goslogx.KeyErrorCause] != "critical failure" {
+		t.Errorf("Expected error_cause %q, got %v", "critical failure", entry[goslogx.KeyErrorCause])
+	}
+	if origin, _ := entry[goslogx.KeyErrorOrigin].(string); !strings.Contains(origin, "/errors_test.go:") {
+		t.Errorf("Expected error_origin at the Fatal call, got %q", origin)
+	}
+	if stack, ok := entry[goslogx.KeyStackTrace]; ok {
+		t.Errorf("Expected no stack trace, got %v", stack)
+	}
+}
+
+// DomainError is a structured error exposing a code and context fields
+type DomainError struct {
+	code   string
+	fields map[string]any
+}
+
+func (e *DomainError) Error() string          { return "domain error " + e.code }
+func (e *DomainError) Code() string           { return e.code }
+func (e *DomainError) Fields() map[string]any { return e.fields }
+
+// TestErrorCodeAndFields verifies structured errors surface their code and fields
+func TestErrorCodeAndFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf))()
+
+	domainErr := &DomainError{
+		code:   "ORDER_NOT_FOUND",
+		fields: map[string]any{"order_id": "ord-001", "password": "secret123"},
+	}
+	tests := []struct {
+		name string
+		log  func(err error)
+	}{
+		{"Error", func(err error) { goslogx.Error("trace-001", "test", err) }},
+		{"WarningErr", func(err error) { goslogx.WarningErr("trace-002", "test", "retrying", err) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.log(fmt.Errorf("checkout: %w", domainErr))
+
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("Failed to unmarshal log entry: %v (%s)", err, buf.String())
+			}
+			if entry["error_code"] != "ORDER_NOT_FOUND" {
+				t.Errorf("Expected error_code ORDER_NOT_FOUND, got %v", entry["error_code"])
+			}
+			if entry["order_id"] != "ord-001" {
+				t.Errorf("Expected order_id field, got %v", entry["order_id"])
+			}
+			if entry["password"] == "secret123" {
+				t.Error("Expected password field to be masked")
+			}
+		})
+	}
+
+	t.Run("PlainError", func(t *testing.T) {
+		buf.Reset()
+		goslogx.Error("trace-003", "test", errors.New("plain error"))
+		if strings.Contains(buf.String(), "error_code") {
+			t.Errorf("Expected no error_code for plain errors, got %s", buf.String())
+		}
+	})
+}
+
+func TestValidationErrors(t *testing.T) {
+	errs := []goslogx.FieldError{
+		{Field: "email", Message: "john.doe@example.com is already registered"},
+		{Field: "phone", Message: "+6281234567890 is not reachable"},
+		{Field: "age", Message: "must be at least 18"},
+	}
+	want := []any{
+		map[string]any{"field": "email", "message": "jo****@example.com is already registered"},
+		map[string]any{"field": "phone", "message": "+62********90 is not reachable"},
+		map[string]any{"field": "age", "message": "must be at least 18"},
+	}
+
+	tests := []struct {
+		name string
+		data any
+		get  func(data any) any
+	}{
+		{"Slice", errs, func(data any) any { return data }},
+		{"Map", map[string]any{goslogx.KeyValidationErrors: errs}, func(data any) any {
+			return data.(map[string]any)[goslogx.KeyValidationErrors]
+		}},
+		{"ArrayMarshaler", goslogx.ValidationErrors(errs), func(data any) any { return data }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			defer goslogx.ReplaceGlobal(goslogx.WithOutput(&buf))()
+
+			goslogx.Warning("trace-1", "signup", "invalid request", tt.data)
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("Failed to decode %q: %v", buf.String(), err)
+			}
+			if got := tt.get(entry["data"]); !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected %v, got %v", want, got)
+			}
+		})
+	}
+
+	t.Run("StandaloneLogger", func(t *testing.T) {
+		// Masked with the logger's configuration, not the global logger's
+		var buf bytes.Buffer
+		logger := goslogx.NewLogger(goslogx.WithOutput(&buf), goslogx.WithRedactFields([]string{"message"}))
+		logger.Warning("trace-1", "signup", "invalid request", goslogx.ValidationErrors(errs))
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to decode %q: %v", buf.String(), err)
+		}
+		want := []any{map[string]any{"field": "email"}, map[string]any{"field": "phone"}, map[string]any{"field": "age"}}
+		if !reflect.DeepEqual(entry["data"], want) {
+			t.Errorf("Expected %v, got %v", want, entry["data"])
+		}
+	})
+}
+
+func TestErrorFields(t *testing.T) {
+	type account struct {
+		ID       string `json:"id"`
+		Password string `json:"password" log:"masked:full"`
+	}
+	tests := []struct {
+		name  string
+		extra []goslogx.Field
+		want  map[string]any
+	}{
+		{"None", nil, map[string]any{}},
+		{"Several", []goslogx.Field{
+			{Key: "order_id", Val: "ord-42"},
+			{Key: "attempt", Val: 3},
+			{Key: "password", Val: "hunter2"},
+			{Key: "account", Val: account{ID: "acc-1", Password: "hunter2"}},
+			{Key: "internal", Val: "dropped"},
+		}, map[string]any{
+			"order_id": "ord-42",
+			"attempt":  float64(3),
+			"password": "****",
+			"account":  map[string]any{"id": "acc-1", "password": "****"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			defer goslogx.ReplaceGlobal(goslogx.WithOutput(&buf), goslogx.WithRedactFields([]string{"internal"}))()
+
+			goslogx.Error("trace-1", "orders", errors.New("payment failed"), tt.extra...)
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("Failed to decode %q: %v", buf.String(), err)
+			}
+			if entry["error"] != "payment failed" || entry["trace_id"] != "trace-1" {
+				t.Errorf("Expected the error entry, got %v", entry)
+			}
+			for k, v := range tt.want {
+				if !reflect.DeepEqual(entry[k], v) {
+					t.Errorf("Expected %s=%v, got %v", k, v, entry[k])
+				}
+			}
+			if _, ok := entry["internal"]; ok {
+				t.Errorf("Expected redacted field to be dropped, got %v", entry["internal"])
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package goslogx
+
+// ReplaceGlobal swaps the global logger for one built from opts, bypassing
+// the once-only New, and returns a function that restores the previous logger.
+// It is only compiled into tests, for external tests that assert on output.
+func ReplaceGlobal(opts ...Option) (restore func()) {
+	prev := globalLog.Load()
+	globalLog.Store(setupLog(opts...))
+	return func() { globalLog.Store(prev) }
+}
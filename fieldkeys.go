@@ -0,0 +1,169 @@
+package goslogx
+
+import "fmt"
+
+// Default keys for the well-known log fields.
+const (
+	KeyTime            = "time"
+	KeyLevel           = "level"
+	KeyMessage         = "msg"
+	KeySource          = "source"
+	KeyFunction        = "function"
+	KeyStackTrace      = "stack_trace"
+	KeyApplicationName = "application_name"
+	KeyHostname        = "hostname"
+	KeyPID             = "pid"
+	KeyTraceID         = "trace_id"
+	KeySpanID          = "span_id"
+	KeyModule          = "module"
+	KeyMsgType         = "msg_type"
+	KeySeverity        = "severity"
+	KeyData            = "data"
+	KeyError           = "error"
+	KeyCauses          = "causes"
+	KeyErrorCode       = "error_code"
+	KeyErrorCause      = "error_cause"
+	KeyErrorOrigin     = "error_origin"
+	KeyRepeated        = "repeated"
+	KeyMaskedCount     = "masked_field_count"
+	KeySeq             = "seq"
+)
+
+// FieldKeys holds the JSON keys emitted for the well-known log fields.
+// Use WithFieldKeys to remap individual keys; unspecified keys keep their defaults.
+type FieldKeys struct {
+	Time            string
+	Level           string
+	Message         string
+	Source          string
+	Function        string
+	StackTrace      string
+	ApplicationName string
+	Hostname        string
+	PID             string
+	TraceID         string
+	SpanID          string
+	Module          string
+	MsgType         string
+	Severity        string
+	Data            string
+	Error           string
+	Causes          string
+	ErrorCode       string
+	ErrorCause      string
+	ErrorOrigin     string
+	Repeated        string
+	MaskedCount     string
+	Seq             string
+}
+
+// defaultFieldKeys returns the default well-known field keys.
+func defaultFieldKeys() FieldKeys {
+	return FieldKeys{
+		Time:            KeyTime,
+		Level:           KeyLevel,
+		Message:         KeyMessage,
+		Source:          KeySource,
+		Function:        KeyFunction,
+		StackTrace:      KeyStackTrace,
+		ApplicationName: KeyApplicationName,
+		Hostname:        KeyHostname,
+		PID:             KeyPID,
+		TraceID:         KeyTraceID,
+		SpanID:          KeySpanID,
+		Module:          KeyModule,
+		MsgType:         KeyMsgType,
+		Severity:        KeySeverity,
+		Data:            KeyData,
+		Error:           KeyError,
+		Causes:          KeyCauses,
+		ErrorCode:       KeyErrorCode,
+		ErrorCause:      KeyErrorCause,
+		ErrorOrigin:     KeyErrorOrigin,
+		Repeated:        KeyRepeated,
+		MaskedCount:     KeyMaskedCount,
+		Seq:             KeySeq,
+	}
+}
+
+// entries returns pointers to each key paired with its default name,
+// in the order keys appear in a log entry.
+func (k *FieldKeys) entries() []struct {
+	name string
+	ptr  *string
+} {
+	return []struct {
+		name string
+		ptr  *string
+	}{
+		{KeyLevel, &k.Level},
+		{KeyTime, &k.Time},
+		{KeySource, &k.Source},
+		{KeyFunction, &k.Function},
+		{KeyMessage, &k.Message},
+		{KeyApplicationName, &k.ApplicationName},
+		{KeyHostname, &k.Hostname},
+		{KeyPID, &k.PID},
+		{KeyTraceID, &k.TraceID},
+		{KeySpanID, &k.SpanID},
+		{KeyModule, &k.Module},
+		{KeyMsgType, &k.MsgType},
+		{KeySeverity, &k.Severity},
+		{KeyData, &k.Data},
+		{KeyMaskedCount, &k.MaskedCount},
+		{KeyError, &k.Error},
+		{KeyErrorCode, &k.ErrorCode},
+		{KeyErrorCause, &k.ErrorCause},
+		{KeyErrorOrigin, &k.ErrorOrigin},
+		{KeyCauses, &k.Causes},
+		{KeyRepeated, &k.Repeated},
+		{KeySeq, &k.Seq},
+		{KeyStackTrace, &k.StackTrace},
+	}
+}
+
+// set remaps the key whose default name is name.
+// Returns false if name is not a well-known key.
+func (k *FieldKeys) set(name, key string) bool {
+	for _, e := range k.entries() {
+		if e.name == name {
+			*e.ptr = key
+			return true
+		}
+	}
+	return false
+}
+
+// normalize resets any remapped key that is empty or collides with another key,
+// so that no field is silently dropped or overwritten in the output.
+// Keys left at their default always win over remapped ones.
+// Returns a description of each rejected remapping.
+func (k *FieldKeys) normalize() []string {
+	var rejected []string
+	entries := k.entries()
+	used := make(map[string]string, len(entries))
+	// First pass: claim keys that kept their default name
+	for _, e := range entries {
+		if *e.ptr == e.name {
+			used[e.name] = e.name
+		}
+	}
+	// Second pass: accept remapped keys that don't collide
+	for _, e := range entries {
+		if *e.ptr == e.name {
+			continue
+		}
+		if *e.ptr == "" {
+			rejected = append(rejected, fmt.Sprintf("%s: empty key", e.name))
+			*e.ptr = e.name
+			continue
+		}
+		if owner, ok := used[*e.ptr]; ok {
+			rejected = append(rejected, fmt.Sprintf("%s -> %s: collides with %s", e.name, *e.ptr, owner))
+			*e.ptr = e.name
+			continue
+		}
+		used[*e.ptr] = e.name
+	}
+	return rejected
+}
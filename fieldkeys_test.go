@@ -0,0 +1,112 @@
+package goslogx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithFieldKeys(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(
+			WithOutput(buf),
+			WithFieldKeys(map[string]string{
+				"trace_id": "traceId",
+				"level":    "zap_level",
+				"severity": "level",
+			}),
+		)
+		logger.Info("trace-123", "mod", MESSSAGE_TYPE_EVENT, "info msg", map[string]string{"foo": "bar"})
+
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to unmarshal log entry: %v (%s)", err, buf.String())
+		}
+		if entry["traceId"] != "trace-123" {
+			t.Errorf("Expected traceId=trace-123, got %v", entry["traceId"])
+		}
+		if entry["level"] != "INFO" {
+			t.Errorf("Expected level=INFO (severity), got %v", entry["level"])
+		}
+		if entry["zap_level"] != "info" {
+			t.Errorf("Expected zap_level=info, got %v", entry["zap_level"])
+		}
+		if _, ok := entry["trace_id"]; ok {
+			t.Error("Expected trace_id to be renamed")
+		}
+		// Unspecified keys keep their defaults
+		for _, key := range []string{"module", "msg_type", "data", "time", "msg", "application_name"} {
+			if _, ok := entry[key]; !ok {
+				t.Errorf("Expected default key %q to be present", key)
+			}
+		}
+	})
+
+	t.Run("CollisionRejected", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(
+			WithOutput(buf),
+			WithFieldKeys(map[string]string{"severity": "level"}),
+		)
+		if !strings.Contains(buf.String(), "field key remap ignored") {
+			t.Errorf("Expected a warning about the rejected remap, got %s", buf.String())
+		}
+		buf.Reset()
+		logger.Info("trace-123", "mod", MESSSAGE_TYPE_EVENT, "info msg", nil)
+
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to unmarshal log entry: %v", err)
+		}
+		if entry["level"] != "info" || entry["severity"] != "INFO" {
+			t.Errorf("Expected both level and severity to survive, got %v", entry)
+		}
+	})
+
+	t.Run("ErrorKey", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf), WithFieldKeys(map[string]string{"error": "err"}))
+		logger.Error("trace-123", "mod", errors.New("boom"))
+		if !strings.Contains(buf.String(), `"err":"boom"`) {
+			t.Errorf("Expected renamed error key, got %s", buf.String())
+		}
+	})
+}
+
+func TestFieldKeysNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		remap    map[string]string
+		rejected int
+	}{
+		{"NoRemap", nil, 0},
+		{"Valid", map[string]string{"trace_id": "traceId"}, 0},
+		{"Empty", map[string]string{"module": ""}, 1},
+		{"CollidesWithDefault", map[string]string{"module": "msg"}, 1},
+		{"CollidesWithRemap", map[string]string{"module": "x", "msg_type": "x"}, 1},
+		{"Unknown", map[string]string{"unknown": "x"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := defaultFieldKeys()
+			for name, key := range tt.remap {
+				keys.set(name, key)
+			}
+			rejected := keys.normalize()
+			if len(rejected) != tt.rejected {
+				t.Errorf("Expected %d rejected remaps, got %v", tt.rejected, rejected)
+			}
+			seen := map[string]bool{}
+			for _, e := range keys.entries() {
+				if *e.ptr == "" || seen[*e.ptr] {
+					t.Errorf("Key %q is empty or duplicated after normalize", *e.ptr)
+				}
+				seen[*e.ptr] = true
+			}
+		})
+	}
+}
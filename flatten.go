@@ -0,0 +1,196 @@
+package goslogx
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// flatField logs a field with its nested objects and arrays flattened into
+// top-level keys joined by sep, see WithFlattenKeys. It is added with
+// zap.Inline, so the field itself contributes the first key segment.
+type flatField struct {
+	field zap.Field
+	sep   string
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (f flatField) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	f.field.AddTo(&flatObjectEncoder{enc: enc, sep: f.sep})
+	return nil
+}
+
+// flatObjectEncoder is a zapcore.ObjectEncoder that adds every value to enc
+// under its full path, such as data.user.email, instead of nesting objects.
+type flatObjectEncoder struct {
+	enc    zapcore.ObjectEncoder
+	prefix string // Path of the current object followed by sep, or empty at the top
+	sep    string
+}
+
+// flatArrayEncoder is a zapcore.ArrayEncoder that adds each element to enc
+// under its path followed by its index, such as data.users.0.
+type flatArrayEncoder struct {
+	enc    zapcore.ObjectEncoder
+	prefix string // Path of the array followed by sep
+	sep    string
+	i      int // Index of the next element
+}
+
+func (e *flatObjectEncoder) key(k string) string { return e.prefix + k }
+
+func (e *flatObjectEncoder) AddArray(k string, v zapcore.ArrayMarshaler) error {
+	return addFlatArray(e.enc, e.key(k), e.sep, v)
+}
+
+func (e *flatObjectEncoder) AddObject(k string, v zapcore.ObjectMarshaler) error {
+	return v.MarshalLogObject(&flatObjectEncoder{enc: e.enc, prefix: e.key(k) + e.sep, sep: e.sep})
+}
+
+func (e *flatObjectEncoder) AddReflected(k string, v any) error {
+	return addFlatReflected(e.enc, e.key(k), e.sep, v)
+}
+
+func (e *flatObjectEncoder) OpenNamespace(k string) { e.prefix = e.key(k) + e.sep }
+
+func (e *flatObjectEncoder) AddBinary(k string, v []byte)          { e.enc.AddBinary(e.key(k), v) }
+func (e *flatObjectEncoder) AddByteString(k string, v []byte)      { e.enc.AddByteString(e.key(k), v) }
+func (e *flatObjectEncoder) AddBool(k string, v bool)              { e.enc.AddBool(e.key(k), v) }
+func (e *flatObjectEncoder) AddComplex128(k string, v complex128)  { e.enc.AddComplex128(e.key(k), v) }
+func (e *flatObjectEncoder) AddComplex64(k string, v complex64)    { e.enc.AddComplex64(e.key(k), v) }
+func (e *flatObjectEncoder) AddDuration(k string, v time.Duration) { e.enc.AddDuration(e.key(k), v) }
+func (e *flatObjectEncoder) AddFloat64(k string, v float64)        { e.enc.AddFloat64(e.key(k), v) }
+func (e *flatObjectEncoder) AddFloat32(k string, v float32)        { e.enc.AddFloat32(e.key(k), v) }
+func (e *flatObjectEncoder) AddInt(k string, v int)                { e.enc.AddInt(e.key(k), v) }
+func (e *flatObjectEncoder) AddInt64(k string, v int64)            { e.enc.AddInt64(e.key(k), v) }
+func (e *flatObjectEncoder) AddInt32(k string, v int32)            { e.enc.AddInt32(e.key(k), v) }
+func (e *flatObjectEncoder) AddInt16(k string, v int16)            { e.enc.AddInt16(e.key(k), v) }
+func (e *flatObjectEncoder) AddInt8(k string, v int8)              { e.enc.AddInt8(e.key(k), v) }
+func (e *flatObjectEncoder) AddString(k string, v string)          { e.enc.AddString(e.key(k), v) }
+func (e *flatObjectEncoder) AddTime(k string, v time.Time)         { e.enc.AddTime(e.key(k), v) }
+func (e *flatObjectEncoder) AddUint(k string, v uint)              { e.enc.AddUint(e.key(k), v) }
+func (e *flatObjectEncoder) AddUint64(k string, v uint64)          { e.enc.AddUint64(e.key(k), v) }
+func (e *flatObjectEncoder) AddUint32(k string, v uint32)          { e.enc.AddUint32(e.key(k), v) }
+func (e *flatObjectEncoder) AddUint16(k string, v uint16)          { e.enc.AddUint16(e.key(k), v) }
+func (e *flatObjectEncoder) AddUint8(k string, v uint8)            { e.enc.AddUint8(e.key(k), v) }
+func (e *flatObjectEncoder) AddUintptr(k string, v uintptr)        { e.enc.AddUintptr(e.key(k), v) }
+
+// next returns the key of the next element and advances the index.
+func (e *flatArrayEncoder) next() string {
+	k := e.prefix + strconv.Itoa(e.i)
+	e.i++
+	return k
+}
+
+func (e *flatArrayEncoder) AppendArray(v zapcore.ArrayMarshaler) error {
+	return addFlatArray(e.enc, e.next(), e.sep, v)
+}
+
+func (e *flatArrayEncoder) AppendObject(v zapcore.ObjectMarshaler) error {
+	return v.MarshalLogObject(&flatObjectEncoder{enc: e.enc, prefix: e.next() + e.sep, sep: e.sep})
+}
+
+func (e *flatArrayEncoder) AppendReflected(v any) error {
+	return addFlatReflected(e.enc, e.next(), e.sep, v)
+}
+
+func (e *flatArrayEncoder) AppendBool(v bool)              { e.enc.AddBool(e.next(), v) }
+func (e *flatArrayEncoder) AppendByteString(v []byte)      { e.enc.AddByteString(e.next(), v) }
+func (e *flatArrayEncoder) AppendComplex128(v complex128)  { e.enc.AddComplex128(e.next(), v) }
+func (e *flatArrayEncoder) AppendComplex64(v complex64)    { e.enc.AddComplex64(e.next(), v) }
+func (e *flatArrayEncoder) AppendDuration(v time.Duration) { e.enc.AddDuration(e.next(), v) }
+func (e *flatArrayEncoder) AppendFloat64(v float64)        { e.enc.AddFloat64(e.next(), v) }
+func (e *flatArrayEncoder) AppendFloat32(v float32)        { e.enc.AddFloat32(e.next(), v) }
+func (e *flatArrayEncoder) AppendInt(v int)                { e.enc.AddInt(e.next(), v) }
+func (e *flatArrayEncoder) AppendInt64(v int64)            { e.enc.AddInt64(e.next(), v) }
+func (e *flatArrayEncoder) AppendInt32(v int32)            { e.enc.AddInt32(e.next(), v) }
+func (e *flatArrayEncoder) AppendInt16(v int16)            { e.enc.AddInt16(e.next(), v) }
+func (e *flatArrayEncoder) AppendInt8(v int8)              { e.enc.AddInt8(e.next(), v) }
+func (e *flatArrayEncoder) AppendString(v string)          { e.enc.AddString(e.next(), v) }
+func (e *flatArrayEncoder) AppendTime(v time.Time)         { e.enc.AddTime(e.next(), v) }
+func (e *flatArrayEncoder) AppendUint(v uint)              { e.enc.AddUint(e.next(), v) }
+func (e *flatArrayEncoder) AppendUint64(v uint64)          { e.enc.AddUint64(e.next(), v) }
+func (e *flatArrayEncoder) AppendUint32(v uint32)          { e.enc.AddUint32(e.next(), v) }
+func (e *flatArrayEncoder) AppendUint16(v uint16)          { e.enc.AddUint16(e.next(), v) }
+func (e *flatArrayEncoder) AppendUint8(v uint8)            { e.enc.AddUint8(e.next(), v) }
+func (e *flatArrayEncoder) AppendUintptr(v uintptr)        { e.enc.AddUintptr(e.next(), v) }
+
+// addFlatArray adds the elements of v to enc under key followed by their
+// index. An empty array is kept under key, so it doesn't vanish.
+func addFlatArray(enc zapcore.ObjectEncoder, key, sep string, v zapcore.ArrayMarshaler) error {
+	arr := &flatArrayEncoder{enc: enc, prefix: key + sep, sep: sep}
+	if err := v.MarshalLogArray(arr); err != nil {
+		return err
+	}
+	if arr.i == 0 {
+		return enc.AddReflected(key, []any{})
+	}
+	return nil
+}
+
+// addFlatReflected adds v to enc under key, flattening structs, maps, and
+// slices by their JSON encoding. Empty objects and arrays are kept under key,
+// so they don't vanish from the output.
+func addFlatReflected(enc zapcore.ObjectEncoder, key, sep string, v any) error {
+	switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+	default:
+		return enc.AddReflected(key, v)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return enc.AddReflected(key, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return enc.AddReflected(key, v)
+	}
+	return addFlatJSON(enc, key, sep, decoded)
+}
+
+// addFlatJSON adds the decoded JSON value v to enc under key, flattening
+// objects and arrays.
+func addFlatJSON(enc zapcore.ObjectEncoder, key, sep string, v any) error {
+	switch val := v.(type) {
+	case map[string]any:
+		if len(val) == 0 {
+			return enc.AddReflected(key, val)
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := addFlatJSON(enc, key+sep+k, sep, val[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		if len(val) == 0 {
+			return enc.AddReflected(key, val)
+		}
+		for i, elem := range val {
+			if err := addFlatJSON(enc, key+sep+strconv.Itoa(i), sep, elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	case string:
+		enc.AddString(key, val)
+		return nil
+	case bool:
+		enc.AddBool(key, val)
+		return nil
+	}
+	return enc.AddReflected(key, v)
+}
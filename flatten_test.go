@@ -0,0 +1,72 @@
+package goslogx
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlattenKeys(t *testing.T) {
+	type user struct {
+		ID    int    `json:"id"`
+		Email string `json:"email" log:"masked:email"`
+	}
+	type team struct {
+		Name  string         `json:"name"`
+		Users []user         `json:"users"`
+		Tags  []string       `json:"tags"`
+		Meta  map[string]any `json:"meta"`
+		Empty []string       `json:"empty"`
+	}
+	data := team{
+		Name:  "core",
+		Users: []user{{ID: 1, Email: "jane.doe@example.com"}, {ID: 2, Email: "john.doe@example.com"}},
+		Tags:  []string{"a", "b"},
+		Meta:  map[string]any{"region": "id", "limits": map[string]any{"max": 10}},
+		Empty: []string{},
+	}
+
+	buf := &bytes.Buffer{}
+	setupLog(WithOutput(buf)).Info("trace-1", "svc", MESSAGE_TYPE_EVENT, "nested", data)
+	setupLog(WithOutput(buf), WithFlattenKeys(".")).Info("trace-2", "svc", MESSAGE_TYPE_EVENT, "flat", data)
+	setupLog(WithOutput(buf), WithFlattenKeys("_")).Info("trace-3", "svc", MESSAGE_TYPE_EVENT, "flat", map[string]any{
+		"user": map[string]any{"id": 3},
+	})
+	entries := decodeEntries(t, buf.Bytes())
+
+	nested := entries[0]["data"].(map[string]any)
+	users := nested["users"].([]any)
+	if got := users[0].(map[string]any)["email"]; got != "ja****@example.com" {
+		t.Errorf("Expected nested email to be masked, got %v", got)
+	}
+
+	flat := map[string]any{}
+	for k, v := range entries[1] {
+		if strings.HasPrefix(k, "data.") {
+			flat[k] = v
+		}
+	}
+	want := map[string]any{
+		"data.name":            "core",
+		"data.users.0.id":      float64(1),
+		"data.users.0.email":   "ja****@example.com",
+		"data.users.1.id":      float64(2),
+		"data.users.1.email":   "jo****@example.com",
+		"data.tags.0":          "a",
+		"data.tags.1":          "b",
+		"data.meta.region":     "id",
+		"data.meta.limits.max": float64(10),
+		"data.empty":           []any{},
+	}
+	if !reflect.DeepEqual(flat, want) {
+		t.Errorf("Expected flattened data\n%v\ngot\n%v", want, flat)
+	}
+	if _, ok := entries[1]["data"]; ok {
+		t.Errorf("Expected no nested data object, got %v", entries[1]["data"])
+	}
+
+	if got := entries[2]["data_user_id"]; got != float64(3) {
+		t.Errorf("Expected data_user_id 3 with the _ separator, got %v in %v", got, entries[2])
+	}
+}
@@ -2,7 +2,10 @@ package goslogx
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"reflect"
@@ -479,6 +482,26 @@ func TestMaskingExecution(t *testing.T) {
 		if m["Partial"] != "se****ve" {
 			t.Errorf("Expected Partial mask, got %v", m["Partial"])
 		}
+		// json:"-" fields never appear, under either name
+		if _, ok := m["Ignored"]; ok {
+			t.Errorf("Expected json:\"-\" field to be omitted, got %v", m["Ignored"])
+		}
+		if _, ok := m["-"]; ok {
+			t.Errorf("Expected no field named \"-\", got %v", m["-"])
+		}
+	})
+
+	t.Run("MarshalLogObjectDashName", func(t *testing.T) {
+		type Dash struct {
+			Dash string `json:"-,"`
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		if err := (maskedObject{v: Dash{Dash: "value"}}).MarshalLogObject(enc); err != nil {
+			t.Fatalf("MarshalLogObject failed: %v", err)
+		}
+		if enc.Fields["-"] != "value" {
+			t.Errorf("Expected json:\"-,\" field to be named \"-\", got %v", enc.Fields)
+		}
 	})
 
 	t.Run("MarshalLogObjectExtra", func(t *testing.T) {
@@ -616,6 +639,25 @@ func TestInstanceLogging(t *testing.T) {
 		}
 	})
 
+	t.Run("WarningErr", func(t *testing.T) {
+		buf.Reset()
+		logger.WarningErr(traceID, "mod", "retrying", errors.New("recoverable error"))
+
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to unmarshal log entry: %v", err)
+		}
+		if entry[KeySeverity] != severityWarning {
+			t.Errorf("Expected severity %s, got %v", severityWarning, entry[KeySeverity])
+		}
+		if entry[KeyError] != "recoverable error" {
+			t.Errorf("Expected error field, got %v", entry[KeyError])
+		}
+		if stack, _ := entry[KeyStackTrace].(string); !strings.HasPrefix(stack, "[") {
+			t.Errorf("Expected formatted stack trace, got %q", stack)
+		}
+	})
+
 	t.Run("Debug", func(t *testing.T) {
 		buf.Reset()
 		// Re-setup with debug level to cover the branch
@@ -659,16 +701,36 @@ func TestDetectCallerSkipEdgeCases(t *testing.T) {
 		var callDeep func(int) int
 		callDeep = func(n int) int {
 			if n <= 0 {
-				return detectCallerSkip()
+				return detectCallerSkip(15)
 			}
 			return callDeep(n - 1)
 		}
-		// Max depth is 15 in caller.go
+		// Every frame searched belongs to this package
 		skip := callDeep(20)
 		if skip != 2 { // Fallback value
 			t.Errorf("Expected fallback skip 2 for deep stack, got %d", skip)
 		}
 	})
+
+	t.Run("ConfigurableDepth", func(t *testing.T) {
+		var callDeep func(int) int
+		callDeep = func(n int) int {
+			if n <= 0 {
+				return detectCallerSkip(64)
+			}
+			return callDeep(n - 1)
+		}
+		// callDeep frames belong to this package; testing.tRunner does not
+		if skip := callDeep(20); skip != 22 {
+			t.Errorf("Expected skip 22 past 21 callDeep frames and the subtest, got %d", skip)
+		}
+	})
+
+	t.Run("PackagePrefix", func(t *testing.T) {
+		if pkgPrefix != "github.com/muhammadluth/goslogx." {
+			t.Errorf("Unexpected package prefix %q", pkgPrefix)
+		}
+	})
 }
 
 // TestDataFieldUnsupported covers fallback to zap.Any
@@ -785,3 +847,551 @@ func TestMaskedObjectEdgeCases(t *testing.T) {
 		_ = obj.MarshalLogObject(enc)
 	})
 }
+
+// TestLoggerWith covers child loggers with bound fields
+func TestLoggerWith(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := setupLog(WithOutput(buf), WithServiceName("test-with"))
+
+	type Auth struct {
+		Password string `json:"password" log:"masked:full"`
+	}
+
+	child := logger.With(map[string]any{
+		"tenant": "acme",
+		"auth":   Auth{Password: "supersecret"},
+	})
+
+	t.Run("BoundFields", func(t *testing.T) {
+		buf.Reset()
+		child.Info("trace-123", "mod", MESSSAGE_TYPE_EVENT, "info msg", nil)
+		out := buf.String()
+		if !strings.Contains(out, `"tenant":"acme"`) {
+			t.Errorf("Expected bound tenant field, got %s", out)
+		}
+		if strings.Contains(out, "supersecret") {
+			t.Errorf("Bound field leaked sensitive value: %s", out)
+		}
+		if !strings.Contains(out, `"password":"****"`) {
+			t.Errorf("Expected bound field to be masked, got %s", out)
+		}
+	})
+
+	t.Run("AllLevels", func(t *testing.T) {
+		buf.Reset()
+		child.Warning("trace-123", "mod", "warn msg", nil)
+		child.Error("trace-123", "mod", errors.New("child error"))
+		if n := strings.Count(buf.String(), `"tenant":"acme"`); n != 2 {
+			t.Errorf("Expected bound field on 2 entries, got %d: %s", n, buf.String())
+		}
+	})
+
+	t.Run("ParentUnchanged", func(t *testing.T) {
+		buf.Reset()
+		logger.Info("trace-123", "mod", MESSSAGE_TYPE_EVENT, "info msg", nil)
+		if strings.Contains(buf.String(), "tenant") {
+			t.Errorf("Parent logger should not carry child fields, got %s", buf.String())
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		if logger.With(nil) != logger {
+			t.Error("Expected With(nil) to return the same logger")
+		}
+	})
+}
+
+// TestMultipleOutputs covers fan-out to several writers
+func TestMultipleOutputs(t *testing.T) {
+	a, b := &bytes.Buffer{}, &bytes.Buffer{}
+	logger := setupLog(WithOutputs(a, b), WithServiceName("test-outputs"))
+	logger.Info("trace-123", "mod", MESSSAGE_TYPE_EVENT, "fan-out msg", nil)
+
+	for name, buf := range map[string]*bytes.Buffer{"first": a, "second": b} {
+		if !strings.Contains(buf.String(), "fan-out msg") {
+			t.Errorf("Expected %s writer to receive the entry, got %q", name, buf.String())
+		}
+	}
+
+	t.Run("StackTracePerDestination", func(t *testing.T) {
+		a, b := &bytes.Buffer{}, &bytes.Buffer{}
+		ws := newWriteSyncer([]io.Writer{a, b}, KeyStackTrace, StackTraceInline)
+		if _, err := ws.Write([]byte(`{"stack_trace":"main.main\n\tmain.go:1"}`)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		want := `{"stack_trace":"[main.main | main.go:1]"}`
+		if a.String() != want || b.String() != want {
+			t.Errorf("Expected formatted stack trace on both writers, got %q and %q", a.String(), b.String())
+		}
+	})
+}
+
+// TestErrorOutput covers level-based routing between standard and error writers
+func TestErrorOutput(t *testing.T) {
+	stdBuf, errBuf := &bytes.Buffer{}, &bytes.Buffer{}
+	logger := setupLog(WithOutput(stdBuf), WithErrorOutput(errBuf), WithDebug(true))
+
+	logger.Info("trace-123", "mod", MESSSAGE_TYPE_EVENT, "info msg", nil)
+	logger.Debug("trace-123", "mod", MESSSAGE_TYPE_EVENT, "debug msg", nil)
+	logger.Warning("trace-123", "mod", "warn msg", nil)
+	logger.Error("trace-123", "mod", errors.New("routed error"))
+
+	for _, msg := range []string{"info msg", "debug msg"} {
+		if !strings.Contains(stdBuf.String(), msg) {
+			t.Errorf("Expected %q on standard writer, got %s", msg, stdBuf.String())
+		}
+		if strings.Contains(errBuf.String(), msg) {
+			t.Errorf("Did not expect %q on error writer, got %s", msg, errBuf.String())
+		}
+	}
+	for _, msg := range []string{"warn msg", "routed error"} {
+		if !strings.Contains(errBuf.String(), msg) {
+			t.Errorf("Expected %q on error writer, got %s", msg, errBuf.String())
+		}
+		if strings.Contains(stdBuf.String(), msg) {
+			t.Errorf("Did not expect %q on standard writer, got %s", msg, stdBuf.String())
+		}
+	}
+
+	t.Run("RespectsLevel", func(t *testing.T) {
+		stdBuf, errBuf := &bytes.Buffer{}, &bytes.Buffer{}
+		logger := setupLog(WithOutput(stdBuf), WithErrorOutput(errBuf))
+		logger.Debug("trace-123", "mod", MESSSAGE_TYPE_EVENT, "debug msg", nil)
+		if stdBuf.Len() != 0 || errBuf.Len() != 0 {
+			t.Errorf("Expected Debug to be dropped at Info level, got %q / %q", stdBuf.String(), errBuf.String())
+		}
+	})
+}
+
+// TestStackTraceFormat covers inline and multiline stack trace output
+func TestStackTraceFormat(t *testing.T) {
+	const entry = `{"level":"fatal","stack_trace":"goroutine 1 [running]:\nmain.main()\n\t/app/main.go:10"}`
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{StackTraceInline, `{"level":"fatal","stack_trace":"[goroutine 1 [running]: | main.main() | /app/main.go:10]"}`},
+		{StackTraceMultiline, entry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			ws := newWriteSyncer([]io.Writer{buf}, KeyStackTrace, tt.format)
+			if _, err := ws.Write([]byte(entry)); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, buf.String())
+			}
+		})
+	}
+
+	t.Run("MultilineKeepsFrames", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf), WithStackTraceFormat(StackTraceMultiline))
+		logger.logger.Error("boom", zap.StackSkip(KeyStackTrace, 0))
+
+		var decoded map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Failed to unmarshal log entry: %v", err)
+		}
+		stack, _ := decoded[KeyStackTrace].(string)
+		if !strings.Contains(stack, "\n\t") || strings.HasPrefix(stack, "[") {
+			t.Errorf("Expected newline-separated frames, got %q", stack)
+		}
+	})
+
+	t.Run("Console", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf), WithConsoleEncoder(true), WithStackTraceFormat(StackTraceMultiline))
+		logger.logger.Error("boom", zap.String(KeyStackTrace, "main.main()\n\t/app/main.go:10"))
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if len(lines) != 3 || lines[1] != "main.main()" || lines[2] != "\t/app/main.go:10" {
+			t.Errorf("Expected stack trace on its own lines, got %q", buf.String())
+		}
+		if strings.Contains(lines[0], KeyStackTrace+"=") {
+			t.Errorf("Expected no inline stack trace pair, got %q", lines[0])
+		}
+	})
+
+	t.Run("UnknownFormatIgnored", func(t *testing.T) {
+		cfg := defaultConfig()
+		WithStackTraceFormat("fancy")(cfg)
+		if cfg.StackTraceFormat != StackTraceInline {
+			t.Errorf("Expected unknown format to keep %q, got %q", StackTraceInline, cfg.StackTraceFormat)
+		}
+	})
+}
+
+func TestSeverityMapping(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := setupLog(WithOutput(buf), WithSeverityMapping(map[zapcore.Level]string{
+		zapcore.ErrorLevel: "err",
+	}))
+
+	logger.Error("trace-1", "test", errors.New("boom"))
+	logger.Warning("trace-2", "test", "careful", nil)
+
+	dec := json.NewDecoder(buf)
+	for _, want := range []string{"err", severityWarning} {
+		var entry map[string]any
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatalf("Failed to decode log entry: %v", err)
+		}
+		if entry[KeySeverity] != want {
+			t.Errorf("Expected severity %q, got %v", want, entry[KeySeverity])
+		}
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		for lvl, want := range map[zapcore.Level]string{
+			zapcore.DPanicLevel: severityDefault,
+			zapcore.PanicLevel:  severityDefault,
+			zapcore.FatalLevel:  severityCritical,
+		} {
+			if got := severityFor(lvl, nil); got != want {
+				t.Errorf("Expected %s to map to %q, got %q", lvl, want, got)
+			}
+		}
+		if got := severityFor(zapcore.PanicLevel, map[zapcore.Level]string{zapcore.PanicLevel: "alert"}); got != "alert" {
+			t.Errorf("Expected overridden panic severity, got %q", got)
+		}
+	})
+}
+
+func TestDefaultModule(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := setupLog(WithOutput(buf), WithDebug(true), WithModule("billing"))
+
+	logger.Info("trace-1", "", MESSSAGE_TYPE_EVENT, "info", nil)
+	logger.Debug("trace-2", "", MESSSAGE_TYPE_EVENT, "debug", nil)
+	logger.Warning("trace-3", "", "warning", nil)
+	logger.WarningErr("trace-4", "", "warning", errors.New("boom"))
+	logger.Error("trace-5", "", errors.New("boom"))
+	logger.Info("trace-6", "invoices", MESSSAGE_TYPE_EVENT, "override", nil)
+
+	entries := decodeEntries(t, buf.Bytes())
+	for i, want := range []string{"billing", "billing", "billing", "billing", "billing", "invoices"} {
+		if got := entries[i][KeyModule]; got != want {
+			t.Errorf("Entry %d: expected module %q, got %v", i, want, got)
+		}
+	}
+}
+
+func TestHostInfo(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skipf("Hostname unavailable: %v", err)
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf), WithHostInfo(true))
+		logger.Info("trace-1", "mod", MESSSAGE_TYPE_EVENT, "info", nil)
+		logger.With(map[string]any{"k": "v"}).Error("trace-2", "mod", errors.New("boom"))
+
+		for i, entry := range decodeEntries(t, buf.Bytes()) {
+			if entry[KeyHostname] != hostname || entry[KeyPID] != float64(os.Getpid()) {
+				t.Errorf("Entry %d: expected hostname %q and pid %d, got %v", i, hostname, os.Getpid(), entry)
+			}
+		}
+	})
+
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		setupLog(WithOutput(buf)).Info("trace-1", "mod", MESSSAGE_TYPE_EVENT, "info", nil)
+		entry := decodeEntries(t, buf.Bytes())[0]
+		if _, ok := entry[KeyHostname]; ok {
+			t.Errorf("Expected no hostname by default, got %v", entry)
+		}
+		if _, ok := entry[KeyPID]; ok {
+			t.Errorf("Expected no pid by default, got %v", entry)
+		}
+	})
+
+	t.Run("RemappedKeys", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		setupLog(WithOutput(buf), WithHostInfo(true), WithFieldKeys(map[string]string{KeyHostname: "host"})).
+			Info("trace-1", "mod", MESSSAGE_TYPE_EVENT, "info", nil)
+		if entry := decodeEntries(t, buf.Bytes())[0]; entry["host"] != hostname {
+			t.Errorf("Expected remapped hostname key, got %v", entry)
+		}
+	})
+}
+
+// frozenClock is a zapcore.Clock that always returns the same time.
+type frozenClock struct{ t time.Time }
+
+func (c frozenClock) Now() time.Time                         { return c.t }
+func (c frozenClock) NewTicker(d time.Duration) *time.Ticker { return time.NewTicker(d) }
+
+func TestClock(t *testing.T) {
+	clock := frozenClock{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	buf := &bytes.Buffer{}
+	logger := setupLog(WithOutput(buf), WithClock(clock), WithErrorDedup(time.Hour))
+
+	logger.Info("trace-1", "mod", MESSSAGE_TYPE_EVENT, "info", nil)
+	logger.Error("trace-2", "mod", errors.New("boom"))
+	logger.Error("trace-3", "mod", errors.New("boom"))
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	entries := decodeEntries(t, buf.Bytes())
+	if len(entries) != 3 {
+		t.Fatalf("Expected 2 entries and a dedup summary, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		if entry[KeyTime] != "2024-01-02T03:04:05Z" {
+			t.Errorf("Entry %d: expected the frozen time, got %v", i, entry[KeyTime])
+		}
+	}
+}
+
+func TestTimezone(t *testing.T) {
+	clock := frozenClock{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	loc := time.FixedZone("WIB", 7*60*60)
+	for _, console := range []bool{false, true} {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf), WithClock(clock), WithTimezone(loc), WithConsoleEncoder(console))
+		logger.Info("trace-1", "mod", MESSSAGE_TYPE_EVENT, "info", nil)
+
+		if !strings.Contains(buf.String(), "2024-01-02T10:04:05+07:00") {
+			t.Errorf("Console %v: expected the time at +07:00, got %s", console, buf.String())
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	setupLog(WithOutput(buf), WithClock(clock), WithTimezone(nil)).Info("trace-2", "mod", MESSSAGE_TYPE_EVENT, "info", nil)
+	if ts := decodeEntries(t, buf.Bytes())[0][KeyTime]; ts != "2024-01-02T03:04:05Z" {
+		t.Errorf("Expected a nil location to keep the clock's, got %v", ts)
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	required := WithValidateFields([]string{KeyTraceID, KeyModule})
+
+	t.Run("Warning", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf), required)
+
+		logger.Info("trace-1", "users", MESSSAGE_TYPE_EVENT, "complete", nil)
+		logger.Info("", "users", MESSSAGE_TYPE_EVENT, "no trace", nil)
+
+		entries := decodeEntries(t, buf.Bytes())
+		if len(entries) != 3 {
+			t.Fatalf("Expected 2 entries and a warning, got %d: %s", len(entries), buf.String())
+		}
+		if entries[1][KeyMessage] != "no trace" {
+			t.Errorf("Expected the invalid entry to still be written, got %v", entries[1])
+		}
+		warning := entries[2]
+		if warning[KeyLevel] != "warn" || warning[KeySeverity] != "WARNING" || warning["entry_msg"] != "no trace" {
+			t.Errorf("Unexpected warning entry: %v", warning)
+		}
+		if missing, _ := warning["missing_fields"].([]any); len(missing) != 1 || missing[0] != KeyTraceID {
+			t.Errorf("Expected trace_id to be reported missing, got %v", warning["missing_fields"])
+		}
+	})
+
+	t.Run("BoundWithWith", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf), WithValidateFields([]string{"tenant"}))
+
+		logger.With(map[string]any{"tenant": "acme"}).Info("trace-1", "users", MESSSAGE_TYPE_EVENT, "bound", nil)
+		logger.Info("trace-2", "users", MESSSAGE_TYPE_EVENT, "parent", nil)
+
+		entries := decodeEntries(t, buf.Bytes())
+		if len(entries) != 3 || entries[2]["entry_msg"] != "parent" {
+			t.Errorf("Expected only the parent's entry to be reported, got %s", buf.String())
+		}
+	})
+
+	t.Run("ErrorOutput", func(t *testing.T) {
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		logger := setupLog(WithOutput(out), WithErrorOutput(errOut), required)
+
+		logger.Info("", "users", MESSSAGE_TYPE_EVENT, "no trace", nil)
+
+		entries := decodeEntries(t, out.Bytes())
+		if len(entries) != 1 || entries[0][KeyMessage] != "no trace" {
+			t.Errorf("Expected only the info entry in the output, got %s", out.String())
+		}
+		entries = decodeEntries(t, errOut.Bytes())
+		if len(entries) != 1 || entries[0]["entry_msg"] != "no trace" {
+			t.Errorf("Expected only the warning in the error output, got %s", errOut.String())
+		}
+	})
+
+	t.Run("LevelFiltered", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		errorLevel := func(c *Config) { c.Level = zapcore.ErrorLevel }
+		logger := setupLog(WithOutput(buf), required, errorLevel)
+
+		logger.Error("", "users", errors.New("boom"))
+
+		entries := decodeEntries(t, buf.Bytes())
+		if len(entries) != 1 || entries[0][KeyLevel] != "error" {
+			t.Errorf("Expected the warning to be filtered out by the level, got %s", buf.String())
+		}
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		logger := setupLog(WithOutput(io.Discard), required, WithValidationPanic(true))
+		defer func() {
+			if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), KeyTraceID) {
+				t.Errorf("Expected a panic naming trace_id, got %v", r)
+			}
+		}()
+		logger.Error("", "users", errors.New("boom"))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		setupLog(WithOutput(buf)).Info("", "", MESSSAGE_TYPE_EVENT, "unchecked", nil)
+		if entries := decodeEntries(t, buf.Bytes()); len(entries) != 1 {
+			t.Errorf("Expected no validation by default, got %s", buf.String())
+		}
+	})
+}
+
+func TestStrictMsgType(t *testing.T) {
+	for _, mt := range []MsgType{MESSSAGE_TYPE_IN, MESSSAGE_TYPE_OUT, MESSSAGE_TYPE_REQUEST,
+		MESSSAGE_TYPE_RESPONSE, MESSSAGE_TYPE_EVENT, MESSAGE_TYPE_UNKNOWN} {
+		if !IsValidMsgType(mt) {
+			t.Errorf("Expected %q to be valid", mt)
+		}
+	}
+	for _, mt := range []MsgType{"", "REQEUST", "event"} {
+		if IsValidMsgType(mt) {
+			t.Errorf("Expected %q to be invalid", mt)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	logger := setupLog(WithOutput(buf), WithDebug(true), WithStrictMsgType(true))
+	logger.Info("trace-1", "api", "REQEUST", "first", nil)
+	logger.With(map[string]any{"k": "v"}).Debug("trace-2", "api", "REQEUST", "second", nil)
+	logger.Info("trace-3", "api", MESSSAGE_TYPE_REQUEST, "valid", nil)
+
+	entries := decodeEntries(t, buf.Bytes())
+	if len(entries) != 4 {
+		t.Fatalf("Expected one warning plus 3 entries, got %d", len(entries))
+	}
+	if entries[0][KeySeverity] != severityWarning || !strings.Contains(entries[0]["msg"].(string), `"REQEUST"`) {
+		t.Errorf("Expected a warning naming the invalid type first, got %v", entries[0])
+	}
+	for i, want := range []MsgType{MESSAGE_TYPE_UNKNOWN, MESSAGE_TYPE_UNKNOWN, MESSSAGE_TYPE_REQUEST} {
+		if got := entries[i+1][KeyMsgType]; got != string(want) {
+			t.Errorf("Entry %d: expected msg_type %q, got %v", i+1, want, got)
+		}
+	}
+
+	t.Run("Lenient", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		setupLog(WithOutput(buf)).Info("trace-4", "api", "REQEUST", "as is", nil)
+		if entries := decodeEntries(t, buf.Bytes()); len(entries) != 1 || entries[0][KeyMsgType] != "REQEUST" {
+			t.Errorf("Expected invalid types to be logged as is by default, got %v", entries)
+		}
+	})
+}
+
+func TestSampling(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := setupLog(WithOutput(buf), WithSampling(10, 100))
+
+	for i := 0; i < 1000; i++ {
+		logger.Info("trace-1", "loop", MESSSAGE_TYPE_EVENT, "hot path", nil)
+	}
+	// 10 initial entries plus 1 in 100 of the rest, within one sampling tick
+	if lines := strings.Count(buf.String(), "\n"); lines == 0 || lines > 50 {
+		t.Errorf("Expected about 19 sampled lines, got %d", lines)
+	}
+
+	t.Run("PerMessage", func(t *testing.T) {
+		buf.Reset()
+		logger.Info("trace-2", "loop", MESSSAGE_TYPE_EVENT, "another message", nil)
+		if !strings.Contains(buf.String(), "another message") {
+			t.Errorf("Expected a different message to be sampled separately, got %q", buf.String())
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf))
+		for i := 0; i < 1000; i++ {
+			logger.Info("trace-3", "loop", MESSSAGE_TYPE_EVENT, "hot path", nil)
+		}
+		if lines := strings.Count(buf.String(), "\n"); lines != 1000 {
+			t.Errorf("Expected all 1000 lines without sampling, got %d", lines)
+		}
+	})
+}
+
+func TestAsync(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := setupLog(WithOutput(buf), WithAsync(0, time.Hour))
+
+	logger.Info("trace-1", "test", MESSSAGE_TYPE_EVENT, "buffered", nil)
+	logger.logger.Error("with stack", zap.String(KeyStackTrace, "main.main()\n\t/app/main.go:10"))
+	logger.logger.Error("with stack", zap.String(KeyStackTrace, "main.run()\n\t/app/run.go:20"))
+	if buf.Len() != 0 {
+		t.Fatalf("Expected entries to be buffered, got %s", buf.String())
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	entries := decodeEntries(t, buf.Bytes())
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries after Close, got %d", len(entries))
+	}
+	// Stack traces are formatted even when several entries are flushed at once
+	for i, want := range []string{"[main.main() | /app/main.go:10]", "[main.run() | /app/run.go:20]"} {
+		if entries[i+1][KeyStackTrace] != want {
+			t.Errorf("Expected stack trace %q, got %v", want, entries[i+1][KeyStackTrace])
+		}
+	}
+
+	t.Run("FatalFlushes", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf), WithAsync(0, time.Hour))
+		defer logger.Close()
+		// A recovered panic is logged at fatal level without exiting
+		func() {
+			defer logger.RecoverAndLog("trace-2", "test")()
+			panic("boom")
+		}()
+		if !strings.Contains(buf.String(), "panic recovered") {
+			t.Errorf("Expected fatal entries to be written immediately, got %q", buf.String())
+		}
+	})
+}
+
+func TestTruncateStack(t *testing.T) {
+	stack := "main.a\n\ta.go:1\nmain.b\n\tb.go:2\nmain.c\n\tc.go:3"
+	goroutine := "goroutine 1 [running]:\nmain.a()\n\ta.go:1 +0x1d\nmain.b()\n\tb.go:2 +0x2e\ncreated by main.c in goroutine 1\n\tc.go:3 +0x3f\n"
+	tests := []struct {
+		name  string
+		stack string
+		depth int
+		want  string
+	}{
+		{"Unlimited", stack, 0, stack},
+		{"Negative", stack, -1, stack},
+		{"Truncated", stack, 1, "main.a\n\ta.go:1\n... (2 more)"},
+		{"Exact", stack, 3, stack},
+		{"Deeper", stack, 10, stack},
+		{"Goroutine", goroutine, 1, "goroutine 1 [running]:\nmain.a()\n\ta.go:1 +0x1d\n... (2 more)"},
+		{"Empty", "", 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateStack(tt.stack, tt.depth); got != tt.want {
+				t.Errorf("truncateStack(%q, %d) = %q, want %q", tt.stack, tt.depth, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,18 +4,21 @@
 //
 // Basic Usage:
 //
-//	logger, err := goslogx.New(goslogx.WithServiceName("my-service"))
-//	if err != nil {
-//		log.Fatal(err)
-//	}
-//	logger.Info("trace-001", "handler", goslogx.MessageTypeEvent, "request received", nil)
+//	logger := goslogx.New(goslogx.WithServiceName("my-service"))
+//	logger.Info("trace-001", "handler", goslogx.MESSAGE_TYPE_EVENT, "request received", nil)
 package goslogx
 
 import (
 	"bytes"
+	"errors"
 	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -26,6 +29,8 @@ var (
 	globalLog atomic.Pointer[Logger]
 	// once ensures New() only configures the global logger once.
 	once sync.Once
+	// reconfigureMu serializes Reconfigure calls.
+	reconfigureMu sync.Mutex
 )
 
 func init() {
@@ -38,15 +43,33 @@ func init() {
 //
 // Example:
 //
-//	logger, err := goslogx.New(
+//	logger := goslogx.New(
 //		goslogx.WithServiceName("my-service"),
-//		goslogx.WithLevel(zapcore.DebugLevel),
+//		goslogx.WithDebug(true),
 //	)
 type Logger struct {
-	logger *zap.Logger
-	config *Config
+	logger  *zap.Logger
+	config  *Config
+	closers []func() error // Stop background flushing, see WithAsync, then release outputs, see WithCloser
+
+	// invalidMsgTypes holds the unrecognized message types already warned
+	// about, shared with child loggers, see WithStrictMsgType
+	invalidMsgTypes *sync.Map
+
+	// inContext is set on the logger a Ctx function logs with, so that the
+	// context it was given isn't overridden by BaseContext
+	inContext bool
 }
 
+// Supported values for Config.StackTraceFormat.
+const (
+	// StackTraceInline collapses stack traces into a single bracketed,
+	// pipe-separated line (default).
+	StackTraceInline = "inline"
+	// StackTraceMultiline keeps the original newline-separated frames.
+	StackTraceMultiline = "multiline"
+)
+
 // formatStackTraceBytes formats a stack trace string into a compact, bracketed format.
 // It replaces newlines and tabs with pipe separators for improved readability.
 // This function operates byte-by-byte to ensure zero allocations.
@@ -71,12 +94,52 @@ func formatStackTraceBytes(dst *bytes.Buffer, stackStr string) {
 	dst.WriteByte(']')
 }
 
+// truncateStack keeps the first depth frames of stack, each a function line
+// followed by its indented file:line line, and replaces the rest with a
+// "... (M more)" line. A "goroutine N [running]:" header is kept without
+// counting as a frame. A depth of 0 or less returns stack unchanged.
+//
+// Example: truncateStack("main.a\n\ta.go:1\nmain.b\n\tb.go:2", 1) -> "main.a\n\ta.go:1\n... (1 more)"
+func truncateStack(stack string, depth int) string {
+	if depth <= 0 {
+		return stack
+	}
+	frames, cut := 0, -1
+	for start := 0; start < len(stack); {
+		end := strings.IndexByte(stack[start:], '\n') + 1
+		if end == 0 {
+			end = len(stack) - start
+		}
+		line := stack[start : start+end]
+		if line[0] != '\t' && line[0] != '\n' && !strings.HasPrefix(line, "goroutine ") {
+			if frames == depth {
+				cut = start
+			}
+			frames++
+		}
+		start += end
+	}
+	if cut < 0 {
+		return stack
+	}
+	return stack[:cut] + "... (" + strconv.Itoa(frames-depth) + " more)"
+}
+
 // stackTraceFormattingWriter wraps io.Writer to format stack traces in JSON output.
 // It implements zapcore.WriteSyncer and performs byte-level scanning to detect
 // and format stack_trace fields without full JSON parsing (zero-allocation design).
 type stackTraceFormattingWriter struct {
 	io.Writer               // Underlying writer for formatted output
 	buf       *bytes.Buffer // Pre-allocated 1KB buffer reused across writes to minimize allocations
+	key       []byte        // "<key>":" pattern to look for; nil means defaultStackTraceKey
+}
+
+// defaultStackTraceKey is the pattern used when no custom stack trace key is configured.
+var defaultStackTraceKey = []byte("\"stack_trace\":\"")
+
+// stackTracePattern builds the "<key>":" pattern for a stack trace key.
+func stackTracePattern(key string) []byte {
+	return []byte("\"" + key + "\":\"")
 }
 
 // Write implements io.Writer and formats stack traces in JSON output.
@@ -88,50 +151,63 @@ type stackTraceFormattingWriter struct {
 // 2. Scans forward to find the closing quote, respecting escaped characters
 // 3. Decodes the JSON-escaped stack trace string
 // 4. Formats the stack trace with pipe separators and brackets
-// 5. Writes the modified JSON back to the underlying writer
+// 5. Repeats from 1 for the rest of p, which may hold several entries
+// 6. Writes the modified JSON back to the underlying writer
 func (w *stackTraceFormattingWriter) Write(p []byte) (n int, err error) {
+	stackTraceKey := w.key
+	if stackTraceKey == nil {
+		stackTraceKey = defaultStackTraceKey
+	}
+
 	// Fast path: only process if there's a stack_trace field
 	// This avoids unnecessary processing for non-error logs
-	if !bytes.Contains(p, []byte("\"stack_trace\":\"")) {
+	idx := bytes.Index(p, stackTraceKey)
+	if idx < 0 {
 		return w.Writer.Write(p)
 	}
 
-	// Find the position of stack_trace field
-	stackTraceKey := []byte("\"stack_trace\":\"")
-	idx := bytes.Index(p, stackTraceKey)
-
-	// Calculate the starting position of the stack trace value (after the key)
-	startIdx := idx + len(stackTraceKey)
-	endIdx := startIdx
-
-	// Scan forward to find the closing quote, respecting escape sequences
-	for endIdx < len(p)-1 {
-		if p[endIdx] == '\\' {
-			endIdx += 2 // Skip both backslash and the escaped character
-			continue
-		}
-		if p[endIdx] == '"' {
-			break // Found the closing quote
+	// p may hold several entries when written through WithAsync's buffer,
+	// so every stack trace value is formatted, not just the first
+	w.buf.Reset()
+	rest := p
+	for idx >= 0 {
+		// Calculate the starting position of the stack trace value (after the key)
+		startIdx := idx + len(stackTraceKey)
+		endIdx := startIdx
+
+		// Scan forward to find the closing quote, respecting escape sequences
+		for endIdx < len(rest)-1 {
+			if rest[endIdx] == '\\' {
+				endIdx += 2 // Skip both backslash and the escaped character
+				continue
+			}
+			if rest[endIdx] == '"' {
+				break // Found the closing quote
+			}
+			endIdx++
 		}
-		endIdx++
-	}
 
-	// If we didn't find a complete stack trace value, write data as-is
-	if endIdx >= len(p) {
-		return w.Writer.Write(p)
-	}
+		// If we didn't find a complete stack trace value, write the rest as-is
+		if endIdx >= len(rest) {
+			break
+		}
 
-	// Decode the JSON-escaped stack trace string to get the actual content
-	stackBytes := p[startIdx:endIdx]
-	stackStr := decodeJSONString(stackBytes)
+		// Decode the JSON-escaped stack trace string to get the actual content
+		stackStr := decodeJSONString(rest[startIdx:endIdx])
 
-	// Format the stack trace and reconstruct the JSON with formatted stack trace
-	w.buf.Reset()
-	w.buf.Write(p[:startIdx])              // Write everything before the stack trace value
-	formatStackTraceBytes(w.buf, stackStr) // Write the formatted stack trace
-	w.buf.Write(p[endIdx:])                // Write everything after the stack trace value (closing quote and rest)
+		// Format the stack trace in place of the original value
+		w.buf.Write(rest[:startIdx])           // Write everything before the stack trace value
+		formatStackTraceBytes(w.buf, stackStr) // Write the formatted stack trace
+		rest = rest[endIdx:]                   // Continue from the closing quote
+		idx = bytes.Index(rest, stackTraceKey)
+	}
+	w.buf.Write(rest)
 
-	return w.Writer.Write(w.buf.Bytes())
+	if _, err := w.Writer.Write(w.buf.Bytes()); err != nil {
+		return 0, err
+	}
+	// Report p as fully written: the formatted length differs from len(p)
+	return len(p), nil
 }
 
 // decodeJSONString decodes a JSON-escaped string without unmarshaling the entire JSON.
@@ -197,15 +273,44 @@ func (w *stackTraceFormattingWriter) Sync() error {
 	return nil
 }
 
+// newWriteSyncer wraps each writer with its own stackTraceFormattingWriter
+// so stack trace formatting applies per destination, then fans them out
+// with zapcore.NewMultiWriteSyncer when more than one writer is given.
+// With StackTraceMultiline, writers are used as-is so stack traces keep
+// their original line breaks.
+func newWriteSyncer(ws []io.Writer, stackKey string, stackFormat string) zapcore.WriteSyncer {
+	key := stackTracePattern(stackKey)
+	syncers := make([]zapcore.WriteSyncer, 0, len(ws))
+	for _, w := range ws {
+		if w == nil {
+			continue
+		}
+		if stackFormat == StackTraceMultiline {
+			syncers = append(syncers, zapcore.AddSync(w))
+			continue
+		}
+		// Use custom writer for zero-allocation stack trace formatting
+		syncers = append(syncers, &stackTraceFormattingWriter{
+			Writer: w,
+			buf:    bytes.NewBuffer(make([]byte, 0, 1024)),
+			key:    key,
+		})
+	}
+	if len(syncers) == 1 {
+		return syncers[0]
+	}
+	return zapcore.NewMultiWriteSyncer(syncers...)
+}
+
 // New creates a new Logger instance with the given options.
-// Returns an error if logger initialization fails.
 //
 // Example:
 //
-//	logger, err := goslogx.New(
+//	logger := goslogx.New(
 //	    goslogx.WithServiceName("my-service"),
-//	    goslogx.WithLevel(zapcore.DebugLevel),
-//	    goslogx.WithMasking(),
+//	    goslogx.WithDebug(true),
+//	    goslogx.WithMasking(true),
+//	)
 //
 // New sets the global logger configuration exactly once.
 // Subsequent calls to New will return the existing global logger.
@@ -217,6 +322,46 @@ func New(opts ...Option) *Logger {
 	return globalLog.Load()
 }
 
+// NewLogger creates a standalone Logger with the given options, e.g. for a
+// component that logs to its own output. Unlike New it does not touch the
+// global logger, and each call returns a new Logger.
+//
+// Example:
+//
+//	audit := goslogx.NewLogger(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithOutput(auditFile),
+//	)
+//	audit.Info(traceID, "audit", goslogx.MESSAGE_TYPE_EVENT, "role changed", change)
+func NewLogger(opts ...Option) *Logger {
+	return setupLog(opts...)
+}
+
+// Reconfigure replaces the global logger with one built from opts, e.g. to
+// re-point output after log rotation. Unlike New it can be called any number
+// of times, and a later New keeps the reconfigured logger. Log calls running
+// concurrently finish on the previous logger, which is then closed; the
+// returned error is from closing it, see Logger.Close.
+//
+// Example:
+//
+//	f, err := os.OpenFile("app.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+//	if err != nil {
+//	    return err
+//	}
+//	if err := goslogx.Reconfigure(goslogx.WithOutput(f)); err != nil {
+//	    return err
+//	}
+func Reconfigure(opts ...Option) error {
+	reconfigureMu.Lock()
+	defer reconfigureMu.Unlock()
+
+	// Keep a later New from overwriting this configuration
+	once.Do(func() {})
+	prev := globalLog.Swap(setupLog(opts...))
+	return prev.Close()
+}
+
 func setupLog(opts ...Option) *Logger {
 	// Apply options to default config
 	cfg := defaultConfig()
@@ -224,38 +369,245 @@ func setupLog(opts ...Option) *Logger {
 		opt(cfg)
 	}
 
+	// Reject remappings that would collide with another key
+	rejected := cfg.FieldKeys.normalize()
+	keys := cfg.FieldKeys
+
 	// Configure JSON encoder with production defaults
 	encoderConfig := zap.NewProductionEncoderConfig()
-	encoderConfig.TimeKey = "time"
+	encoderConfig.TimeKey = keys.Time
+	encoderConfig.LevelKey = keys.Level
+	encoderConfig.MessageKey = keys.Message
 	encoderConfig.EncodeTime = zapcore.RFC3339TimeEncoder
-	encoderConfig.CallerKey = "source"
-	encoderConfig.FunctionKey = "function"
-	encoderConfig.StacktraceKey = "stack_trace"
+	encoderConfig.CallerKey = keys.Source
+	encoderConfig.FunctionKey = keys.Function
+	encoderConfig.StacktraceKey = keys.StackTrace
 	encoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
 
-	// Use custom writer for zero-allocation stack trace formatting
-	writer := &stackTraceFormattingWriter{
-		Writer: cfg.Output,
-		buf:    bytes.NewBuffer(make([]byte, 0, 1024)),
+	// Outputs (from WithOutputs) take precedence over the single Output
+	outputs := cfg.Outputs
+	if len(outputs) == 0 {
+		outputs = []io.Writer{cfg.Output}
 	}
 
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderConfig),
-		zapcore.AddSync(writer),
-		cfg.Level,
-	)
+	var encoder zapcore.Encoder
+	prettyJSON := false
+	switch cfg.Encoding {
+	case EncodingConsole:
+		encoder = newConsoleEncoder(encoderConfig, cfg.StackTraceFormat == StackTraceMultiline)
+	case EncodingLogfmt:
+		encoder = newLogfmtEncoder(encoderConfig, cfg.StackTraceFormat == StackTraceMultiline)
+	default:
+		prettyJSON = cfg.PrettyJSON
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+		if len(cfg.FieldOrder) > 0 {
+			encoder = &orderedEncoder{Encoder: encoder, order: cfg.FieldOrder}
+		}
+	}
+	if cfg.Masking.Enabled && cfg.Masking.ScanMessage {
+		encoder = messageEncoder{Encoder: encoder}
+	}
+	if replacer := newSensitiveReplacer(cfg.Masking.SensitiveValues); replacer != nil {
+		encoder = &sensitiveEncoder{Encoder: encoder, replacer: replacer}
+	}
+	// Buffer writes in front of the stack trace formatting when async
+	var closers []func() error
+	writeSyncer := func(ws []io.Writer) zapcore.WriteSyncer {
+		if prettyJSON {
+			ws = prettyJSONWriters(ws)
+		}
+		syncer := newWriteSyncer(ws, keys.StackTrace, cfg.StackTraceFormat)
+		if !cfg.Async {
+			return syncer
+		}
+		buffered := &zapcore.BufferedWriteSyncer{
+			WS:            syncer,
+			Size:          cfg.AsyncBufferSize,
+			FlushInterval: cfg.AsyncFlushInterval,
+		}
+		closers = append(closers, buffered.Stop)
+		return buffered
+	}
+
+	// Number entries on each output core rather than around the tee, whose
+	// Write goes to every output regardless of level
+	seq := new(atomic.Uint64)
+	newCore := func(enc zapcore.Encoder, ws zapcore.WriteSyncer, level zapcore.LevelEnabler) zapcore.Core {
+		core := zapcore.NewCore(enc, ws, level)
+		if cfg.Sequence {
+			core = newSeqCore(core, &keys, seq)
+		}
+		return core
+	}
 
-	logger := zap.New(
-		core,
+	var core zapcore.Core
+	if cfg.ErrorOutput != nil {
+		// Route warnings and above to ErrorOutput, everything below to outputs
+		stdLevel := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+			return cfg.Level.Enabled(lvl) && lvl < zapcore.WarnLevel
+		})
+		errLevel := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+			return cfg.Level.Enabled(lvl) && lvl >= zapcore.WarnLevel
+		})
+		core = zapcore.NewTee(
+			newCore(encoder, writeSyncer(outputs), stdLevel),
+			newCore(encoder.Clone(), writeSyncer([]io.Writer{cfg.ErrorOutput}), errLevel),
+		)
+	} else {
+		core = newCore(encoder, writeSyncer(outputs), cfg.Level)
+	}
+
+	// Innermost, so the sampler, which decides in Check, stays in front of it
+	if len(cfg.RequiredFields) > 0 {
+		core = newValidateCore(core, cfg.RequiredFields, cfg.ValidationPanic, &keys, cfg.SeverityMapping)
+	}
+	for _, wrap := range cfg.CoreWrappers {
+		core = wrap(core)
+	}
+	clock := cfg.Clock
+	if clock == nil {
+		clock = zapcore.DefaultClock
+	}
+	if cfg.Location != nil {
+		clock = locationClock{Clock: clock, loc: cfg.Location}
+	}
+	if cfg.ErrorDedupWindow > 0 {
+		core = newDedupCore(core, cfg.ErrorDedupWindow, &keys, clock)
+	}
+	if cfg.SamplingThereafter > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, cfg.SamplingInitial, cfg.SamplingThereafter)
+	}
+
+	zapOpts := []zap.Option{
 		zap.AddStacktrace(zapcore.FatalLevel),
-	).With(zap.String("application_name", cfg.ServiceName))
+		zap.WithClock(clock),
+	}
+	if cfg.ExitFunc != nil {
+		zapOpts = append(zapOpts, zap.WithFatalHook(exitAfterFatal(cfg.ExitFunc)))
+	}
+	logger := zap.New(core, zapOpts...).With(zap.String(keys.ApplicationName, cfg.ServiceName))
+	if cfg.HostInfo {
+		if hostname, err := os.Hostname(); err == nil {
+			logger = logger.With(zap.String(keys.Hostname, hostname))
+		}
+		logger = logger.With(zap.Int(keys.PID, os.Getpid()))
+	}
+
+	for _, r := range rejected {
+		logger.Warn("field key remap ignored", zap.String("reason", r))
+	}
+
+	return &Logger{
+		logger:          logger,
+		config:          cfg,
+		closers:         append(closers, cfg.Closers...),
+		invalidMsgTypes: &sync.Map{},
+	}
+}
 
+// locationClock is a zapcore.Clock returning the wrapped clock's times in
+// loc, see WithTimezone.
+type locationClock struct {
+	zapcore.Clock
+	loc *time.Location
+}
+
+// Now implements zapcore.Clock.
+func (c locationClock) Now() time.Time {
+	return c.Clock.Now().In(c.loc)
+}
+
+// NewNop returns a Logger that discards every entry, for tests and tools that
+// need logging suppressed. Unlike New(WithOutput(io.Discard)), which still
+// encodes each entry before throwing it away, its methods return almost
+// immediately. Fatal still terminates the process. It does not touch the
+// global logger.
+//
+// Example:
+//
+//	svc := NewService(goslogx.NewNop())
+func NewNop() *Logger {
 	return &Logger{
-		logger: logger,
-		config: cfg,
+		logger:          zap.NewNop(),
+		config:          defaultConfig(),
+		invalidMsgTypes: &sync.Map{},
 	}
 }
 
+// Sync flushes buffered entries, such as a pending WithErrorDedup summary,
+// and syncs the underlying writers. Call it before the process exits.
+func (l *Logger) Sync() error {
+	return l.logger.Sync()
+}
+
+// Sync flushes the global logger, see Logger.Sync.
+func Sync() error {
+	return globalLog.Load().Sync()
+}
+
+// Close flushes buffered entries like Sync, stops the background flushing
+// started by WithAsync, and calls the closers set via WithCloser. Call it
+// once on shutdown, after the last log call; entries logged afterwards are
+// written synchronously. Loggers derived with With share the buffers, so
+// closing any of them closes all.
+//
+// Example:
+//
+//	logger := goslogx.New(goslogx.WithAsync(0, time.Second))
+//	defer logger.Close()
+func (l *Logger) Close() error {
+	err := l.Sync()
+	for _, stop := range l.closers {
+		err = errors.Join(err, stop())
+	}
+	return err
+}
+
+// Close closes the global logger, see Logger.Close.
+func Close() error {
+	return globalLog.Load().Close()
+}
+
+// With returns a child Logger that carries the given fields into every
+// subsequent log entry. Field values are masked using the same rules as
+// the data argument. Keys are added in sorted order for stable output.
+// The parent Logger is not modified.
+//
+// Example:
+//
+//	reqLog := logger.With(map[string]any{"user_id": "usr-001", "tenant": "acme"})
+//	reqLog.Info("trace-001", "handler", goslogx.MESSAGE_TYPE_EVENT, "request received", nil)
+func (l *Logger) With(fields map[string]any) *Logger {
+	if len(fields) == 0 {
+		return l
+	}
+	child := *l
+	child.logger = l.logger.With(appendMapFields(nil, fields, &l.config.Masking)...)
+	return &child
+}
+
+// appendMapFields appends fields to dst as masked zap fields, sorted by key,
+// leaving out those listed in cfg.RedactFields.
+func appendMapFields(dst []zap.Field, fields map[string]any, cfg *MaskingConfig) []zap.Field {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if !cfg.redacts(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		dst = append(dst, maskedField(k, fields[k], cfg))
+	}
+	return dst
+}
+
+// With returns a child of the global logger that carries the given fields
+// into every subsequent log entry. See (*Logger).With for details.
+func With(fields map[string]any) *Logger {
+	return globalLog.Load().With(fields)
+}
+
 // Severity level constants for Cloud Logging compatibility.
 // Using constants avoids string allocations on every log call.
 const (
@@ -264,11 +616,66 @@ const (
 	severityWarning  = "WARNING"
 	severityError    = "ERROR"
 	severityCritical = "CRITICAL"
+	severityDefault  = "DEFAULT"
 )
 
+// severityFor returns the severity value for lvl, preferring overrides
+// set via WithSeverityMapping over the built-in values.
+func severityFor(lvl zapcore.Level, overrides map[zapcore.Level]string) string {
+	if s, ok := overrides[lvl]; ok {
+		return s
+	}
+	switch lvl {
+	case zapcore.DebugLevel:
+		return severityDebug
+	case zapcore.InfoLevel:
+		return severityInfo
+	case zapcore.WarnLevel:
+		return severityWarning
+	case zapcore.ErrorLevel:
+		return severityError
+	case zapcore.FatalLevel:
+		return severityCritical
+	}
+	return severityDefault
+}
+
+// stackField returns the stack trace field for stack, cut to the depth set
+// via WithErrorStackDepth.
+func (l *Logger) stackField(stack string) zap.Field {
+	return zap.String(l.config.FieldKeys.StackTrace, truncateStack(stack, l.config.StackTraceDepth))
+}
+
+// severity returns the severity value for lvl using l's mapping.
+func (l *Logger) severity(lvl zapcore.Level) string {
+	return severityFor(lvl, l.config.SeverityMapping)
+}
+
+// module returns module, or the configured default module if it is empty.
+func (l *Logger) module(module string) string {
+	if module == "" {
+		return l.config.Module
+	}
+	return module
+}
+
+// msgType returns msgType, or MESSAGE_TYPE_UNKNOWN if it is unrecognized and
+// StrictMsgType is set, logging a warning the first time each value is seen.
+func (l *Logger) msgType(traceID string, module string, msgType MsgType) MsgType {
+	if !l.config.StrictMsgType || IsValidMsgType(msgType) {
+		return msgType
+	}
+	if _, warned := l.invalidMsgTypes.LoadOrStore(msgType, struct{}{}); !warned {
+		l.Warning(traceID, module, "unrecognized message type "+strconv.Quote(string(msgType))+
+			", logged as "+string(MESSAGE_TYPE_UNKNOWN), nil)
+	}
+	return MESSAGE_TYPE_UNKNOWN
+}
+
 // fieldPool reuses zap.Field slices to reduce allocations.
 // Capacity of 6 is the maximum number of fields used in any logging function:
-// trace_id, module, msg_type, severity, data, error = 6 fields max
+// trace_id, module, msg_type, severity, data = 5 fields for Info and Debug,
+// trace_id, module, error, severity, causes, stack_trace = 6 fields for Error
 var fieldPool = sync.Pool{
 	New: func() any { return make([]zap.Field, 0, 6) },
 }
@@ -285,184 +692,294 @@ func putFields(f []zap.Field) {
 	fieldPool.Put(f[:0])
 }
 
-// Fatal logs a critical error and terminates the process.
+// Fatal logs a critical error and terminates the process with os.Exit(1),
+// or calls the function set via WithExitFunc instead.
 func (l *Logger) Fatal(traceID string, module string, err error) {
+	if l.config.BaseContext != nil && !l.inContext {
+		l, traceID = l.fromContext(l.config.BaseContext, traceID)
+	}
 	fields := getFields()
 	defer putFields(fields)
 
-	callerSkip := detectCallerSkip()
+	callerSkip := l.config.CallerSkip
+	if callerSkip <= 0 {
+		callerSkip = detectCallerSkip(l.config.CallerMaxDepth)
+	}
+	keys := &l.config.FieldKeys
 
 	logger := l.logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(callerSkip))
 	fields = append(fields,
-		zap.String("trace_id", traceID),
-		zap.String("module", module),
-		zap.Error(err),
-		zap.String("severity", severityCritical),
+		zap.String(keys.TraceID, traceID),
+		zap.String(keys.Module, l.module(module)),
+		errorField(keys.Error, err),
+		zap.String(keys.Severity, l.severity(zapcore.FatalLevel)),
 	)
+	fields = l.appendErrorDetails(fields, err)
+	// The stack below replaces zap's, so that WithErrorStackDepth applies
+	logger = logger.WithOptions(zap.AddStacktrace(zapcore.InvalidLevel))
+	// Prefer where the error was created over the goroutine stack
+	stack, ok := errorStack(err)
+	if !ok {
+		stack = zap.StackSkip("", callerSkip).String
+	}
+	if l.config.CompactErrors {
+		fields = l.appendCompactError(fields, err, stack)
+	} else {
+		fields = append(fields, l.stackField(stack))
+	}
 
+	l.runErrorHook(l.config.OnFatal, zapcore.FatalLevel, "fatal error occurred", traceID, l.module(module), err, fields)
 	logger.Log(zapcore.FatalLevel, "fatal error occurred", fields...)
 }
 
 // Fatal logs a critical error using the global logger and terminates the process.
 func Fatal(traceID string, module string, err error) {
-	fields := getFields()
-	defer putFields(fields)
-
-	callerSkip := detectCallerSkip()
-
-	logger := globalLog.Load().logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(callerSkip))
-	fields = append(fields,
-		zap.String("trace_id", traceID),
-		zap.String("module", module),
-		zap.Error(err),
-		zap.String("severity", severityCritical),
-	)
-
-	logger.Log(zapcore.FatalLevel, "fatal error occurred", fields...)
+	globalLog.Load().Fatal(traceID, module, err)
 }
 
-// Error logs an error event with automatic stack trace capture.
-func (l *Logger) Error(traceID string, module string, err error) {
+// Error logs an error event. Errors from github.com/pkg/errors, also when
+// wrapped with fmt.Errorf("...: %w", err), add the stack trace recorded where
+// the error was created; other errors log the message only.
+// With WithCompactErrors, the stack is replaced by "error_cause" and
+// "error_origin" fields.
+//
+// Errors in the chain implementing Code() string add an "error_code" field,
+// and those implementing Fields() map[string]any add their fields to the entry.
+// Extra fields are masked like the entries of a map passed as data and added
+// after them.
+//
+// Example:
+//
+//	logger.Error(traceID, "orders", err, goslogx.Field{Key: "order_id", Val: orderID})
+func (l *Logger) Error(traceID string, module string, err error, extra ...Field) {
+	// Skip building fields for entries that would be dropped
+	if !l.logger.Core().Enabled(zapcore.ErrorLevel) {
+		return
+	}
+	if l.config.BaseContext != nil && !l.inContext {
+		l, traceID = l.fromContext(l.config.BaseContext, traceID)
+	}
 	fields := getFields()
 	defer putFields(fields)
 
-	callerSkip := detectCallerSkip()
+	callerSkip := l.config.CallerSkip
+	if callerSkip <= 0 {
+		callerSkip = detectCallerSkip(l.config.CallerMaxDepth)
+	}
+	keys := &l.config.FieldKeys
 
 	logger := l.logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(callerSkip))
 	fields = append(fields,
-		zap.String("trace_id", traceID),
-		zap.String("module", module),
-		zap.Error(err),
-		zap.String("severity", severityError),
+		zap.String(keys.TraceID, traceID),
+		zap.String(keys.Module, l.module(module)),
+		errorField(keys.Error, err),
+		zap.String(keys.Severity, l.severity(zapcore.ErrorLevel)),
 	)
+	fields = l.appendErrorDetails(fields, err)
+	fields = l.appendFields(fields, extra)
+	stack, ok := errorStack(err)
+	switch {
+	case l.config.CompactErrors:
+		if !ok {
+			// Without a recorded stack, the error surfaced at the caller
+			stack = zap.StackSkip("", callerSkip).String
+		}
+		fields = l.appendCompactError(fields, err, stack)
+	case ok:
+		fields = append(fields, l.stackField(stack))
+	}
+	l.runErrorHook(l.config.OnError, zapcore.ErrorLevel, "error occurred", traceID, l.module(module), err, fields)
 	logger.Log(zapcore.ErrorLevel, "error occurred", fields...)
 }
 
 // Error logs an error event using the global logger with automatic stack trace capture.
-func Error(traceID string, module string, err error) {
-	fields := getFields()
-	defer putFields(fields)
-
-	callerSkip := detectCallerSkip()
-
-	logger := globalLog.Load().logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(callerSkip))
-	fields = append(fields,
-		zap.String("trace_id", traceID),
-		zap.String("module", module),
-		zap.Error(err),
-		zap.String("severity", severityError),
-	)
-	logger.Log(zapcore.ErrorLevel, "error occurred", fields...)
+func Error(traceID string, module string, err error, extra ...Field) {
+	globalLog.Load().Error(traceID, module, err, extra...)
 }
 
 // Warning logs a warning-level message with optional context data.
 func (l *Logger) Warning(traceID string, module string, msg string, data any) {
+	// Skip building fields for entries that would be dropped
+	if !l.logger.Core().Enabled(zapcore.WarnLevel) {
+		return
+	}
+	if l.config.BaseContext != nil && !l.inContext {
+		l, traceID = l.fromContext(l.config.BaseContext, traceID)
+	}
 	fields := getFields()
 	defer putFields(fields)
 
-	callerSkip := detectCallerSkip()
+	callerSkip := l.config.CallerSkip
+	if callerSkip <= 0 {
+		callerSkip = detectCallerSkip(l.config.CallerMaxDepth)
+	}
+	keys := &l.config.FieldKeys
 
 	logger := l.logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(callerSkip))
 	fields = append(fields,
-		zap.String("trace_id", traceID),
-		zap.String("module", module),
-		zap.String("severity", severityWarning),
+		zap.String(keys.TraceID, traceID),
+		zap.String(keys.Module, l.module(module)),
+		zap.String(keys.Severity, l.severity(zapcore.WarnLevel)),
 	)
 	if data != nil {
-		fields = append(fields, zap.Any("data", data))
+		fields = l.appendData(fields, zapcore.WarnLevel, data)
 	}
 	logger.Log(zapcore.WarnLevel, msg, fields...)
 }
 
 // Warning logs a warning-level message using the global logger with optional context data.
 func Warning(traceID string, module string, msg string, data any) {
+	globalLog.Load().Warning(traceID, module, msg, data)
+}
+
+// WarningErr logs a recoverable error at warning level. Unlike Warning, the
+// entry carries the error and a stack trace, so the origin of the error can be
+// found without escalating it to Error. The stack is the one recorded by a
+// github.com/pkg/errors error in err's chain, or else starts at the caller.
+func (l *Logger) WarningErr(traceID string, module string, msg string, err error) {
+	// Skip building fields for entries that would be dropped
+	if !l.logger.Core().Enabled(zapcore.WarnLevel) {
+		return
+	}
+	if l.config.BaseContext != nil && !l.inContext {
+		l, traceID = l.fromContext(l.config.BaseContext, traceID)
+	}
 	fields := getFields()
 	defer putFields(fields)
 
-	callerSkip := detectCallerSkip()
+	callerSkip := l.config.CallerSkip
+	if callerSkip <= 0 {
+		callerSkip = detectCallerSkip(l.config.CallerMaxDepth)
+	}
+	keys := &l.config.FieldKeys
 
-	logger := globalLog.Load().logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(callerSkip))
+	logger := l.logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(callerSkip))
 	fields = append(fields,
-		zap.String("trace_id", traceID),
-		zap.String("module", module),
-		zap.String("severity", severityWarning),
+		zap.String(keys.TraceID, traceID),
+		zap.String(keys.Module, l.module(module)),
+		errorField(keys.Error, err),
+		zap.String(keys.Severity, l.severity(zapcore.WarnLevel)),
 	)
-	if data != nil {
-		fields = append(fields, zap.Any("data", data))
+	fields = l.appendErrorDetails(fields, err)
+	if stack, ok := errorStack(err); ok {
+		fields = append(fields, l.stackField(stack))
+	} else {
+		fields = append(fields, l.stackField(zap.StackSkip("", callerSkip).String))
 	}
 	logger.Log(zapcore.WarnLevel, msg, fields...)
 }
 
+// WarningErr logs a recoverable error at warning level using the global logger,
+// including the error and a stack trace starting at the caller.
+func WarningErr(traceID string, module string, msg string, err error) {
+	globalLog.Load().WarningErr(traceID, module, msg, err)
+}
+
 // Info logs an informational message with a specified message type.
 func (l *Logger) Info(traceID string, module string, msgType MsgType, msg string, data any) {
+	// Skip building fields for entries that would be dropped
+	if !l.logger.Core().Enabled(zapcore.InfoLevel) {
+		return
+	}
+	if l.config.BaseContext != nil && !l.inContext {
+		l, traceID = l.fromContext(l.config.BaseContext, traceID)
+	}
 	fields := getFields()
 	defer putFields(fields)
 
+	keys := &l.config.FieldKeys
+
+	// Source is opt-in for Info, see WithSource
+	logger := l.logger
+	if l.config.Source {
+		callerSkip := l.config.CallerSkip
+		if callerSkip <= 0 {
+			callerSkip = detectCallerSkip(l.config.CallerMaxDepth)
+		}
+		logger = logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(callerSkip))
+	}
 	fields = append(fields,
-		zap.String("trace_id", traceID),
-		zap.String("module", module),
-		zap.String("msg_type", string(msgType)),
-		zap.String("severity", severityInfo),
+		zap.String(keys.TraceID, traceID),
+		zap.String(keys.Module, l.module(module)),
+		zap.String(keys.MsgType, string(l.msgType(traceID, module, msgType))),
+		zap.String(keys.Severity, l.severity(zapcore.InfoLevel)),
 	)
 	if data != nil {
-		fields = append(fields, dataField("data", data))
+		fields = l.appendData(fields, zapcore.InfoLevel, data)
 	}
-	l.logger.Log(zapcore.InfoLevel, msg, fields...)
+	logger.Log(zapcore.InfoLevel, msg, fields...)
+}
+
+// appendData appends the data field of an entry at level lvl to fields,
+// masked unless lvl is one of UnmaskedLevels and flattened when
+// FlattenSeparator is set, followed by the count of masked values when
+// EmitMaskCount is set. Masking happens when the entry is
+// encoded, so the count is an inline field encoded after the data.
+func (l *Logger) appendData(fields []zap.Field, lvl zapcore.Level, data any) []zap.Field {
+	cfg := &l.config.Masking
+	keys := &l.config.FieldKeys
+	var field zap.Field
+	var count *maskCounter
+	switch {
+	case !cfg.masks(lvl):
+		field = unmaskedField(keys.Data, data, cfg)
+	case cfg.EmitMaskCount:
+		count = &maskCounter{key: keys.MaskedCount}
+		field = countedField(keys.Data, data, cfg, count)
+	default:
+		field = maskedField(keys.Data, data, cfg)
+	}
+	if sep := l.config.FlattenSeparator; sep != "" && field.Type != zapcore.SkipType {
+		field = zap.Inline(flatField{field: field, sep: sep})
+	}
+	fields = append(fields, field)
+	if count != nil {
+		fields = append(fields, zap.Inline(count))
+	}
+	return fields
 }
 
 // Info logs an informational message using the global logger with a specified message type.
 func Info(traceID string, module string, msgType MsgType, msg string, data any) {
-	fields := getFields()
-	defer putFields(fields)
-
-	fields = append(fields,
-		zap.String("trace_id", traceID),
-		zap.String("module", module),
-		zap.String("msg_type", string(msgType)),
-		zap.String("severity", severityInfo),
-	)
-	if data != nil {
-		fields = append(fields, dataField("data", data))
-	}
-	globalLog.Load().logger.Log(zapcore.InfoLevel, msg, fields...)
+	globalLog.Load().Info(traceID, module, msgType, msg, data)
 }
 
 // Debug logs a debug-level message with a specified message type.
 func (l *Logger) Debug(traceID string, module string, msgType MsgType, msg string, data any) {
+	// Skip building fields for entries that would be dropped
+	if !l.logger.Core().Enabled(zapcore.DebugLevel) {
+		return
+	}
+	if l.config.BaseContext != nil && !l.inContext {
+		l, traceID = l.fromContext(l.config.BaseContext, traceID)
+	}
 	fields := getFields()
 	defer putFields(fields)
 
-	callerSkip := detectCallerSkip()
+	keys := &l.config.FieldKeys
 
-	logger := l.logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(callerSkip))
+	// Source is opt-in for Debug, see WithSource
+	logger := l.logger
+	if l.config.Source {
+		callerSkip := l.config.CallerSkip
+		if callerSkip <= 0 {
+			callerSkip = detectCallerSkip(l.config.CallerMaxDepth)
+		}
+		logger = logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(callerSkip))
+	}
 	fields = append(fields,
-		zap.String("trace_id", traceID),
-		zap.String("module", module),
-		zap.String("msg_type", string(msgType)),
-		zap.String("severity", severityDebug),
+		zap.String(keys.TraceID, traceID),
+		zap.String(keys.Module, l.module(module)),
+		zap.String(keys.MsgType, string(l.msgType(traceID, module, msgType))),
+		zap.String(keys.Severity, l.severity(zapcore.DebugLevel)),
 	)
 	if data != nil {
-		fields = append(fields, zap.Any("data", data))
+		fields = l.appendData(fields, zapcore.DebugLevel, data)
 	}
 	logger.Log(zapcore.DebugLevel, msg, fields...)
 }
 
 // Debug logs a debug-level message using the global logger with a specified message type.
 func Debug(traceID string, module string, msgType MsgType, msg string, data any) {
-	fields := getFields()
-	defer putFields(fields)
-
-	callerSkip := detectCallerSkip()
-
-	logger := globalLog.Load().logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(callerSkip))
-	fields = append(fields,
-		zap.String("trace_id", traceID),
-		zap.String("module", module),
-		zap.String("msg_type", string(msgType)),
-		zap.String("severity", severityDebug),
-	)
-	if data != nil {
-		fields = append(fields, zap.Any("data", data))
-	}
-	logger.Log(zapcore.DebugLevel, msg, fields...)
+	globalLog.Load().Debug(traceID, module, msgType, msg, data)
 }
@@ -1,8 +1,10 @@
 package goslogx_test
 
 import (
+	"io"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/muhammadluth/goslogx"
 )
@@ -204,3 +206,53 @@ type CustomError struct {
 }
 
 func (e *CustomError) Error() string { return e.Msg }
+
+func BenchmarkInfoSync(b *testing.B) {
+	f, _ := os.OpenFile(os.DevNull, os.O_WRONLY, 0666)
+	defer f.Close()
+	defer goslogx.ReplaceGlobal(goslogx.WithServiceName("bench-service"), goslogx.WithOutput(f))()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		goslogx.Info("trace-001", "api", goslogx.MESSSAGE_TYPE_EVENT, "request received", nil)
+	}
+}
+
+func BenchmarkInfoAsync(b *testing.B) {
+	f, _ := os.OpenFile(os.DevNull, os.O_WRONLY, 0666)
+	defer f.Close()
+	defer goslogx.ReplaceGlobal(goslogx.WithServiceName("bench-service"), goslogx.WithOutput(f), goslogx.WithAsync(0, time.Second))()
+	defer goslogx.Close()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		goslogx.Info("trace-001", "api", goslogx.MESSSAGE_TYPE_EVENT, "request received", nil)
+	}
+}
+
+func BenchmarkWarningCallerSkip(b *testing.B) {
+	for _, bm := range []struct {
+		name string
+		opts []goslogx.Option
+	}{
+		{"Detect", nil},
+		{"Fixed", []goslogx.Option{goslogx.WithCallerSkip(2)}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			defer goslogx.ReplaceGlobal(append(bm.opts, goslogx.WithOutput(io.Discard))...)()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				goslogx.Warning("trace-001", "api", "slow request", nil)
+			}
+		})
+	}
+}
+
+func BenchmarkNopInfo(b *testing.B) {
+	logger := goslogx.NewNop()
+	data := map[string]any{"password": "secret"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("trace-001", "api", goslogx.MESSAGE_TYPE_EVENT, "request received", data)
+	}
+}
@@ -1,10 +1,14 @@
 package goslogx_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"os"
 	"os/exec"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -71,6 +75,60 @@ func TestDTOs(t *testing.T) {
 	})
 }
 
+// TestDeprecatedMsgTypes verifies the misspelled names alias the new constants
+func TestDeprecatedMsgTypes(t *testing.T) {
+	pairs := []struct{ old, new goslogx.MsgType }{
+		{goslogx.MESSSAGE_TYPE_IN, goslogx.MESSAGE_TYPE_IN},
+		{goslogx.MESSSAGE_TYPE_OUT, goslogx.MESSAGE_TYPE_OUT},
+		{goslogx.MESSSAGE_TYPE_REQUEST, goslogx.MESSAGE_TYPE_REQUEST},
+		{goslogx.MESSSAGE_TYPE_RESPONSE, goslogx.MESSAGE_TYPE_RESPONSE},
+		{goslogx.MESSSAGE_TYPE_EVENT, goslogx.MESSAGE_TYPE_EVENT},
+	}
+	for _, p := range pairs {
+		if p.old != p.new {
+			t.Errorf("Expected deprecated %q to equal %q", p.old, p.new)
+		}
+	}
+}
+
+func TestGenericDataBuilder(t *testing.T) {
+	b := goslogx.NewGenericData("Stripe").
+		Action("Charge").
+		Payload(map[string]any{"amount": 100}).
+		Field("customer_email", "jane@example.com").
+		Field("password", "hunter2")
+	data := b.Build()
+	if data.Service != "Stripe" || data.Action != "Charge" || len(data.Fields) != 2 {
+		t.Errorf("Unexpected GenericData: %+v", data)
+	}
+	b.Field("retry", 1)
+	if _, ok := data.Fields["retry"]; ok {
+		t.Error("Expected built data to be unaffected by later Field calls")
+	}
+
+	var zero goslogx.GenericDataBuilder
+	if got := zero.Action("Ping").Build(); got.Action != "Ping" || got.Fields != nil {
+		t.Errorf("Expected zero builder to be usable, got %+v", got)
+	}
+
+	buf := &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf))()
+	goslogx.Info("trace-123", "payment", goslogx.MESSSAGE_TYPE_REQUEST, "charge initiated", b)
+	var entry struct {
+		Data struct {
+			Service string         `json:"service"`
+			Fields  map[string]any `json:"fields"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to decode entry: %v", err)
+	}
+	want := map[string]any{"customer_email": "ja****om", "password": "****", "retry": float64(1)}
+	if entry.Data.Service != "Stripe" || !reflect.DeepEqual(entry.Data.Fields, want) {
+		t.Errorf("Expected masked fields %v, got %+v", want, entry.Data)
+	}
+}
+
 // TestLoggingFunctions ensures all logging functions execute without panicking
 func TestLoggingFunctions(t *testing.T) {
 	goslogx.New(goslogx.WithServiceName("test-service"))
@@ -152,6 +210,28 @@ func TestFatal(t *testing.T) {
 	t.Fatalf("process ran with err %v, want exit status 1", err)
 }
 
+// TestFatalExitFunc verifies Fatal calls the injected exit function instead of os.Exit
+func TestFatalExitFunc(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var codes []int
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf), goslogx.WithExitFunc(func(code int) {
+		codes = append(codes, code)
+	}))()
+
+	goslogx.Fatal("crash-trace", "main", errors.New("critical failure"))
+
+	if len(codes) != 1 || codes[0] != 1 {
+		t.Fatalf("Expected exit to be called once with code 1, got %v", codes)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to decode %q: %v", buf.String(), err)
+	}
+	if entry["level"] != "fatal" || entry["error"] != "critical failure" || entry["trace_id"] != "crash-trace" {
+		t.Errorf("Expected the fatal entry to be written first, got %v", entry)
+	}
+}
+
 // TestLoggerMethods tests direct methods on Logger instance
 // Since Logger instance creation is internal, we test it in internal tests
 // but we can ensure global functions work here.
@@ -173,3 +253,142 @@ func TestNewConcurrency(t *testing.T) {
 	// Give them time to race
 	time.Sleep(10 * time.Millisecond)
 }
+
+func TestReconfigure(t *testing.T) {
+	first, second := &bytes.Buffer{}, &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(first))()
+
+	goslogx.Info("trace-001", "mod", goslogx.MESSAGE_TYPE_EVENT, "before", nil)
+	if err := goslogx.Reconfigure(goslogx.WithOutput(second), goslogx.WithServiceName("reconfigured")); err != nil {
+		t.Fatalf("Reconfigure failed: %v", err)
+	}
+	goslogx.Info("trace-001", "mod", goslogx.MESSAGE_TYPE_EVENT, "after", nil)
+
+	if !strings.Contains(first.String(), "before") || strings.Contains(first.String(), "after") {
+		t.Errorf("Expected only the first line in the previous output, got %s", first.String())
+	}
+	if !strings.Contains(second.String(), "after") || !strings.Contains(second.String(), "reconfigured") {
+		t.Errorf("Expected the next line in the new output, got %s", second.String())
+	}
+
+	// New keeps the reconfigured logger
+	goslogx.New(goslogx.WithOutput(first))
+	goslogx.Info("trace-001", "mod", goslogx.MESSAGE_TYPE_EVENT, "after-new", nil)
+	if !strings.Contains(second.String(), "after-new") {
+		t.Errorf("Expected New not to replace the reconfigured logger, got %s", second.String())
+	}
+}
+
+func TestReconfigureConcurrent(t *testing.T) {
+	// Async output serializes writes, as bytes.Buffer isn't safe for concurrent use
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(&bytes.Buffer{}), goslogx.WithAsync(0, time.Millisecond))()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					goslogx.Info("trace-001", "mod", goslogx.MESSAGE_TYPE_EVENT, "line", nil)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		if err := goslogx.Reconfigure(goslogx.WithOutput(&bytes.Buffer{}), goslogx.WithAsync(0, time.Millisecond)); err != nil {
+			t.Fatalf("Reconfigure failed: %v", err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestNewNop(t *testing.T) {
+	buf := &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf))()
+
+	logger := goslogx.NewNop()
+	logger.Info("trace-001", "mod", goslogx.MESSAGE_TYPE_EVENT, "info", map[string]any{"password": "secret"})
+	logger.Debug("trace-001", "mod", goslogx.MESSAGE_TYPE_EVENT, "debug", nil)
+	logger.Warning("trace-001", "mod", "warning", nil)
+	logger.WarningErr("trace-001", "mod", "warning", errors.New("boom"))
+	logger.Error("trace-001", "mod", errors.New("boom"))
+	logger.With(map[string]any{"k": "v"}).Info("trace-001", "mod", goslogx.MESSAGE_TYPE_EVENT, "child", nil)
+	if err := logger.Sync(); err != nil {
+		t.Errorf("Expected Sync to succeed, got %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Errorf("Expected Close to succeed, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected the global logger to be untouched, got %s", buf.String())
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	global, own := &bytes.Buffer{}, &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(global))()
+
+	logger := goslogx.NewLogger(goslogx.WithOutput(own), goslogx.WithServiceName("standalone"))
+	if logger == goslogx.NewLogger(goslogx.WithOutput(own)) {
+		t.Error("Expected each call to return a new Logger")
+	}
+	logger.Info("trace-001", "mod", goslogx.MESSAGE_TYPE_EVENT, "standalone", nil)
+	goslogx.Info("trace-001", "mod", goslogx.MESSAGE_TYPE_EVENT, "global", nil)
+
+	if !strings.Contains(own.String(), "standalone") || strings.Contains(own.String(), `"msg":"global"`) {
+		t.Errorf("Expected only the standalone entry in its output, got %s", own.String())
+	}
+	if strings.Contains(global.String(), `"msg":"standalone"`) {
+		t.Errorf("Expected the global logger to be untouched, got %s", global.String())
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d      time.Duration
+		want   string
+		wantMs float64
+	}{
+		{0, "0s", 0},
+		{400 * time.Nanosecond, "400ns", 0.0004},
+		{850400 * time.Nanosecond, "850µs", 0.8504},
+		{125432 * time.Microsecond, "125.43ms", 125.432},
+		{2345678 * time.Microsecond, "2.346s", 2345.678},
+		{65 * time.Second, "1m5s", 65000},
+		{-1500 * time.Microsecond, "-1.5ms", -1.5},
+	}
+	for _, tt := range tests {
+		if got := goslogx.FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, expected %q", tt.d, got, tt.want)
+		}
+		if got := goslogx.DurationMillis(tt.d); got != tt.wantMs {
+			t.Errorf("DurationMillis(%v) = %v, expected %v", tt.d, got, tt.wantMs)
+		}
+	}
+}
+
+func TestDurationFields(t *testing.T) {
+	elapsed := 1500 * time.Microsecond
+	buf := &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf))()
+
+	goslogx.Info("trace-001", "db", goslogx.MESSAGE_TYPE_IN, "query executed", goslogx.DBData{
+		Operation:  "SELECT",
+		Duration:   goslogx.FormatDuration(elapsed),
+		DurationMs: goslogx.DurationMillis(elapsed),
+	})
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to unmarshal log entry: %v", err)
+	}
+	data := entry["data"].(map[string]any)
+	if data["duration"] != "1.5ms" || data["duration_ms"] != 1.5 {
+		t.Errorf("Expected string and numeric durations, got %v", data)
+	}
+}
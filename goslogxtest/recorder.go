@@ -0,0 +1,109 @@
+// Package goslogxtest records goslogx entries for assertions in tests.
+//
+// Example:
+//
+//	func TestCreateUser(t *testing.T) {
+//	    logger, rec := goslogxtest.NewRecorder()
+//	    svc := NewUserService(logger)
+//	    svc.Create(ctx, user)
+//
+//	    entry := rec.LastEntry()
+//	    if entry["msg"] != "user created" {
+//	        t.Errorf("unexpected entry: %v", entry)
+//	    }
+//	    if errs := rec.Filter("error"); len(errs) > 0 {
+//	        t.Errorf("unexpected errors: %v", errs)
+//	    }
+//	}
+package goslogxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/muhammadluth/goslogx"
+)
+
+// Recorder captures the entries written by a Logger, parsed from JSON.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	entries []map[string]any
+}
+
+// NewRecorder returns a Logger that writes to a new Recorder, with Debug
+// entries enabled. opts apply on top, except that output always goes to the
+// Recorder; the JSON encoding must be kept. The global logger is untouched.
+func NewRecorder(opts ...goslogx.Option) (*goslogx.Logger, *Recorder) {
+	rec := &Recorder{}
+	all := make([]goslogx.Option, 0, len(opts)+2)
+	all = append(all, goslogx.WithDebug(true))
+	all = append(all, opts...)
+	all = append(all, goslogx.WithOutput(rec))
+	return goslogx.NewLogger(all...), rec
+}
+
+// Write implements io.Writer, parsing each line of p as an entry.
+// Lines that are not JSON objects are ignored.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err == nil {
+			r.entries = append(r.entries, entry)
+		}
+	}
+	return len(p), nil
+}
+
+// Entries returns the entries recorded so far, oldest first.
+func (r *Recorder) Entries() []map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]map[string]any(nil), r.entries...)
+}
+
+// LastEntry returns the most recent entry, or nil if none was recorded.
+func (r *Recorder) LastEntry() map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) == 0 {
+		return nil
+	}
+	return r.entries[len(r.entries)-1]
+}
+
+// Filter returns the entries logged at level, matched case-insensitively
+// against the "level" field ("debug", "info", "warn", "error", "fatal") or
+// the "severity" field ("DEBUG", "INFO", "WARNING", "ERROR", "CRITICAL").
+func (r *Recorder) Filter(level string) []map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var matched []map[string]any
+	for _, entry := range r.entries {
+		if fieldEquals(entry, goslogx.KeyLevel, level) || fieldEquals(entry, goslogx.KeySeverity, level) {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
+
+// Reset discards the entries recorded so far.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+// fieldEquals reports whether the string field key of entry equals value,
+// ignoring case.
+func fieldEquals(entry map[string]any, key, value string) bool {
+	s, ok := entry[key].(string)
+	return ok && strings.EqualFold(s, value)
+}
@@ -0,0 +1,111 @@
+package goslogxtest_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/muhammadluth/goslogx"
+	"github.com/muhammadluth/goslogx/goslogxtest"
+)
+
+func TestRecorder(t *testing.T) {
+	logger, rec := goslogxtest.NewRecorder(goslogx.WithServiceName("recorder-test"))
+
+	if rec.LastEntry() != nil || len(rec.Entries()) != 0 {
+		t.Fatal("Expected an empty recorder")
+	}
+
+	logger.Debug("trace-001", "users", goslogx.MESSAGE_TYPE_EVENT, "cache miss", nil)
+	logger.Info("trace-002", "users", goslogx.MESSAGE_TYPE_EVENT, "user created", map[string]any{"password": "secret"})
+	logger.Warning("trace-003", "users", "slow query", nil)
+	logger.Error("trace-004", "users", errors.New("boom"))
+
+	entries := rec.Entries()
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(entries))
+	}
+	if entries[0]["msg"] != "cache miss" || entries[0]["application_name"] != "recorder-test" {
+		t.Errorf("Unexpected first entry: %v", entries[0])
+	}
+	if data := entries[1]["data"].(map[string]any); data["password"] != "****" {
+		t.Errorf("Expected masked data, got %v", data)
+	}
+	if last := rec.LastEntry(); last["trace_id"] != "trace-004" || last["error"] != "boom" {
+		t.Errorf("Unexpected last entry: %v", last)
+	}
+
+	t.Run("Filter", func(t *testing.T) {
+		for _, tt := range []struct {
+			level string
+			want  int
+		}{
+			{"debug", 1},
+			{"info", 1},
+			{"warn", 1},
+			{"WARNING", 1},
+			{"error", 1},
+			{"ERROR", 1},
+			{"fatal", 0},
+		} {
+			if got := rec.Filter(tt.level); len(got) != tt.want {
+				t.Errorf("Filter(%q): expected %d entries, got %d", tt.level, tt.want, len(got))
+			}
+		}
+		if got := rec.Filter("error"); got[0]["trace_id"] != "trace-004" {
+			t.Errorf("Expected the error entry, got %v", got[0])
+		}
+	})
+
+	t.Run("EntriesIsACopy", func(t *testing.T) {
+		entries := rec.Entries()
+		entries[0] = nil
+		if rec.Entries()[0] == nil {
+			t.Error("Expected Entries to return a copy")
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		rec.Reset()
+		if len(rec.Entries()) != 0 || rec.LastEntry() != nil {
+			t.Error("Expected Reset to discard the entries")
+		}
+		logger.Info("trace-005", "users", goslogx.MESSAGE_TYPE_EVENT, "after reset", nil)
+		if last := rec.LastEntry(); last["msg"] != "after reset" {
+			t.Errorf("Expected recording to continue after Reset, got %v", last)
+		}
+	})
+}
+
+func TestRecorderOptions(t *testing.T) {
+	logger, rec := goslogxtest.NewRecorder(goslogx.WithDebug(false))
+	logger.Debug("trace-001", "mod", goslogx.MESSAGE_TYPE_EVENT, "dropped", nil)
+	logger.Info("trace-002", "mod", goslogx.MESSAGE_TYPE_EVENT, "kept", nil)
+	if entries := rec.Entries(); len(entries) != 1 || entries[0]["msg"] != "kept" {
+		t.Errorf("Expected options to apply on top of the defaults, got %v", entries)
+	}
+}
+
+func TestRecorderConcurrent(t *testing.T) {
+	// Async output flushes several entries in one write
+	logger, rec := goslogxtest.NewRecorder(goslogx.WithAsync(0, time.Hour))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				logger.Info("trace-001", "mod", goslogx.MESSAGE_TYPE_EVENT, "line", nil)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := len(rec.Entries()); got != 400 {
+		t.Errorf("Expected 400 entries, got %d", got)
+	}
+}
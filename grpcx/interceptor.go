@@ -0,0 +1,169 @@
+// Package grpcx provides gRPC interceptors that log through goslogx.
+// It lives in its own module so that non-gRPC users of goslogx
+// do not pull in the gRPC dependency.
+//
+// Basic Usage:
+//
+//	goslogx.New(goslogx.WithServiceName("user-service"))
+//	server := grpc.NewServer(grpc.UnaryInterceptor(grpcx.UnaryServerInterceptor()))
+//
+// Services with a standalone logger pass it with WithLogger:
+//
+//	logger := goslogx.NewLogger(goslogx.WithServiceName("user-service"))
+//	server := grpc.NewServer(grpc.UnaryInterceptor(grpcx.UnaryServerInterceptor(grpcx.WithLogger(logger))))
+package grpcx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/muhammadluth/goslogx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// TraceMetadataKey is the incoming metadata key the trace ID is read from.
+const TraceMetadataKey = "x-trace-id"
+
+// module is the module name attached to every entry.
+const module = "grpc"
+
+// RPCData captures context for gRPC interactions.
+//
+// Example:
+//
+//	data := grpcx.RPCData{
+//		Method:   "/user.v1.UserService/GetUser",
+//		Code:     "OK",
+//		Duration: "12ms",
+//	}
+type RPCData struct {
+	Method     string  `json:"method,omitempty"`
+	Code       string  `json:"code,omitempty"`
+	Duration   string  `json:"duration,omitempty"`
+	DurationMs float64 `json:"duration_ms,omitempty"`
+	Peer       string  `json:"peer,omitempty"`
+	Payload    any     `json:"payload,omitempty"`
+}
+
+// Option configures UnaryServerInterceptor.
+type Option func(*options)
+
+type options struct {
+	logger logger
+}
+
+// logger is the subset of *goslogx.Logger the interceptor logs through.
+type logger interface {
+	Info(traceID string, module string, msgType goslogx.MsgType, msg string, data any)
+	Error(traceID string, module string, err error, extra ...goslogx.Field)
+}
+
+// globalLogger logs through the global goslogx logger.
+type globalLogger struct{}
+
+func (globalLogger) Info(traceID string, module string, msgType goslogx.MsgType, msg string, data any) {
+	goslogx.Info(traceID, module, msgType, msg, data)
+}
+
+func (globalLogger) Error(traceID string, module string, err error, extra ...goslogx.Field) {
+	goslogx.Error(traceID, module, err, extra...)
+}
+
+// WithLogger logs through l instead of the global logger set up by
+// goslogx.New, e.g. a standalone logger from goslogx.NewLogger.
+// A nil l keeps the global logger.
+func WithLogger(l *goslogx.Logger) Option {
+	return func(o *options) {
+		if l != nil {
+			o.logger = l
+		}
+	}
+}
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor that logs an IN
+// entry when a request is received and an OUT entry with the status code and
+// duration when the handler returns. Failed calls are logged as Error entries
+// with the method, status code, and duration in the data field, structured
+// like the OUT entry's RPCData.
+//
+// The trace ID is read from the TraceMetadataKey incoming metadata, or generated
+// when absent. Protobuf payloads are masked by field name using the same rules
+// as goslogx JSON masking.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := options{logger: globalLogger{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	log := o.logger
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		traceID := incomingTraceID(ctx)
+		peerAddr := ""
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			peerAddr = p.Addr.String()
+		}
+
+		log.Info(traceID, module, goslogx.MESSAGE_TYPE_IN, "request received", RPCData{
+			Method:  info.FullMethod,
+			Peer:    peerAddr,
+			Payload: maskPayload(req),
+		})
+
+		resp, err := handler(ctx, req)
+		elapsed := time.Since(start)
+		data := RPCData{
+			Method:     info.FullMethod,
+			Code:       status.Code(err).String(),
+			Duration:   goslogx.FormatDuration(elapsed),
+			DurationMs: goslogx.DurationMillis(elapsed),
+			Peer:       peerAddr,
+		}
+		if err != nil {
+			log.Error(traceID, module, err, goslogx.Field{Key: goslogx.KeyData, Val: data})
+			return resp, err
+		}
+
+		data.Payload = maskPayload(resp)
+		log.Info(traceID, module, goslogx.MESSAGE_TYPE_OUT, "request completed", data)
+		return resp, nil
+	}
+}
+
+// incomingTraceID returns the trace ID from incoming metadata, generating one if absent.
+func incomingTraceID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(TraceMetadataKey); len(v) > 0 && v[0] != "" {
+			return v[0]
+		}
+	}
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// maskPayload converts a protobuf message to a masked JSON object using proto
+// field names, so sensitive fields are matched the same way as JSON bodies.
+// Non-protobuf values are returned as-is and masked by goslogx.
+func maskPayload(v any) any {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return v
+	}
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+	var masked any
+	if err := json.Unmarshal([]byte(goslogx.MaskingLogJSONBytes("payload", b)), &masked); err != nil {
+		return nil
+	}
+	return masked
+}
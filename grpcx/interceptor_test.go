@@ -0,0 +1,115 @@
+package grpcx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/muhammadluth/goslogx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var buf = &bytes.Buffer{}
+
+func init() {
+	goslogx.New(goslogx.WithServiceName("grpc-test"), goslogx.WithOutput(buf))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.v1.UserService/Login"}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(TraceMetadataKey, "trace-123"))
+
+	req, _ := structpb.NewStruct(map[string]any{"username": "johndoe123", "password": "supersecret"})
+
+	t.Run("Success", func(t *testing.T) {
+		buf.Reset()
+		resp, _ := structpb.NewStruct(map[string]any{"token": "newtoken", "status": "ok"})
+		_, err := interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+			return resp, nil
+		})
+		if err != nil {
+			t.Fatalf("Interceptor returned error: %v", err)
+		}
+		out := buf.String()
+		for _, leaked := range []string{"supersecret", "johndoe123", "newtoken"} {
+			if strings.Contains(out, leaked) {
+				t.Errorf("Sensitive value %q leaked: %s", leaked, out)
+			}
+		}
+		for _, want := range []string{`"msg_type":"IN"`, `"msg_type":"OUT"`, `"trace_id":"trace-123"`, `"code":"OK"`, `"password":"****"`, `"status":"ok"`} {
+			if !strings.Contains(out, want) {
+				t.Errorf("Expected output to contain %s, got %s", want, out)
+			}
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		buf.Reset()
+		_, err := interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound to be returned unchanged, got %v", err)
+		}
+		entry := lastEntry(t, buf)
+		data, _ := entry[goslogx.KeyData].(map[string]any)
+		if entry[goslogx.KeySeverity] != "ERROR" || data["code"] != "NotFound" ||
+			data["method"] != info.FullMethod || data["duration"] == nil || data["duration_ms"] == nil {
+			t.Errorf("Expected error entry with structured status code and duration, got %v", entry)
+		}
+		if entry[goslogx.KeyError] != "rpc error: code = NotFound desc = user not found" {
+			t.Errorf("Expected the handler's error unchanged, got %v", entry[goslogx.KeyError])
+		}
+	})
+
+	t.Run("WithLogger", func(t *testing.T) {
+		buf.Reset()
+		own := &bytes.Buffer{}
+		logger := goslogx.NewLogger(goslogx.WithServiceName("own-logger"), goslogx.WithOutput(own))
+		_, _ = UnaryServerInterceptor(WithLogger(logger))(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+			return nil, status.Error(codes.Internal, "boom")
+		})
+		if buf.Len() != 0 {
+			t.Errorf("Expected nothing on the global logger, got %s", buf.String())
+		}
+		if entry := lastEntry(t, own); entry[goslogx.KeyApplicationName] != "own-logger" {
+			t.Errorf("Expected entries on the given logger, got %s", own.String())
+		}
+	})
+
+	t.Run("GeneratedTraceID", func(t *testing.T) {
+		if id := incomingTraceID(context.Background()); len(id) != 32 {
+			t.Errorf("Expected generated 32-char trace ID, got %q", id)
+		}
+	})
+}
+
+// lastEntry decodes the last JSON entry written to b.
+func lastEntry(t *testing.T, b *bytes.Buffer) map[string]any {
+	t.Helper()
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &entry); err != nil {
+		t.Fatalf("Failed to decode entry: %v (%s)", err, b.String())
+	}
+	return entry
+}
+
+func TestMaskPayload(t *testing.T) {
+	type plain struct{ Name string }
+	if v := maskPayload(plain{Name: "x"}); v != (plain{Name: "x"}) {
+		t.Errorf("Expected non-proto values to pass through, got %v", v)
+	}
+	msg, _ := structpb.NewStruct(map[string]any{"api_secret": "s"})
+	masked, ok := maskPayload(msg).(map[string]any)
+	if !ok || masked["api_secret"] != "****" {
+		t.Errorf("Expected masked proto payload, got %v", masked)
+	}
+}
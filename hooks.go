@@ -0,0 +1,51 @@
+package goslogx
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrorEntry describes an Error or Fatal entry about to be written, as
+// passed to the callbacks set via WithOnError and WithOnFatal.
+type ErrorEntry struct {
+	Level   zapcore.Level // zapcore.ErrorLevel or zapcore.FatalLevel
+	Message string        // Entry message, e.g. "error occurred"
+	TraceID string
+	Module  string
+	Err     error
+	// Fields holds the fields of the entry by key, such as "error",
+	// "error_code", and "stack_trace", without those bound with With
+	Fields map[string]any
+}
+
+// runErrorHook calls hook, if set, with the entry logged with msg and
+// fields. A panic in hook is recovered and reported as a WARNING entry, so
+// the entry is still written and Fatal still exits.
+func (l *Logger) runErrorHook(hook func(ErrorEntry), lvl zapcore.Level, msg, traceID, module string, err error, fields []zap.Field) {
+	if hook == nil {
+		return
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	for i := range fields {
+		fields[i].AddTo(enc)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			l.logger.Warn("error hook panicked",
+				zap.String(l.config.FieldKeys.TraceID, traceID),
+				zap.String(l.config.FieldKeys.Severity, l.severity(zapcore.WarnLevel)),
+				zap.String("panic", fmt.Sprint(r)),
+			)
+		}
+	}()
+	hook(ErrorEntry{
+		Level:   lvl,
+		Message: msg,
+		TraceID: traceID,
+		Module:  module,
+		Err:     err,
+		Fields:  enc.Fields,
+	})
+}
@@ -0,0 +1,82 @@
+package goslogx_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/muhammadluth/goslogx"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestOnErrorHook(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var got []goslogx.ErrorEntry
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf), goslogx.WithOnError(func(e goslogx.ErrorEntry) {
+		if buf.Len() != 0 {
+			t.Errorf("Expected the hook to run before the entry is written, got %q", buf.String())
+		}
+		got = append(got, e)
+	}))()
+
+	goslogx.Error("trace-1", "payments", errors.New("card declined"))
+	goslogx.Warning("trace-2", "payments", "retrying", nil)
+
+	if len(got) != 1 {
+		t.Fatalf("Expected the hook to run once, for the error only, got %d calls", len(got))
+	}
+	e := got[0]
+	if e.Level != zapcore.ErrorLevel || e.TraceID != "trace-1" || e.Module != "payments" || e.Err.Error() != "card declined" {
+		t.Errorf("Expected the error entry, got %+v", e)
+	}
+	if e.Fields["trace_id"] != "trace-1" || e.Fields["error"] != "card declined" {
+		t.Errorf("Expected the entry fields, got %v", e.Fields)
+	}
+	if !strings.Contains(buf.String(), `"error":"card declined"`) {
+		t.Errorf("Expected the entry to be written, got %q", buf.String())
+	}
+}
+
+func TestOnFatalHook(t *testing.T) {
+	tests := []struct {
+		name  string
+		panic bool
+	}{
+		{"Returns", false},
+		{"Panics", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			var got []goslogx.ErrorEntry
+			var codes []int
+			defer goslogx.ReplaceGlobal(
+				goslogx.WithOutput(buf),
+				goslogx.WithExitFunc(func(code int) { codes = append(codes, code) }),
+				goslogx.WithOnFatal(func(e goslogx.ErrorEntry) {
+					got = append(got, e)
+					if tt.panic {
+						panic("crash reporter unavailable")
+					}
+				}),
+			)()
+
+			goslogx.Fatal("trace-1", "main", errors.New("config missing"))
+
+			if len(got) != 1 || got[0].TraceID != "trace-1" || got[0].Err.Error() != "config missing" ||
+				got[0].Level != zapcore.FatalLevel {
+				t.Fatalf("Expected the hook to receive the fatal entry, got %+v", got)
+			}
+			if len(codes) != 1 || codes[0] != 1 {
+				t.Errorf("Expected exit with code 1 despite the hook, got %v", codes)
+			}
+			if !strings.Contains(buf.String(), `"error":"config missing"`) {
+				t.Errorf("Expected the fatal entry to be written, got %q", buf.String())
+			}
+			if panicked := strings.Contains(buf.String(), "crash reporter unavailable"); panicked != tt.panic {
+				t.Errorf("Expected the hook panic reported: %v, got %q", tt.panic, buf.String())
+			}
+		})
+	}
+}
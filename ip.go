@@ -0,0 +1,57 @@
+package goslogx
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// anonymizeIP zeroes the host part of the IP address s: the last octet of an
+// IPv4 address, including one mapped into IPv6, and the last 80 bits of an
+// IPv6 address. Zones are dropped. Values that aren't IP addresses are
+// returned unchanged.
+//
+// Examples:
+//   - "203.0.113.42" → "203.0.113.0"
+//   - "2001:db8:85a3:8d3:1319:8a2e:370:7348" → "2001:db8:85a3::"
+//   - "::ffff:203.0.113.42" → "::ffff:203.0.113.0"
+//   - "unknown" → "unknown"
+func anonymizeIP(s string) string {
+	if anonymized, ok := anonymizeRecognizedIP(s); ok {
+		return anonymized
+	}
+	return s
+}
+
+// anonymizeRecognizedIP anonymizes s as by anonymizeIP if it is an IP
+// address, and reports whether it was.
+func anonymizeRecognizedIP(s string) (string, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(s))
+	if err != nil {
+		return "", false
+	}
+	addr = addr.WithZone("")
+	bits := 48
+	switch {
+	case addr.Is4():
+		bits = 24
+	case addr.Is4In6():
+		bits = 96 + 24
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return "", false
+	}
+	return prefix.Addr().String(), true
+}
+
+// anonymizeIPValue anonymizes s as by anonymizeIP, replacing values that
+// aren't IP addresses with "****" when maskInvalid is set.
+func anonymizeIPValue(s string, maskInvalid bool) string {
+	if !maskInvalid {
+		return anonymizeIP(s)
+	}
+	if anonymized, ok := anonymizeRecognizedIP(s); ok {
+		return anonymized
+	}
+	return "****"
+}
@@ -0,0 +1,70 @@
+package goslogx
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestAnonymizeIP(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		want string
+	}{
+		{"IPv4", "203.0.113.42", "203.0.113.0"},
+		{"IPv4Zero", "10.0.0.0", "10.0.0.0"},
+		{"IPv6", "2001:db8:85a3:8d3:1319:8a2e:370:7348", "2001:db8:85a3::"},
+		{"IPv6Short", "2001:db8::1", "2001:db8::"},
+		{"IPv6Zone", "fe80::1%eth0", "fe80::"},
+		{"IPv4In6", "::ffff:203.0.113.42", "::ffff:203.0.113.0"},
+		{"Whitespace", " 203.0.113.42 ", "203.0.113.0"},
+		{"Invalid", "not-an-ip", "not-an-ip"},
+		{"HostPort", "203.0.113.42:8080", "203.0.113.42:8080"},
+		{"Empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := anonymizeIP(tt.ip); got != tt.want {
+				t.Errorf("anonymizeIP(%q) = %q, want %q", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPDataAnonymizeIP(t *testing.T) {
+	logged := func(t *testing.T, ip string, opts ...Option) any {
+		t.Helper()
+		buf := &bytes.Buffer{}
+		setupLog(append([]Option{WithOutput(buf)}, opts...)...).
+			Info("trace-1", "http", MESSAGE_TYPE_REQUEST, "request completed", HTTPData{Method: "GET", ClientIP: ip})
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to decode entry: %v", err)
+		}
+		return entry["data"].(map[string]any)["client_ip"]
+	}
+
+	tests := []struct {
+		name string
+		ip   string
+		opts []Option
+		want string
+	}{
+		{"Default", "203.0.113.42", nil, "203.0.113.42"},
+		{"IPv4", "203.0.113.42", []Option{WithAnonymizeIP(true)}, "203.0.113.0"},
+		{"IPv6", "2001:db8:85a3:8d3:1319:8a2e:370:7348", []Option{WithAnonymizeIP(true)}, "2001:db8:85a3::"},
+		{"InvalidUnchanged", "unknown", []Option{WithAnonymizeIP(true)}, "unknown"},
+		{"InvalidMasked", "unknown", []Option{WithAnonymizeIP(true), WithMaskInvalidIP(true)}, "****"},
+		{"ValidWithMaskInvalid", "203.0.113.42", []Option{WithAnonymizeIP(true), WithMaskInvalidIP(true)}, "203.0.113.0"},
+		{"Allowlist", "203.0.113.42", []Option{WithAllowFields([]string{"method"})}, "****"},
+		{"AllowlistAnonymized", "203.0.113.42", []Option{WithAllowFields([]string{"method"}), WithAnonymizeIP(true)}, "203.0.113.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := logged(t, tt.ip, tt.opts...); got != tt.want {
+				t.Errorf("Expected client_ip %q, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -1,5 +1,14 @@
 package goslogx
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"go.uber.org/zap/zapcore"
+)
+
 // MaskingLogJSONBytes parses a JSON byte slice and masks sensitive fields based on field names.
 // It automatically detects and masks fields containing credentials, tokens, and personal information.
 //
@@ -9,6 +18,9 @@ package goslogx
 //
 // Returns the original string if the input is not valid JSON.
 //
+// Bodies logged in HTTPData, DBData, MQData, or GenericData are masked
+// automatically; use this for JSON logged elsewhere, e.g. through another logger.
+//
 // Example:
 //
 //	body := []byte(`{"username":"admin@example.com","password":"secret123"}`)
@@ -18,15 +30,97 @@ func MaskingLogJSONBytes(key string, data []byte) string {
 	return maskJSONString(string(data))
 }
 
+// MaskData returns a masked copy of v, for handing a payload to another
+// logging or tracing system. It applies the same rules, and the global
+// logger's masking configuration, as the data argument of Info: structs
+// honor their log:"masked:*" tags and maps are masked by key name.
+//
+// Structs become map[string]any, slices and arrays []any, and maps with
+// string keys map[string]any; other values are returned as is. v itself is
+// never modified. Nil maps, slices, and pointers are safe to pass; a nil v or
+// nil pointer returns nil.
+//
+// Example:
+//
+//	masked := goslogx.MaskData(User{Username: "johndoe", Password: "secret"})
+//	// map[string]any{"username": "jo****oe", "password": "****"}
+//	span.SetAttributes(attribute.String("user", fmt.Sprint(masked)))
+func MaskData(v any) any {
+	const key = "data"
+	f := maskedField(key, v, globalMaskingConfig())
+	switch f.Type {
+	case zapcore.SkipType:
+		return nil
+	case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType:
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		return enc.Fields[key]
+	}
+	// Nothing to mask
+	return v
+}
+
+// MaskJSONStream masks sensitive fields of the JSON document read from src
+// and writes the result to dst, like MaskingLogJSONBytes but without holding
+// the whole document in memory: output is written incrementally as the input
+// is read. Numbers keep their exact literal.
+//
+// Unlike MaskingLogJSONBytes, invalid JSON is reported as an error rather
+// than passed through, and dst may already hold part of the output by then.
+//
+// Example:
+//
+//	resp, err := http.Get(url)
+//	if err != nil {
+//	    return err
+//	}
+//	defer resp.Body.Close()
+//	var masked bytes.Buffer
+//	if err := goslogx.MaskJSONStream(&masked, resp.Body); err != nil {
+//	    return err
+//	}
+func MaskJSONStream(dst io.Writer, src io.Reader) error {
+	return maskJSONStream(dst, src, globalMaskingConfig())
+}
+
+// SanitizeJSON returns data masked like MaskingLogJSONBytes and indented with
+// two spaces, for debugging endpoints and developer tools that show a
+// sanitized payload rather than log it. Unlike MaskingLogJSONBytes, invalid
+// JSON is reported as an error rather than passed through.
+//
+// Example:
+//
+//	sanitized, err := goslogx.SanitizeJSON(body)
+//	if err != nil {
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return
+//	}
+//	w.Header().Set("Content-Type", "application/json")
+//	w.Write(sanitized)
+func SanitizeJSON(data []byte) ([]byte, error) {
+	var masked bytes.Buffer
+	if err := maskJSONStream(&masked, bytes.NewReader(data), globalMaskingConfig()); err != nil {
+		return nil, fmt.Errorf("goslogx: invalid JSON: %w", err)
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, masked.Bytes(), "", prettyIndent); err != nil {
+		return nil, fmt.Errorf("goslogx: invalid JSON: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
 // MaskingLogHttpHeaders masks sensitive values in HTTP headers.
 // It detects sensitive headers like Authorization, Cookie, API keys and masks their values.
 //
 // Masking behavior:
-//   - Full masking (****): Authorization, Bearer, Cookie
+//   - Full masking (****): Authorization, Bearer
 //   - Partial masking (first/last 2 chars): X-API-Key, API-Key
+//   - Cookie values (session=****; HttpOnly): Cookie, Set-Cookie
 //
 // Returns a new map with masked values. Non-sensitive headers are returned unchanged.
 //
+// HTTPData.Headers are masked automatically; use this for headers logged elsewhere.
+//
 // Example:
 //
 //	headers := map[string][]string{
@@ -36,7 +130,7 @@ func MaskingLogJSONBytes(key string, data []byte) string {
 //	masked := goslogx.MaskingLogHttpHeaders("headers", headers)
 //	// Result: {"Authorization": ["****"], "Content-Type": ["application/json"]}
 func MaskingLogHttpHeaders(key string, data map[string][]string) map[string][]string {
-	return maskHttpHeaders(data)
+	return maskHttpHeaders(data, globalMaskingConfig())
 }
 
 // MaskingLogJSONString parses a JSON string and masks sensitive fields based on field names.
@@ -52,3 +146,65 @@ func MaskingLogHttpHeaders(key string, data map[string][]string) map[string][]st
 func MaskingLogJSONString(key string, data string) string {
 	return maskJSONString(data)
 }
+
+// MaskValue masks a single value for use in a free-form message. Emails keep
+// their domain, payment card numbers their last 4 digits, and phone numbers
+// their country code and last 2 digits; any other value keeps its first and
+// last 2 characters, or is fully masked if it has 4 or fewer.
+//
+// Example:
+//
+//	goslogx.Info(traceID, "auth", goslogx.MESSAGE_TYPE_EVENT,
+//	    "reset link sent to "+goslogx.MaskValue(email), nil)
+//	// reset link sent to jo****@example.com
+func MaskValue(s string) string {
+	if masked, ok := maskRecognizedValue(s); ok {
+		return masked
+	}
+	return maskMiddle(s)
+}
+
+// MaskField masks value the way a field called name is masked in JSON
+// bodies, maps, and headers: fully for credentials such as password or token,
+// partially for identifiers such as email or phone, and not at all for other
+// names. It applies the built-in name patterns only, not a logger's
+// configuration such as WithAllowFields.
+//
+// Example:
+//
+//	goslogx.MaskField("password", "hunter2")   // ****
+//	goslogx.MaskField("username", "johndoe")   // jo****oe
+//	goslogx.MaskField("status", "active")      // active
+func MaskField(name, value string) string {
+	return maskString(value, shouldMaskField(name))
+}
+
+// RawValue wraps a value that is logged without any masking.
+// Create one with Raw.
+type RawValue struct {
+	v any
+}
+
+// Raw marks v to be logged as-is, bypassing all automatic masking:
+// struct tags, sensitive field names, and JSON body masking are ignored for v
+// and everything nested inside it.
+//
+// Security: only use Raw for values known to be safe, such as a field whose
+// name looks sensitive but whose content is public. Anything wrapped in Raw is
+// written to the log verbatim, so never wrap user input, request bodies, or
+// structs that may carry credentials.
+//
+// Example:
+//
+//	goslogx.Info(traceID, "auth", goslogx.MESSAGE_TYPE_EVENT, "token issued",
+//	    goslogx.Raw(map[string]string{"public_token": "pk_live_123"}))
+//	// Result: {"public_token":"pk_live_123"}
+func Raw(v any) RawValue {
+	return RawValue{v: v}
+}
+
+// MarshalJSON implements json.Marshaler so that Raw values are emitted
+// unchanged even when logged through a reflection-based path.
+func (r RawValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.v)
+}
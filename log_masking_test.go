@@ -1,6 +1,12 @@
 package goslogx
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -123,6 +129,65 @@ func TestMaskingLogHttpHeaders(t *testing.T) {
 	}
 }
 
+func TestMaskCookieHeaders(t *testing.T) {
+	headers := map[string][]string{
+		"Cookie":     {"session=abc123; theme=dark"},
+		"Set-Cookie": {"session=abc123; Path=/; Max-Age=3600; HttpOnly; Secure", "flag; Secure"},
+	}
+	want := map[string][]string{
+		"Cookie":     {"session=****; theme=****"},
+		"Set-Cookie": {"session=****; Path=/; Max-Age=3600; HttpOnly; Secure", "****; Secure"},
+	}
+	if got := MaskingLogHttpHeaders("headers", headers); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected cookie values masked\n got: %v\nwant: %v", got, want)
+	}
+
+	buf := &bytes.Buffer{}
+	setupLog(WithOutput(buf)).Info("trace-1", "http", MESSAGE_TYPE_RESPONSE, "response", HTTPData{Headers: headers})
+	var entry struct {
+		Data struct {
+			Headers map[string][]string `json:"headers"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to decode entry: %v", err)
+	}
+	if !reflect.DeepEqual(entry.Data.Headers, want) {
+		t.Errorf("Expected cookie values masked in HTTPData\n got: %v\nwant: %v", entry.Data.Headers, want)
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	buf := &bytes.Buffer{}
+	defer ReplaceGlobal(WithOutput(buf), WithRedactHeaders([]string{"cookie"}))()
+
+	headers := map[string][]string{
+		"Authorization": {"Bearer token123"},
+		"Cookie":        {"session=abc"},
+		"Content-Type":  {"application/json"},
+	}
+	want := map[string][]string{
+		"Authorization": {"****"},
+		"Content-Type":  {"application/json"},
+	}
+	if got := MaskingLogHttpHeaders("headers", headers); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected Cookie dropped and Authorization masked, got %v", got)
+	}
+
+	Info("trace-1", "http", MESSAGE_TYPE_REQUEST, "request", HTTPData{Method: "GET", Headers: headers})
+	var entry struct {
+		Data struct {
+			Headers map[string][]string `json:"headers"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to decode entry: %v", err)
+	}
+	if !reflect.DeepEqual(entry.Data.Headers, want) {
+		t.Errorf("Expected Cookie dropped from HTTPData headers, got %v", entry.Data.Headers)
+	}
+}
+
 func TestMaskingLogJSONString(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -159,3 +224,307 @@ func TestMaskingLogJSONString(t *testing.T) {
 		})
 	}
 }
+
+func TestRaw(t *testing.T) {
+	type credentials struct {
+		Username string `json:"username" log:"masked:partial"`
+		Password string `json:"password" log:"masked:full"`
+	}
+	type envelope struct {
+		Masked credentials `json:"masked"`
+		Raw    RawValue    `json:"raw"`
+	}
+
+	buf := &bytes.Buffer{}
+	logger := setupLog(WithOutput(buf))
+	creds := credentials{Username: "johndoe123", Password: "supersecret"}
+
+	decode := func() map[string]any {
+		t.Helper()
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to unmarshal log entry: %v (%s)", err, buf.String())
+		}
+		buf.Reset()
+		return entry["data"].(map[string]any)
+	}
+
+	t.Run("TopLevel", func(t *testing.T) {
+		logger.Info("trace-001", "auth", MESSSAGE_TYPE_EVENT, "raw", Raw(creds))
+		data := decode()
+		if data["username"] != "johndoe123" || data["password"] != "supersecret" {
+			t.Errorf("Expected Raw struct to be unmasked, got %v", data)
+		}
+	})
+
+	t.Run("NestedField", func(t *testing.T) {
+		logger.Info("trace-001", "auth", MESSSAGE_TYPE_EVENT, "raw", envelope{Masked: creds, Raw: Raw(creds)})
+		data := decode()
+		if masked := data["masked"].(map[string]any); masked["password"] != "****" {
+			t.Errorf("Expected sibling struct to stay masked, got %v", masked)
+		}
+		if raw := data["raw"].(map[string]any); raw["password"] != "supersecret" {
+			t.Errorf("Expected Raw field to be unmasked, got %v", raw)
+		}
+	})
+
+	t.Run("ReflectionPath", func(t *testing.T) {
+		logger.Warning("trace-001", "auth", "raw", Raw(map[string]string{"password": "visible"}))
+		data := decode()
+		if data["password"] != "visible" {
+			t.Errorf("Expected Raw value to marshal as its content, got %v", data)
+		}
+	})
+}
+
+func TestMaskValue(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"123", "****"},
+		{"12345", "12****45"},
+		{"johndoe", "jo****oe"},
+		{"", "****"},
+		{"john.doe@example.com", "jo****@example.com"},
+		{"jd@example.com", "****@example.com"},
+		{"4111 1111 1111 1111", "****1111"},
+		{"+6281234567890", "+62********90"},
+	}
+
+	for _, tt := range tests {
+		if result := MaskValue(tt.input); result != tt.expected {
+			t.Errorf("MaskValue(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestMaskField(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"password", "hunter2", "****"},
+		{"Authorization", "Bearer abc", "****"},
+		{"username", "johndoe", "jo****oe"},
+		{"email", "john.doe@example.com", "jo****om"},
+		{"status", "active", "active"},
+		{"", "johndoe", "johndoe"},
+	}
+
+	for _, tt := range tests {
+		if result := MaskField(tt.name, tt.value); result != tt.expected {
+			t.Errorf("MaskField(%q, %q) = %q, expected %q", tt.name, tt.value, result, tt.expected)
+		}
+	}
+}
+
+func BenchmarkMaskValue(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = MaskValue("john.doe@example.com")
+	}
+}
+
+// chunkWriter records the size of each write.
+type chunkWriter struct {
+	bytes.Buffer
+	writes []int
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, len(p))
+	return w.Buffer.Write(p)
+}
+
+func TestMaskJSONStream(t *testing.T) {
+	t.Run("MatchesMaskingLogJSONString", func(t *testing.T) {
+		input := `{"user":{"username":"johndoe","password":"secret"},"ids":[1,2.50,12345678901234567890],"tags":["a",null,true]}`
+		var out bytes.Buffer
+		if err := MaskJSONStream(&out, strings.NewReader(input)); err != nil {
+			t.Fatalf("MaskJSONStream failed: %v", err)
+		}
+		if expected := MaskingLogJSONString("data", input); out.String() != expected {
+			t.Errorf("Expected %s, got %s", expected, out.String())
+		}
+		if !strings.Contains(out.String(), "12345678901234567890") || !strings.Contains(out.String(), "2.50") {
+			t.Errorf("Expected number literals to be preserved, got %s", out.String())
+		}
+	})
+
+	t.Run("LargeBody", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		go func() {
+			pw.Write([]byte("["))
+			for i := 0; i < 50000; i++ {
+				if i > 0 {
+					pw.Write([]byte(","))
+				}
+				fmt.Fprintf(pw, `{"id":%d,"email":"user%d@example.com","password":"secret-%d","note":"%s"}`,
+					i, i, i, strings.Repeat("x", 40))
+			}
+			pw.Write([]byte("]"))
+			pw.Close()
+		}()
+
+		out := &chunkWriter{}
+		if err := MaskJSONStream(out, pr); err != nil {
+			t.Fatalf("MaskJSONStream failed: %v", err)
+		}
+		if out.Len() < 4<<20 {
+			t.Fatalf("Expected a multi-megabyte output, got %d bytes", out.Len())
+		}
+		if len(out.writes) < 2 {
+			t.Errorf("Expected output to be written incrementally, got %d write(s)", len(out.writes))
+		}
+		for _, n := range out.writes {
+			if n > 2*jsonStreamFlushSize {
+				t.Errorf("Expected writes of about %d bytes, got one of %d", jsonStreamFlushSize, n)
+				break
+			}
+		}
+
+		var entries []map[string]any
+		if err := json.Unmarshal(out.Bytes(), &entries); err != nil {
+			t.Fatalf("Expected valid JSON output: %v", err)
+		}
+		if len(entries) != 50000 {
+			t.Fatalf("Expected 50000 entries, got %d", len(entries))
+		}
+		for _, e := range []map[string]any{entries[0], entries[49999]} {
+			if e["password"] != "****" {
+				t.Errorf("Expected password to be masked, got %v", e["password"])
+			}
+			if email := e["email"].(string); strings.Contains(email, "@example.com") {
+				t.Errorf("Expected email to be masked, got %s", email)
+			}
+		}
+	})
+
+	t.Run("InvalidJSON", func(t *testing.T) {
+		for _, input := range []string{``, `{"password":`, `{"a":1}{"b":2}`, `not json`} {
+			if err := MaskJSONStream(io.Discard, strings.NewReader(input)); err == nil {
+				t.Errorf("Expected an error for %q", input)
+			}
+		}
+	})
+}
+
+func TestSanitizeJSON(t *testing.T) {
+	t.Run("NestedJSON", func(t *testing.T) {
+		input := `{"user":{"username":"johndoe","password":"secret","roles":["admin"]},"amount":12.50,"items":[{"token":"abc123","sku":"A-1"}],"empty":{}}`
+		out, err := SanitizeJSON([]byte(input))
+		if err != nil {
+			t.Fatalf("SanitizeJSON failed: %v", err)
+		}
+		expected := `{
+  "user": {
+    "username": "jo****oe",
+    "password": "****",
+    "roles": [
+      "admin"
+    ]
+  },
+  "amount": 12.50,
+  "items": [
+    {
+      "token": "****",
+      "sku": "A-1"
+    }
+  ],
+  "empty": {}
+}`
+		if string(out) != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, out)
+		}
+	})
+
+	t.Run("InvalidJSON", func(t *testing.T) {
+		for _, input := range []string{``, `{"password":"secret"`, `{"a":1}{"b":2}`, `not json`} {
+			out, err := SanitizeJSON([]byte(input))
+			if err == nil {
+				t.Errorf("Expected an error for %q, got %s", input, out)
+			}
+			if out != nil {
+				t.Errorf("Expected no output for %q, got %s", input, out)
+			}
+		}
+	})
+}
+
+func TestMaskData(t *testing.T) {
+	type account struct {
+		Username string `json:"username" log:"masked:partial"`
+		Password string `json:"password" log:"masked:full"`
+	}
+
+	t.Run("Struct", func(t *testing.T) {
+		in := account{Username: "johndoe", Password: "secret"}
+		got, ok := MaskData(in).(map[string]any)
+		if !ok || got["username"] != "jo****oe" || got["password"] != "****" {
+			t.Errorf("Expected masked struct fields, got %#v", MaskData(in))
+		}
+		if in.Password != "secret" {
+			t.Error("Expected the input to be left untouched")
+		}
+	})
+
+	t.Run("Pointer", func(t *testing.T) {
+		got, ok := MaskData(&account{Password: "secret"}).(map[string]any)
+		if !ok || got["password"] != "****" {
+			t.Errorf("Expected masked struct fields, got %#v", got)
+		}
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		in := map[string]any{
+			"password": "secret",
+			"nested":   map[string]any{"token": "abc"},
+			"status":   "ok",
+		}
+		got, ok := MaskData(in).(map[string]any)
+		if !ok || got["password"] != "****" || got["status"] != "ok" {
+			t.Fatalf("Expected masked map values, got %#v", MaskData(in))
+		}
+		if nested := got["nested"].(map[string]any); nested["token"] != "****" {
+			t.Errorf("Expected nested map to be masked, got %#v", nested)
+		}
+		if in["password"] != "secret" || in["nested"].(map[string]any)["token"] != "abc" {
+			t.Errorf("Expected the input to be left untouched, got %#v", in)
+		}
+	})
+
+	t.Run("Slice", func(t *testing.T) {
+		got, ok := MaskData([]account{{Password: "secret"}}).([]any)
+		if !ok || len(got) != 1 || got[0].(map[string]any)["password"] != "****" {
+			t.Errorf("Expected masked slice elements, got %#v", got)
+		}
+	})
+
+	t.Run("Primitive", func(t *testing.T) {
+		if got := MaskData(42); got != 42 {
+			t.Errorf("Expected 42, got %#v", got)
+		}
+		if got := MaskData("plain"); got != "plain" {
+			t.Errorf("Expected plain, got %#v", got)
+		}
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		var (
+			ptr   *account
+			m     map[string]any
+			slice []account
+		)
+		if got := MaskData(nil); got != nil {
+			t.Errorf("Expected nil, got %#v", got)
+		}
+		if got := MaskData(ptr); got != nil {
+			t.Errorf("Expected nil for a nil pointer, got %#v", got)
+		}
+		// Must not panic
+		MaskData(m)
+		MaskData(slice)
+	})
+}
@@ -0,0 +1,206 @@
+package goslogx
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// logfmtPool reuses output buffers for logfmt entries.
+var logfmtPool = buffer.NewPool()
+
+// logfmtEncoder renders entries as logfmt lines, such as
+// `level=info time=2024-01-02T03:04:05Z msg="order placed" data.id=42`.
+// Like consoleEncoder, it lets an embedded JSON encoder build the entry and
+// rewrites the result, so masking applies exactly as it does for JSON
+// output. Nested objects and arrays are flattened into dot-separated keys,
+// with array elements keyed by their index.
+type logfmtEncoder struct {
+	zapcore.Encoder        // JSON encoder that builds the whole entry
+	stackKey        string // Key whose value is compacted as a stack trace
+	compactStack    bool   // Compact the stack trace with formatStackTraceBytes
+}
+
+// newLogfmtEncoder creates a logfmtEncoder from the logger's encoder config.
+// With multilineStack, the stack trace is kept as a quoted value with its
+// newlines escaped, since a logfmt entry must stay on one line.
+func newLogfmtEncoder(cfg zapcore.EncoderConfig, multilineStack bool) *logfmtEncoder {
+	cfg.LineEnding = ""
+	return &logfmtEncoder{
+		Encoder:      zapcore.NewJSONEncoder(cfg),
+		stackKey:     cfg.StacktraceKey,
+		compactStack: !multilineStack,
+	}
+}
+
+// Clone implements zapcore.Encoder.
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	return &logfmtEncoder{
+		Encoder:      e.Encoder.Clone(),
+		stackKey:     e.stackKey,
+		compactStack: e.compactStack,
+	}
+}
+
+// EncodeEntry implements zapcore.Encoder.
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	inner, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer inner.Free()
+
+	dec := json.NewDecoder(bytes.NewReader(inner.Bytes()))
+	dec.UseNumber()
+	w := logfmtWriter{line: logfmtPool.Get(), dec: dec, enc: e}
+	// Opening brace
+	if _, err := dec.Token(); err != nil {
+		w.line.Free()
+		return nil, err
+	}
+	if err := w.object(""); err != nil {
+		w.line.Free()
+		return nil, err
+	}
+	w.line.AppendByte('\n')
+	return w.line, nil
+}
+
+// logfmtWriter writes the members of a JSON entry read from dec to line as
+// logfmt pairs.
+type logfmtWriter struct {
+	line *buffer.Buffer
+	dec  *json.Decoder
+	enc  *logfmtEncoder
+}
+
+// object writes the members of the object whose opening brace was just
+// read, with their keys following prefix.
+func (w *logfmtWriter) object(prefix string) error {
+	empty := true
+	for w.dec.More() {
+		tok, err := w.dec.Token()
+		if err != nil {
+			return err
+		}
+		if err := w.value(prefix + tok.(string)); err != nil {
+			return err
+		}
+		empty = false
+	}
+	// Closing brace
+	if _, err := w.dec.Token(); err != nil {
+		return err
+	}
+	if empty && prefix != "" {
+		// Keep the key of an empty object rather than dropping it
+		w.pair(prefix[:len(prefix)-1], "{}")
+	}
+	return nil
+}
+
+// array writes the elements of the array whose opening bracket was just
+// read, keyed by path and their index.
+func (w *logfmtWriter) array(path string) error {
+	i := 0
+	for ; w.dec.More(); i++ {
+		if err := w.value(path + "." + strconv.Itoa(i)); err != nil {
+			return err
+		}
+	}
+	// Closing bracket
+	if _, err := w.dec.Token(); err != nil {
+		return err
+	}
+	if i == 0 {
+		w.pair(path, "[]")
+	}
+	return nil
+}
+
+// value writes the next JSON value under path, flattening objects and arrays.
+func (w *logfmtWriter) value(path string) error {
+	tok, err := w.dec.Token()
+	if err != nil {
+		return err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			return w.object(path + ".")
+		}
+		return w.array(path)
+	case string:
+		if path == w.enc.stackKey && w.enc.compactStack {
+			var buf bytes.Buffer
+			formatStackTraceBytes(&buf, t)
+			t = buf.String()
+		}
+		w.pair(path, t)
+	case json.Number:
+		w.pair(path, t.String())
+	case bool:
+		w.pair(path, strconv.FormatBool(t))
+	default:
+		w.pair(path, "null")
+	}
+	return nil
+}
+
+// pair writes " key=value", or "key=value" at the start of the line.
+func (w *logfmtWriter) pair(key, value string) {
+	if w.line.Len() > 0 {
+		w.line.AppendByte(' ')
+	}
+	appendLogfmtKey(w.line, key)
+	w.line.AppendByte('=')
+	appendLogfmtValue(w.line, value)
+}
+
+// appendLogfmtKey writes key with the characters logfmt does not allow in
+// keys, spaces, '=', '"', and control characters, replaced with '_'.
+func appendLogfmtKey(line *buffer.Buffer, key string) {
+	if key == "" {
+		line.AppendByte('_')
+		return
+	}
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c <= ' ' || c == '=' || c == '"' || c == 0x7f {
+			line.AppendByte('_')
+		} else {
+			line.AppendByte(c)
+		}
+	}
+}
+
+// appendLogfmtValue writes s bare, or as a JSON-escaped quoted string when it
+// is empty or contains spaces, '=', quotes, backslashes, control characters,
+// or invalid UTF-8.
+func appendLogfmtValue(line *buffer.Buffer, s string) {
+	if !needsLogfmtQuotes(s) {
+		line.AppendString(s)
+		return
+	}
+	enc := json.NewEncoder(line)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	// Encode ends the value with a newline
+	line.TrimNewline()
+}
+
+// needsLogfmtQuotes reports whether s must be quoted as a logfmt value.
+func needsLogfmtQuotes(s string) bool {
+	if s == "" {
+		return true
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c <= ' ' || c == '=' || c == '"' || c == '\\' || c == 0x7f {
+			return true
+		}
+	}
+	return !utf8.ValidString(s)
+}
@@ -0,0 +1,122 @@
+package goslogx
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// parseLogfmt splits a logfmt line into its pairs, unquoting quoted values,
+// and fails the test if the line is malformed.
+func parseLogfmt(t *testing.T, line string) map[string]string {
+	t.Helper()
+	pairs := make(map[string]string)
+	rest := strings.TrimSuffix(line, "\n")
+	for rest != "" {
+		key, after, ok := strings.Cut(rest, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \"") {
+			t.Fatalf("Malformed logfmt at %q in %q", rest, line)
+		}
+		var value string
+		if strings.HasPrefix(after, `"`) {
+			dec := json.NewDecoder(strings.NewReader(after))
+			if err := dec.Decode(&value); err != nil {
+				t.Fatalf("Malformed quoted value for %s in %q: %v", key, line, err)
+			}
+			after = after[dec.InputOffset():]
+		} else {
+			value, after, _ = strings.Cut(after, " ")
+			after = " " + after
+		}
+		if _, dup := pairs[key]; dup {
+			t.Fatalf("Duplicate key %s in %q", key, line)
+		}
+		pairs[key] = value
+		rest = strings.TrimPrefix(after, " ")
+		if rest == after && rest != "" {
+			t.Fatalf("Missing separator after %s in %q", key, line)
+		}
+	}
+	return pairs
+}
+
+func TestLogfmtEncoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := setupLog(WithOutput(buf), WithServiceName("checkout"), WithEncoder(EncodingLogfmt))
+	logger.Info("trace-001", "auth", MESSAGE_TYPE_REQUEST, `login "attempt" a=b`, map[string]any{
+		"password": "supersecret",
+		"user":     map[string]any{"email": "john.doe@example.com", "roles": []string{"admin", "ops"}},
+		"note":     "two words",
+		"tags":     []string{},
+	})
+
+	line := buf.String()
+	if strings.Count(line, "\n") != 1 || !strings.HasSuffix(line, "\n") {
+		t.Fatalf("Expected a single line, got %q", line)
+	}
+	if strings.Contains(line, "supersecret") {
+		t.Errorf("Expected the password masked, got %q", line)
+	}
+	pairs := parseLogfmt(t, line)
+	want := map[string]string{
+		KeyLevel:            "info",
+		KeyMessage:          `login "attempt" a=b`,
+		KeyApplicationName:  "checkout",
+		KeyTraceID:          "trace-001",
+		KeyModule:           "auth",
+		"data.password":     "****",
+		"data.user.email":   "jo****om",
+		"data.user.roles.0": "admin",
+		"data.user.roles.1": "ops",
+		"data.note":         "two words",
+		"data.tags":         "[]",
+	}
+	for k, v := range want {
+		if pairs[k] != v {
+			t.Errorf("Expected %s=%q, got %q in %q", k, v, pairs[k], line)
+		}
+	}
+	if pairs[KeyTime] == "" {
+		t.Errorf("Expected a %s pair, got %q", KeyTime, line)
+	}
+
+	t.Run("Quoting", func(t *testing.T) {
+		tests := []struct {
+			value string
+			want  string
+		}{
+			{"plain", "plain"},
+			{"", `""`},
+			{"two words", `"two words"`},
+			{"a=b", `"a=b"`},
+			{`say "hi"`, `"say \"hi\""`},
+			{`C:\tmp`, `"C:\\tmp"`},
+			{"line\nbreak", `"line\nbreak"`},
+			{"<tag>&", "<tag>&"},
+		}
+		for _, tt := range tests {
+			line := logfmtPool.Get()
+			appendLogfmtValue(line, tt.value)
+			if got := line.String(); got != tt.want {
+				t.Errorf("appendLogfmtValue(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+			line.Free()
+		}
+	})
+
+	t.Run("StackTrace", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf), WithEncoder(EncodingLogfmt))
+		logger.Error("trace-001", "auth", pkgerrors.New("boom"))
+		line := buf.String()
+		if strings.Count(line, "\n") != 1 {
+			t.Fatalf("Expected a single line, got %q", line)
+		}
+		if pairs := parseLogfmt(t, line); pairs[KeyStackTrace] == "" || pairs[KeyError] != "boom" {
+			t.Errorf("Expected stack trace and error pairs, got %q", line)
+		}
+	})
+}
@@ -1,11 +1,23 @@
 package goslogx
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
 	"reflect"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -27,6 +39,22 @@ var (
 	}
 )
 
+// defaultMaxDepth is the default nesting limit for masking traversal.
+const defaultMaxDepth = 32
+
+// maxDepthPlaceholder replaces values nested deeper than MaskingConfig.MaxDepth.
+const maxDepthPlaceholder = "<max-depth>"
+
+// truncatedKey marks a map cut short after MaskingConfig.MaxFields keys.
+const truncatedKey = "<truncated>"
+
+// cyclePlaceholder replaces a pointer that refers back to a struct already
+// being marshaled higher up the current path.
+const cyclePlaceholder = "<cycle>"
+
+// defaultMaskingConfig is used when no logger configuration is available.
+var defaultMaskingConfig = MaskingConfig{Enabled: true, MaxDepth: defaultMaxDepth}
+
 // structMetaCache caches struct metadata to avoid repeated reflection.
 // Key: reflect.Type, Value: *structMeta
 var structMetaCache sync.Map
@@ -36,21 +64,69 @@ var structMetaCache sync.Map
 // with automatic masking of sensitive fields tagged with log:"masked:*".
 //
 // Supports:
-//   - Nested structs (unlimited depth)
-//   - Pointer types (nil-safe)
+//   - Nested structs (up to MaskingConfig.MaxDepth levels)
+//   - Pointer types (nil-safe; reference cycles are logged as "<cycle>")
 //   - All basic Go types (int, uint, float, bool, string)
 //   - Special handling for time.Time
+//   - Byte slices and arrays implementing fmt.Stringer, such as net.IP and
+//     uuid.UUID, logged by their String form
 //   - Maps and slices (via reflection)
 //
+// A log:"masked:none" (or log:"nomask") tag disables masking for a field,
+// overriding any masking that its name would otherwise trigger.
+// A log:"masked:scan" tag masks string values, or the string elements of a
+// []any, that look like emails, card numbers, or phone numbers, and
+// log:"masked:text" masks them within free-form text, such as
+// FieldError.Message. log:"masked:sql" scrubs literals from a SQL statement when
+// MaskingConfig.MaskSQLLiterals is set, and adds a <name>_normalized copy when
+// MaskingConfig.NormalizeSQL is set. log:"masked:ip" anonymizes an IP address
+// when MaskingConfig.AnonymizeIP is set, and log:"masked:headers" drops the
+// entries of a header map listed in MaskingConfig.RedactHeaders.
+//
 // Example:
 //
 //	type User struct {
 //	    Email string `json:"email" log:"masked:partial"`
 //	    Password string `json:"password" log:"masked:full"`
+//	    Contact string `json:"contact" log:"masked:email"`
+//	    Mobile string `json:"mobile" log:"masked:phone"`
+//	    PublicToken string `json:"public_token" log:"masked:none"`
 //	}
 //	// Automatically masks when logged via goslogx.Info()
 type maskedObject struct {
-	v any
+	v       any
+	cfg     *MaskingConfig   // Masking configuration; nil means defaultMaskingConfig
+	depth   int              // Nesting depth of v, starting at 0
+	visited map[uintptr]bool // Struct pointers on the current path, for cycle detection
+	count   *maskCounter     // Counts masked values, see MaskingConfig.EmitMaskCount; nil when off
+}
+
+// config returns the masking configuration, falling back to the defaults.
+func (m maskedObject) config() *MaskingConfig {
+	if m.cfg == nil {
+		return &defaultMaskingConfig
+	}
+	return m.cfg
+}
+
+// canNest reports whether a child of m may be traversed without exceeding MaxDepth.
+func (m maskedObject) canNest() bool {
+	return m.depth+1 < m.config().depthLimit()
+}
+
+// child wraps a nested value one level deeper than m.
+func (m maskedObject) child(v any) maskedObject {
+	return maskedObject{v: v, cfg: m.cfg, depth: m.depth + 1, visited: m.visited, count: m.count}
+}
+
+// childArray wraps a nested slice or array one level deeper than m.
+func (m maskedObject) childArray(v reflect.Value) maskedArray {
+	return maskedArray{v: v, cfg: m.cfg, depth: m.depth + 1, visited: m.visited, count: m.count}
+}
+
+// childMap wraps a nested map one level deeper than m.
+func (m maskedObject) childMap(v reflect.Value) maskedMap {
+	return maskedMap{v: v, cfg: m.cfg, depth: m.depth + 1, visited: m.visited, count: m.count}
 }
 
 // MarshalLogObject implements zapcore.ObjectMarshaler.
@@ -63,8 +139,20 @@ func (m maskedObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 		if rv.IsNil() {
 			return nil
 		}
+		// Track the pointer while its fields are marshaled so that
+		// descendants pointing back to it are reported as cycles
+		ptr := rv.Pointer()
+		if m.visited == nil {
+			m.visited = make(map[uintptr]bool)
+		}
+		m.visited[ptr] = true
+		defer delete(m.visited, ptr)
 		rv = rv.Elem()
 	}
+	// The built-in DTOs marshal their known fields without reflection
+	if d, ok := m.v.(dtoMarshaler); ok {
+		return d.marshalMasked(enc, m)
+	}
 	// For non-struct types, we need special handling
 	// This happens when dataField wraps slices/maps in maskedObject
 	if rv.Kind() != reflect.Struct {
@@ -74,9 +162,42 @@ func (m maskedObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	}
 	// Get cached metadata (zero reflection after first call)
 	meta := getStructMeta(rv.Type())
+	cfg := m.config()
 	// Marshal each field
 	for _, f := range meta.fields {
-		fv := rv.Field(f.index)
+		if cfg.redacts(f.name) {
+			m.count.add()
+			continue
+		}
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok {
+			// Promoted through a nil embedded pointer
+			continue
+		}
+		if cfg.FieldMasker != nil && fv.CanInterface() {
+			if masked, ok := cfg.FieldMasker(f.name, fv.Interface()); ok {
+				m.count.add()
+				zap.Any(f.name, masked).AddTo(enc)
+				continue
+			}
+		}
+		// Skip empty values the same way encoding/json does
+		if f.omitempty && isEmptyValue(fv) {
+			continue
+		}
+		// Explicitly unmasked fields are emitted as-is, including nested values
+		if f.mask == maskDisabled && f.kind != reflect.String && !f.isTime && !f.isDuration && !f.stringer && !f.stringers {
+			enc.AddReflected(f.name, fv.Interface())
+			continue
+		}
+		if f.isDuration {
+			m.addDuration(enc, f.name, fv)
+			continue
+		}
+		if f.stringer {
+			m.addStringer(enc, &f, fv)
+			continue
+		}
 		// Handle nested structs recursively
 		if f.kind == reflect.Struct {
 			if f.isTime {
@@ -84,15 +205,32 @@ func (m maskedObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 				enc.AddTime(f.name, fv.Interface().(time.Time))
 				continue
 			}
+			if raw, ok := fv.Interface().(RawValue); ok {
+				// Explicit opt-out: emit as-is without masking
+				enc.AddReflected(f.name, raw.v)
+				continue
+			}
 			// Recursively marshal nested struct
-			enc.AddObject(f.name, maskedObject{fv.Interface()})
+			if !m.canNest() {
+				enc.AddString(f.name, maxDepthPlaceholder)
+				continue
+			}
+			enc.AddObject(f.name, m.child(fv.Interface()))
 			continue
 		}
 		// Handle pointer to struct
 		if f.kind == reflect.Ptr && !fv.IsNil() {
 			elem := fv.Elem()
 			if elem.Kind() == reflect.Struct {
-				enc.AddObject(f.name, maskedObject{elem.Interface()})
+				if m.visited[fv.Pointer()] {
+					enc.AddString(f.name, cyclePlaceholder)
+					continue
+				}
+				if !m.canNest() {
+					enc.AddString(f.name, maxDepthPlaceholder)
+					continue
+				}
+				enc.AddObject(f.name, m.child(fv.Interface()))
 				continue
 			}
 		}
@@ -104,25 +242,97 @@ func (m maskedObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 				elemType := fv.Type().Elem()
 				if elemType.Kind() == reflect.Struct || (elemType.Kind() == reflect.Ptr && elemType.Elem().Kind() == reflect.Struct) {
 					// Slice of structs - use maskedArray for recursive masking
-					enc.AddArray(f.name, maskedArray{fv})
+					if !m.canNest() {
+						enc.AddString(f.name, maxDepthPlaceholder)
+						continue
+					}
+					enc.AddArray(f.name, m.childArray(fv))
+					continue
+				}
+			}
+			elemMask := f.elemMask
+			if f.mask == maskNone {
+				elemMask = cfg.byName(f.name, elemMask)
+			}
+			// Byte slices - mask the whole value, or JSON content by key name
+			if f.kind == reflect.Slice && fv.Type().Elem().Kind() == reflect.Uint8 {
+				if s, ok := maskBytes(fv.Bytes(), elemMask, cfg, m.count); ok {
+					enc.AddString(f.name, s)
+					continue
+				}
+			} else if elemMask != maskNone && fv.Type().Elem().Kind() == reflect.String {
+				// String slices - mask each element
+				enc.AddArray(f.name, maskedStrings{v: fv, mask: elemMask, count: m.count})
+				continue
+			} else if f.stringers && fv.Len() > 0 {
+				// Slices of net.IP, uuid.UUID, and the like - log each by its String form
+				arr := m.childArray(fv)
+				arr.mask = elemMask
+				enc.AddArray(f.name, arr)
+				continue
+			} else if k := fv.Type().Elem().Kind(); (k == reflect.Interface || k == reflect.Map) && !isNil && cfg.Enabled {
+				// Interface slices - mask mixed elements, e.g. DBData.Args
+				if !m.canNest() {
+					enc.AddString(f.name, maxDepthPlaceholder)
 					continue
 				}
+				arr := m.childArray(fv)
+				arr.mask = elemMask
+				enc.AddArray(f.name, arr)
+				continue
 			}
 			// Slice of primitives - use reflection
 			enc.AddReflected(f.name, fv.Interface())
 			continue
 		}
+		// Handle maps with string keys, masking values by key name
+		if f.kind == reflect.Map && !fv.IsNil() && isStringKeyedMap(fv) {
+			if !m.canNest() {
+				enc.AddString(f.name, maxDepthPlaceholder)
+				continue
+			}
+			mm := m.childMap(fv)
+			mm.headers = f.mask == maskHeaders
+			enc.AddObject(f.name, mm)
+			continue
+		}
+		// Handle interface fields, such as HTTPData.Body, by their dynamic value
+		if f.kind == reflect.Interface {
+			mt := f.mask
+			if cfg := m.config(); mt == maskNone && cfg.AutoMaskByName {
+				mt = cfg.withProfiles(f.name, f.nameMask)
+			}
+			m.addDynamic(enc, f.name, fv, mt)
+			continue
+		}
 		// Handle string fields with masking
 		if f.kind == reflect.String {
 			s := fv.String()
-			switch f.mask {
-			case maskFull:
-				enc.AddString(f.name, "****")
-			case maskPartial:
-				enc.AddString(f.name, maskMiddle(s))
-			default:
-				enc.AddString(f.name, s)
+			if f.mask == maskSQL {
+				cfg := m.config()
+				if cfg.MaskSQLLiterals {
+					enc.AddString(f.name, m.count.changed(s, maskSQLLiterals(statementDriver(rv), s)))
+				} else {
+					enc.AddString(f.name, s)
+				}
+				if cfg.NormalizeSQL {
+					enc.AddString(f.name+"_normalized", normalizeSQL(s))
+				}
+				continue
+			}
+			if f.mask == maskIP && cfg.AnonymizeIP {
+				enc.AddString(f.name, m.count.changed(s, anonymizeIPValue(s, cfg.MaskInvalidIP)))
+				continue
+			}
+			mt := f.mask
+			if mt == maskIP || mt == maskHeaders {
+				// Not anonymized; mask by name like an untagged field
+				mt = maskNone
 			}
+			if mt == maskNone && (cfg.AutoMaskByName || cfg.Mode == MaskingAllowlist) {
+				mt = cfg.byName(f.name, f.nameMask)
+			}
+			enc.AddString(f.name, m.count.mask(s, mt))
 			continue
 		}
 		// Handle other basic types
@@ -143,27 +353,281 @@ func (m maskedObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	return nil
 }
 
+// durationType is the type of time.Duration fields, see addDuration.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// addDuration adds the time.Duration or *time.Duration field v as name,
+// formatted by MaskingConfig.DurationFormat rather than as raw nanoseconds.
+func (m maskedObject) addDuration(enc zapcore.ObjectEncoder, name string, v reflect.Value) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			enc.AddReflected(name, nil)
+			return
+		}
+		v = v.Elem()
+	}
+	d := time.Duration(v.Int())
+	switch m.config().DurationFormat {
+	case DurationFormatMillis:
+		enc.AddFloat64(name, DurationMillis(d))
+	case DurationFormatNanos:
+		enc.AddInt64(name, int64(d))
+	default:
+		enc.AddString(name, FormatDuration(d))
+	}
+}
+
+// stringerType and netIPType identify the byte slices and arrays logged by
+// their String form, see addStringer.
+var (
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	netIPType    = reflect.TypeOf(net.IP(nil))
+)
+
+// addStringer adds the byte slice or array field fv implementing
+// fmt.Stringer, such as net.IP or uuid.UUID, by its String form rather than
+// its raw bytes. It is masked by tag or name like other byte slices, and
+// net.IP fields are anonymized when AnonymizeIP is set, as if tagged
+// log:"masked:ip".
+func (m maskedObject) addStringer(enc zapcore.ObjectEncoder, f *fieldMeta, fv reflect.Value) {
+	s := stringerValue(fv)
+	cfg := m.config()
+	mt := f.elemMask
+	if f.mask == maskNone {
+		mt = cfg.byName(f.name, mt)
+	}
+	switch {
+	case mt != maskNone && mt != maskIP:
+		s = m.count.mask(s, mt)
+	case (f.mask == maskIP || f.isIP && f.mask == maskNone) && cfg.AnonymizeIP:
+		s = m.count.changed(s, anonymizeIPValue(s, cfg.MaskInvalidIP))
+	}
+	enc.AddString(f.name, s)
+}
+
+// isByteStringer reports whether t is a byte slice or array implementing
+// fmt.Stringer, to be logged by its String form.
+func isByteStringer(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return t.Elem().Kind() == reflect.Uint8 && t.Implements(stringerType)
+	}
+	return false
+}
+
+// stringerValue returns the String form of v, a value of an isByteStringer
+// type. An empty slice is "", as encoding/json logs it, rather than what
+// String makes of it, such as "<nil>" for net.IP.
+func stringerValue(v reflect.Value) string {
+	if v.Kind() == reflect.Slice && v.Len() == 0 {
+		return ""
+	}
+	return v.Interface().(fmt.Stringer).String()
+}
+
+// dtoMarshaler is implemented by the built-in DTOs, such as HTTPData, which
+// marshal their fields by hand with the masking of m instead of reflecting
+// over their struct tags. The output matches the reflected one.
+type dtoMarshaler interface {
+	marshalMasked(enc zapcore.ObjectEncoder, m maskedObject) error
+}
+
+// dtoField reports whether the DTO field name holding v is left for the
+// caller to add, as MarshalLogObject does for reflected fields: redacted
+// fields are dropped, fields handled by the FieldMasker are added masked, and
+// empty fields are skipped, their json tags all having omitempty.
+func dtoField[T any](m maskedObject, enc zapcore.ObjectEncoder, name string, v T, empty bool) bool {
+	cfg := m.config()
+	if cfg.redacts(name) {
+		m.count.add()
+		return false
+	}
+	if cfg.FieldMasker != nil {
+		if masked, ok := cfg.FieldMasker(name, v); ok {
+			m.count.add()
+			zap.Any(name, masked).AddTo(enc)
+			return false
+		}
+	}
+	return !empty
+}
+
+// addString adds the untagged DTO string field name.
+func (m maskedObject) addString(enc zapcore.ObjectEncoder, name, s string) {
+	if dtoField(m, enc, name, s, s == "") {
+		m.addByName(enc, name, s)
+	}
+}
+
+// addByName adds the string s as name, masked by its name only with
+// AutoMaskByName or in MaskingAllowlist mode.
+func (m maskedObject) addByName(enc zapcore.ObjectEncoder, name, s string) {
+	cfg := m.config()
+	if cfg.AutoMaskByName || cfg.Mode == MaskingAllowlist {
+		s = m.count.mask(s, cfg.fieldMask(name))
+	}
+	enc.AddString(name, s)
+}
+
+// addIP adds the log:"masked:ip" DTO field name, anonymized when
+// AnonymizeIP is set.
+func (m maskedObject) addIP(enc zapcore.ObjectEncoder, name, s string) {
+	if !dtoField(m, enc, name, s, s == "") {
+		return
+	}
+	if cfg := m.config(); cfg.AnonymizeIP {
+		enc.AddString(name, m.count.changed(s, anonymizeIPValue(s, cfg.MaskInvalidIP)))
+		return
+	}
+	// Not anonymized; mask by name like an untagged field
+	m.addByName(enc, name, s)
+}
+
+// addSQL adds the log:"masked:sql" DTO field name holding a statement for
+// driver, see maskSQLLiterals, followed by its normalized form when
+// NormalizeSQL is set.
+func (m maskedObject) addSQL(enc zapcore.ObjectEncoder, name, stmt, driver string) {
+	if !dtoField(m, enc, name, stmt, stmt == "") {
+		return
+	}
+	cfg := m.config()
+	if cfg.MaskSQLLiterals {
+		enc.AddString(name, m.count.changed(stmt, maskSQLLiterals(driver, stmt)))
+	} else {
+		enc.AddString(name, stmt)
+	}
+	if cfg.NormalizeSQL {
+		enc.AddString(name+"_normalized", normalizeSQL(stmt))
+	}
+}
+
+// addAny adds the DTO interface field name, such as HTTPData.Body, by its
+// dynamic value v.
+func (m maskedObject) addAny(enc zapcore.ObjectEncoder, name string, v any) {
+	if !dtoField(m, enc, name, v, v == nil) {
+		return
+	}
+	mt := maskNone
+	if cfg := m.config(); cfg.AutoMaskByName {
+		mt = cfg.withProfiles(name, shouldMaskField(name))
+	}
+	m.addDynamicValue(enc, name, reflect.ValueOf(v), mt)
+}
+
+// addDTOMap adds the DTO map field name, masking its values by key name, or as
+// HTTP headers when headers is set.
+func addDTOMap[V any](m maskedObject, enc zapcore.ObjectEncoder, name string, v map[string]V, headers bool) {
+	if !dtoField(m, enc, name, v, len(v) == 0) {
+		return
+	}
+	if !m.canNest() {
+		enc.AddString(name, maxDepthPlaceholder)
+		return
+	}
+	mm := m.childMap(reflect.ValueOf(v))
+	mm.headers = headers
+	enc.AddObject(name, mm)
+}
+
+// addScanned adds the log:"masked:scan" DTO field name, such as DBData.Args,
+// masking string elements that look like emails, card numbers, or phone
+// numbers.
+func (m maskedObject) addScanned(enc zapcore.ObjectEncoder, name string, v []any) {
+	if !dtoField(m, enc, name, v, len(v) == 0) {
+		return
+	}
+	if !m.config().Enabled {
+		enc.AddReflected(name, v)
+		return
+	}
+	if !m.canNest() {
+		enc.AddString(name, maxDepthPlaceholder)
+		return
+	}
+	arr := m.childArray(reflect.ValueOf(v))
+	arr.mask = maskScan
+	enc.AddArray(name, arr)
+}
+
+// addInt adds the DTO integer field name.
+func (m maskedObject) addInt(enc zapcore.ObjectEncoder, name string, v int) {
+	if dtoField(m, enc, name, v, v == 0) {
+		enc.AddInt64(name, int64(v))
+	}
+}
+
+// addFloat adds the DTO float field name.
+func (m maskedObject) addFloat(enc zapcore.ObjectEncoder, name string, v float64) {
+	if dtoField(m, enc, name, v, v == 0) {
+		enc.AddFloat64(name, v)
+	}
+}
+
 // maskedArray wraps a slice/array for custom marshaling with masking support.
+// Elements share the array's depth, since the array itself counts as one level.
 type maskedArray struct {
-	v reflect.Value
+	v       reflect.Value
+	cfg     *MaskingConfig   // Masking configuration; nil means defaultMaskingConfig
+	depth   int              // Nesting depth of the array
+	visited map[uintptr]bool // Struct pointers on the current path, for cycle detection
+	mask    maskType         // Masking strategy for string elements, by tag
+	count   *maskCounter     // Counts masked values; nil when off
 }
 
 // MarshalLogArray implements zapcore.ArrayMarshaler.
 // It marshals array elements with automatic masking for structs.
 func (m maskedArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	parent := maskedObject{cfg: m.cfg, depth: m.depth, visited: m.visited, count: m.count}
 	for i := 0; i < m.v.Len(); i++ {
 		elem := m.v.Index(i)
+		// Unwrap interface elements, e.g. []any decoded from JSON
+		if elem.Kind() == reflect.Interface {
+			if elem.IsNil() {
+				enc.AppendReflected(nil)
+				continue
+			}
+			elem = elem.Elem()
+		}
+		target := elem
 		// Handle pointer elements
 		if elem.Kind() == reflect.Pointer {
 			if elem.IsNil() {
 				enc.AppendReflected(nil)
 				continue
 			}
+			if parent.visited[elem.Pointer()] {
+				enc.AppendString(cyclePlaceholder)
+				continue
+			}
 			elem = elem.Elem()
 		}
 		// If element is a struct, wrap with maskedObject
-		if elem.Kind() == reflect.Struct {
-			enc.AppendObject(maskedObject{elem.Interface()})
+		if isByteStringer(elem.Type()) {
+			enc.AppendString(m.stringerElem(stringerValue(elem), elem.Type(), parent.config()))
+		} else if elem.Kind() == reflect.Struct {
+			if !parent.canNest() {
+				enc.AppendString(maxDepthPlaceholder)
+				continue
+			}
+			// Pass pointers through so the element is tracked for cycles
+			enc.AppendObject(parent.child(target.Interface()))
+		} else if isStringKeyedMap(elem) {
+			if !parent.canNest() {
+				enc.AppendString(maxDepthPlaceholder)
+				continue
+			}
+			enc.AppendObject(parent.childMap(elem))
+		} else if isNestedArray(elem) {
+			// Nested slices, e.g. []any{[]any{...}}, keep the element mask
+			if !parent.canNest() {
+				enc.AppendString(maxDepthPlaceholder)
+				continue
+			}
+			arr := parent.childArray(elem)
+			arr.mask = m.mask
+			enc.AppendArray(arr)
+		} else if elem.Kind() == reflect.String {
+			enc.AppendString(m.maskElem(elem.String(), parent.config()))
 		} else {
 			enc.AppendReflected(elem.Interface())
 		}
@@ -171,13 +635,302 @@ func (m maskedArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
 	return nil
 }
 
+// maskElem masks the string element s by the array's mask. Without one, as
+// in heterogeneous []any payloads, JSON documents are masked by key name and
+// other values are scanned for emails, card numbers, and phone numbers.
+func (m maskedArray) maskElem(s string, cfg *MaskingConfig) string {
+	switch {
+	case m.mask != maskNone:
+		return m.count.mask(s, m.mask)
+	case !cfg.Enabled:
+		return s
+	case looksLikeJSON(s):
+		return maskJSONCounted(s, cfg, m.count)
+	}
+	return m.count.mask(s, maskScan)
+}
+
+// stringerElem masks s, the String form of a net.IP, uuid.UUID, or similar
+// element of type t, by the array's mask. Without one, net.IP elements are
+// anonymized when AnonymizeIP is set and other values are kept.
+func (m maskedArray) stringerElem(s string, t reflect.Type, cfg *MaskingConfig) string {
+	switch {
+	case m.mask != maskNone && m.mask != maskIP:
+		return m.count.mask(s, m.mask)
+	case (m.mask == maskIP || t == netIPType) && cfg.AnonymizeIP:
+		return m.count.changed(s, anonymizeIPValue(s, cfg.MaskInvalidIP))
+	}
+	return s
+}
+
+// isNestedArray reports whether v is a non-empty slice or array to be masked
+// element by element. Byte slices are excluded, they are logged whole.
+func isNestedArray(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8 {
+			return false
+		}
+	case reflect.Array:
+	default:
+		return false
+	}
+	return v.Len() > 0
+}
+
+// maskedMap wraps a map with string keys for custom marshaling with masking support.
+// String values, and string slices such as header values, are masked by key
+// name using shouldMaskField, the same rules applied to JSON bodies. Nested maps, structs, and slices are masked recursively.
+// Keys are emitted in sorted order, matching encoding/json.
+type maskedMap struct {
+	v       reflect.Value
+	cfg     *MaskingConfig   // Masking configuration; nil means defaultMaskingConfig
+	depth   int              // Nesting depth of the map
+	visited map[uintptr]bool // Struct pointers on the current path, for cycle detection
+	count   *maskCounter     // Counts masked values; nil when off
+	headers bool             // HTTP headers, subject to MaskingConfig.RedactHeaders
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (m maskedMap) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	parent := maskedObject{cfg: m.cfg, depth: m.depth, visited: m.visited, count: m.count}
+	cfg := parent.config()
+	keys := m.v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	for i, k := range keys {
+		if cfg.truncates(i) {
+			enc.AddBool(truncatedKey, true)
+			break
+		}
+		key := k.String()
+		if cfg.redacts(key) || m.headers && cfg.redactsHeader(key) {
+			m.count.add()
+			continue
+		}
+		v := m.v.MapIndex(k)
+		if cfg.FieldMasker != nil {
+			if masked, ok := cfg.FieldMasker(key, v.Interface()); ok {
+				m.count.add()
+				zap.Any(key, masked).AddTo(enc)
+				continue
+			}
+		}
+		if v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				enc.AddReflected(key, nil)
+				continue
+			}
+			v = v.Elem()
+		}
+		maskFor := cfg.fieldMask
+		if m.headers {
+			maskFor = cfg.headerMask
+		}
+		if v.Kind() == reflect.String {
+			enc.AddString(key, m.count.mask(v.String(), maskFor(key)))
+			continue
+		}
+		// String slices, such as HTTP header values, mask each element
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String {
+			values := maskedStrings{v: v, mask: maskFor(key), count: m.count}
+			if m.headers && cfg.FlattenHeaders {
+				enc.AddString(key, values.join(headerSeparator))
+				continue
+			}
+			if values.mask != maskNone {
+				enc.AddArray(key, values)
+				continue
+			}
+		}
+		parent.addNested(enc, key, v)
+	}
+	return nil
+}
+
+// addDynamic adds the value held by an interface field. JSON documents in
+// strings and byte slices are masked by key name, as MaskingLogJSONBytes does,
+// so bodies and payloads can be logged unmasked; other strings get mt and
+// remaining values are masked like map values. Strings and byte slices are
+// then capped at MaskingConfig.MaxPayloadBytes, so truncation never cuts a
+// value before it is masked.
+func (m maskedObject) addDynamic(enc zapcore.ObjectEncoder, key string, v reflect.Value, mt maskType) {
+	if v.IsNil() {
+		enc.AddReflected(key, nil)
+		return
+	}
+	m.addDynamicValue(enc, key, v.Elem(), mt)
+}
+
+// addDynamicValue is addDynamic for the non-nil value v held by the field.
+func (m maskedObject) addDynamicValue(enc zapcore.ObjectEncoder, key string, v reflect.Value, mt maskType) {
+	if cfg := m.config(); len(cfg.DataConverters) > 0 && v.CanInterface() {
+		if converted, ok := cfg.convert(v.Interface()); ok {
+			if converted == nil {
+				enc.AddReflected(key, nil)
+				return
+			}
+			v = reflect.ValueOf(converted)
+		}
+	}
+	max := m.config().MaxPayloadBytes
+	switch {
+	case v.Kind() == reflect.String:
+		s := v.String()
+		if mt == maskNone && looksLikeJSON(s) {
+			// Already-masked documents come back unchanged, masking is idempotent
+			enc.AddString(key, truncatePayload(maskJSONCounted(s, m.config(), m.count), max))
+			return
+		}
+		if mt == maskNone {
+			mt = m.config().allowlistMask(key)
+		}
+		enc.AddString(key, truncatePayload(m.count.mask(s, mt), max))
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		if s, ok := maskBytes(v.Bytes(), mt, m.config(), m.count); ok {
+			enc.AddString(key, truncatePayload(s, max))
+			return
+		}
+		if mt == maskNone && m.config().allowlistMask(key) != maskNone {
+			m.count.add()
+			enc.AddString(key, "****")
+			return
+		}
+		if max > 0 && base64.StdEncoding.EncodedLen(v.Len()) > max {
+			// Binary payloads are logged base64-encoded, like encoding/json does
+			enc.AddString(key, truncatePayload(base64.StdEncoding.EncodeToString(v.Bytes()), max))
+			return
+		}
+		enc.AddReflected(key, v.Interface())
+	default:
+		m.addNested(enc, key, v)
+	}
+}
+
+// truncatePayload cuts s to at most max bytes, on a rune boundary, followed by
+// a "<truncated N bytes>" marker giving the number of bytes dropped.
+// A max of 0 or less leaves s unchanged.
+func truncatePayload(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "<truncated " + strconv.Itoa(len(s)-cut) + " bytes>"
+}
+
+// addNested adds a non-string value found one level below m, wrapping structs,
+// string-keyed maps, and slices so that their contents are masked too.
+func (m maskedObject) addNested(enc zapcore.ObjectEncoder, key string, v reflect.Value) {
+	target := v
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			enc.AddReflected(key, nil)
+			return
+		}
+		if m.visited[v.Pointer()] {
+			enc.AddString(key, cyclePlaceholder)
+			return
+		}
+		v = v.Elem()
+	}
+	if raw, ok := target.Interface().(RawValue); ok {
+		enc.AddReflected(key, raw.v)
+		return
+	}
+	nested := v.Kind() == reflect.Struct && v.Type() != reflect.TypeOf(time.Time{}) ||
+		isStringKeyedMap(v) && !v.IsNil() ||
+		(v.Kind() == reflect.Slice && !v.IsNil() || v.Kind() == reflect.Array) && v.Len() > 0
+	if !nested {
+		enc.AddReflected(key, target.Interface())
+		return
+	}
+	if !m.canNest() {
+		enc.AddString(key, maxDepthPlaceholder)
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		enc.AddObject(key, m.child(target.Interface()))
+	case reflect.Map:
+		enc.AddObject(key, m.childMap(v))
+	default:
+		arr := m.childArray(v)
+		// In allowlist mode, string elements are masked by the array's key
+		arr.mask = m.config().allowlistMask(key)
+		enc.AddArray(key, arr)
+	}
+}
+
+// isStringKeyedMap reports whether v is a map whose keys are strings.
+func isStringKeyedMap(v reflect.Value) bool {
+	return v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String
+}
+
+// maskedStrings wraps a string slice or array, masking every element.
+type maskedStrings struct {
+	v     reflect.Value
+	mask  maskType
+	count *maskCounter // Counts masked values; nil when off
+}
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (m maskedStrings) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for i := 0; i < m.v.Len(); i++ {
+		enc.AppendString(m.count.mask(m.v.Index(i).String(), m.mask))
+	}
+	return nil
+}
+
+// headerSeparator joins the values of a multi-value header when
+// MaskingConfig.FlattenHeaders is set, as in a combined HTTP header field.
+const headerSeparator = ", "
+
+// join returns the masked strings joined with sep.
+func (m maskedStrings) join(sep string) string {
+	if m.v.Len() == 1 {
+		return m.count.mask(m.v.Index(0).String(), m.mask)
+	}
+	var b strings.Builder
+	for i := 0; i < m.v.Len(); i++ {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(m.count.mask(m.v.Index(i).String(), m.mask))
+	}
+	return b.String()
+}
+
+// maskBytes masks a byte slice field. With a mask type the whole value is
+// masked as a string; otherwise a JSON object or array is masked by key name
+// with cfg. Masked values are counted in count, which may be nil.
+// Returns false if b should be logged as-is.
+func maskBytes(b []byte, mt maskType, cfg *MaskingConfig, count *maskCounter) (string, bool) {
+	if mt != maskNone {
+		return count.mask(string(b), mt), true
+	}
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') || !json.Valid(trimmed) {
+		return "", false
+	}
+	return maskJSONCounted(string(trimmed), cfg, count), true
+}
+
 // fieldMeta contains cached metadata for a single struct field.
 type fieldMeta struct {
-	name   string       // Field name (for JSON key)
-	index  int          // Field index in struct
-	kind   reflect.Kind // Field type kind
-	mask   maskType     // Masking strategy
-	isTime bool         // True if field is time.Time
+	name       string       // Field name (for JSON key)
+	index      []int        // Field index path, longer than one for promoted fields
+	kind       reflect.Kind // Field type kind
+	mask       maskType     // Masking strategy
+	isTime     bool         // True if field is time.Time
+	isDuration bool         // True if field is time.Duration or *time.Duration
+	stringer   bool         // True if field is a byte slice or array implementing fmt.Stringer, such as net.IP
+	stringers  bool         // True if field is a slice or array of such values, such as []net.IP
+	isIP       bool         // True if field is net.IP
+	omitempty  bool         // True if the json tag has the omitempty option
+	elemMask   maskType     // Masking strategy for string/byte slice elements, by tag or field name
+	nameMask   maskType     // Masking strategy derived from the field name, for AutoMaskByName
 }
 
 // structMeta contains cached metadata for all fields in a struct.
@@ -189,9 +942,19 @@ type structMeta struct {
 type maskType uint8
 
 const (
-	maskNone    maskType = iota // No masking
-	maskFull                    // Full masking: "****"
-	maskPartial                 // Partial masking: show first 2 and last 2 chars
+	maskNone      maskType = iota // No masking
+	maskFull                      // Full masking: "****"
+	maskPartial                   // Partial masking: show first 2 and last 2 chars
+	maskDisabled                  // Explicitly unmasked via tag, overriding name-based masking
+	maskEmailAddr                 // Email masking: mask the local part, keep the domain
+	maskPhoneNum                  // Phone masking: keep the country code and last 2 digits
+	maskScan                      // Value scanning: mask values that look like emails, cards, or phones
+	maskSQL                       // SQL statement: scrub literals or add a normalized copy, per MaskingConfig
+	maskIP                        // IP address: zero the host part when MaskingConfig.AnonymizeIP is set
+	maskHeaders                   // HTTP headers: drop those listed in MaskingConfig.RedactHeaders
+	maskCookie                    // Cookie header: mask each cookie value, keep the names
+	maskSetCookie                 // Set-Cookie header: mask the cookie value, keep the name and attributes
+	maskText                      // Free-form text: mask sensitive values within it, as in messages
 )
 
 // getStructMeta retrieves or builds cached metadata for a struct type.
@@ -204,23 +967,76 @@ func getStructMeta(t reflect.Type) *structMeta {
 	}
 	// Build metadata
 	m := &structMeta{
-		fields: make([]fieldMeta, 0, t.NumField()),
+		fields: dominantFields(collectFields(t, nil, map[reflect.Type]bool{})),
 	}
+	// Cache for future use
+	structMetaCache.Store(t, m)
+	return m
+}
+
+// promotedField is a candidate field along with where it was found,
+// used to resolve name conflicts between promoted fields.
+type promotedField struct {
+	fieldMeta
+	depth  int  // Embedding depth, 0 for fields declared directly on the struct
+	tagged bool // True if the name came from a json tag
+}
+
+// collectFields gathers the fields of t in declaration order. Exported embedded
+// structs without a json tag have their fields promoted into the parent like
+// encoding/json, keeping their own masking tags. visiting guards against
+// recursive embedding.
+func collectFields(t reflect.Type, index []int, visiting map[reflect.Type]bool) []promotedField {
+	visiting[t] = true
+	defer delete(visiting, t)
+	fields := make([]promotedField, 0, t.NumField())
 	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
-		// Skip unexported fields
+		// Skip unexported fields, including unexported embedded types,
+		// whose values reflection can't read via Interface
 		if !f.IsExported() {
 			continue
 		}
 		// Get JSON tag name, default to field name
 		jsonTag := f.Tag.Get("json")
+		// json:"-" omits the field; json:"-," names it "-"
+		if jsonTag == "-" {
+			continue
+		}
+		fieldIndex := append(index[:len(index):len(index)], i)
+		// Promote fields of embedded structs
+		if f.Anonymous && jsonTag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if visiting[ft] {
+					continue
+				}
+				for _, pf := range collectFields(ft, fieldIndex, visiting) {
+					pf.depth++
+					fields = append(fields, pf)
+				}
+				continue
+			}
+		}
 		fieldName := f.Name
-		if jsonTag != "" && jsonTag != "-" {
+		omitempty := false
+		tagged := false
+		if jsonTag != "" {
 			// Parse JSON tag (handle "name,omitempty" format)
-			if idx := strings.Index(jsonTag, ","); idx > 0 {
-				fieldName = jsonTag[:idx]
-			} else {
-				fieldName = jsonTag
+			name, opts, _ := strings.Cut(jsonTag, ",")
+			if name != "" {
+				fieldName = name
+				tagged = true
+			}
+			for opts != "" {
+				var opt string
+				opt, opts, _ = strings.Cut(opts, ",")
+				if opt == "omitempty" {
+					omitempty = true
+				}
 			}
 		}
 		// Parse masking tag
@@ -231,26 +1047,144 @@ func getStructMeta(t reflect.Type) *structMeta {
 			mt = maskFull
 		case "masked:partial":
 			mt = maskPartial
+		case "masked:email":
+			mt = maskEmailAddr
+		case "masked:phone":
+			mt = maskPhoneNum
+		case "masked:scan":
+			mt = maskScan
+		case "masked:text":
+			mt = maskText
+		case "masked:sql":
+			mt = maskSQL
+		case "masked:ip":
+			mt = maskIP
+		case "masked:headers":
+			mt = maskHeaders
+		case "masked:none", "nomask":
+			mt = maskDisabled
+		}
+		// Slices of strings or bytes are masked by tag, falling back to the field name.
+		// Slices of interfaces, such as DBData.Args, are only masked by tag
+		elemMask := maskNone
+		if k := f.Type.Kind(); k == reflect.Slice || k == reflect.Array {
+			if ek := f.Type.Elem().Kind(); ek == reflect.Interface && mt != maskDisabled {
+				elemMask = mt
+			} else if ek == reflect.String || ek == reflect.Uint8 || isByteStringer(f.Type.Elem()) {
+				switch mt {
+				case maskNone:
+					elemMask = shouldMaskField(fieldName)
+				case maskDisabled:
+					elemMask = maskNone
+				default:
+					elemMask = mt
+				}
+			}
 		}
 		// Check if field is time.Time
 		isTime := f.Type == reflect.TypeOf(time.Time{})
-		m.fields = append(m.fields, fieldMeta{
-			name:   fieldName, // Use JSON tag name
-			index:  i,
-			kind:   f.Type.Kind(),
-			mask:   mt,
-			isTime: isTime,
+		isDuration := f.Type == durationType || f.Type == reflect.PointerTo(durationType)
+		fields = append(fields, promotedField{
+			fieldMeta: fieldMeta{
+				name:       fieldName, // Use JSON tag name
+				index:      fieldIndex,
+				kind:       f.Type.Kind(),
+				mask:       mt,
+				isTime:     isTime,
+				isDuration: isDuration,
+				stringer:   isByteStringer(f.Type),
+				stringers:  (f.Type.Kind() == reflect.Slice || f.Type.Kind() == reflect.Array) && isByteStringer(f.Type.Elem()),
+				isIP:       f.Type == netIPType,
+				omitempty:  omitempty,
+				elemMask:   elemMask,
+				nameMask:   shouldMaskField(fieldName),
+			},
+			tagged: tagged,
 		})
 	}
-	// Cache for future use
-	structMetaCache.Store(t, m)
-	return m
+	return fields
 }
 
-// maskMiddle masks the middle portion of a string, showing only first 2 and last 2 characters.
-//
-// Examples:
-//   - "johndoe123" → "jo****23"
+// dominantFields resolves duplicate names using the encoding/json rules:
+// the shallowest field wins, a json-tagged field wins a tie at the same depth,
+// and any other tie drops the name entirely. Declaration order is preserved.
+func dominantFields(candidates []promotedField) []fieldMeta {
+	byName := make(map[string][]int, len(candidates))
+	for i, c := range candidates {
+		byName[c.name] = append(byName[c.name], i)
+	}
+	fields := make([]fieldMeta, 0, len(candidates))
+	for i, c := range candidates {
+		if winner, ok := dominantField(candidates, byName[c.name]); ok && winner == i {
+			fields = append(fields, c.fieldMeta)
+		}
+	}
+	return fields
+}
+
+// dominantField returns the index of the field that wins among same-named candidates.
+func dominantField(candidates []promotedField, idx []int) (int, bool) {
+	if len(idx) == 1 {
+		return idx[0], true
+	}
+	minDepth := candidates[idx[0]].depth
+	for _, i := range idx[1:] {
+		minDepth = min(minDepth, candidates[i].depth)
+	}
+	var shallowest, tagged []int
+	for _, i := range idx {
+		if candidates[i].depth == minDepth {
+			shallowest = append(shallowest, i)
+			if candidates[i].tagged {
+				tagged = append(tagged, i)
+			}
+		}
+	}
+	switch {
+	case len(shallowest) == 1:
+		return shallowest[0], true
+	case len(tagged) == 1:
+		return tagged[0], true
+	}
+	return 0, false
+}
+
+// fieldByIndex returns the field at the given index path, stepping through
+// embedded pointers. Returns false if an embedded pointer on the path is nil.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmptyValue reports whether v is empty per the encoding/json omitempty rules:
+// false, 0, a nil pointer or interface, and any empty array, slice, map, or string.
+// Structs, including time.Time, are never empty.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// maskMiddle masks the middle portion of a string, showing only first 2 and last 2 characters.
+//
+// Examples:
+//   - "johndoe123" → "jo****23"
 //   - "john.doe@example.com" → "jo****om"
 //   - "abc" → "****" (too short)
 func maskMiddle(s string) string {
@@ -260,56 +1194,848 @@ func maskMiddle(s string) string {
 	return s[:2] + "****" + s[len(s)-2:]
 }
 
+// maskString applies the masking strategy mt to s.
+func maskString(s string, mt maskType) string {
+	switch mt {
+	case maskFull:
+		return "****"
+	case maskPartial:
+		return maskMiddle(s)
+	case maskEmailAddr:
+		return maskEmail(s)
+	case maskPhoneNum:
+		return maskPhone(s)
+	case maskScan:
+		return maskSensitiveValue(s)
+	case maskText:
+		return scrubMessage(s)
+	case maskCookie:
+		return maskCookieValues(s, false)
+	case maskSetCookie:
+		return maskCookieValues(s, true)
+	}
+	return s
+}
+
+// maskCookieValues masks the cookie values in a Cookie header value, or in a
+// Set-Cookie header value when setCookie is true, keeping cookie names and
+// the Set-Cookie attributes, such as Path or HttpOnly, visible.
+//
+// Examples:
+//   - "session=abc123; theme=dark" → "session=****; theme=****"
+//   - "session=abc123; Path=/; HttpOnly; Secure" → "session=****; Path=/; HttpOnly; Secure"
+func maskCookieValues(s string, setCookie bool) string {
+	parts := strings.Split(s, ";")
+	for i, part := range parts {
+		if setCookie && i > 0 {
+			// Attributes follow the cookie itself
+			break
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			if strings.TrimSpace(part) != "" {
+				// A value without a name
+				parts[i] = "****"
+			}
+			continue
+		}
+		if strings.TrimSpace(value) != "" {
+			parts[i] = name + "=****"
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// maskCounter counts the values masked while encoding one log entry, see
+// MaskingConfig.EmitMaskCount. Logged with zap.Inline after the data it
+// counts, it adds the count under key and resets it, so the same entry
+// written to several cores reports the same count. A nil *maskCounter
+// counts nothing.
+type maskCounter struct {
+	key string
+	n   int
+}
+
+// add counts one masked value.
+func (c *maskCounter) add() {
+	if c != nil {
+		c.n++
+	}
+}
+
+// mask is maskString, counting s if masking changed it.
+func (c *maskCounter) mask(s string, mt maskType) string {
+	if mt == maskNone {
+		return s
+	}
+	return c.changed(s, maskString(s, mt))
+}
+
+// changed returns masked, counting it if it differs from the original s.
+func (c *maskCounter) changed(s, masked string) string {
+	if masked != s {
+		c.add()
+	}
+	return masked
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (c *maskCounter) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt(c.key, c.n)
+	c.n = 0
+	return nil
+}
+
+// maskSensitiveValue masks s if its value looks sensitive regardless of the
+// field holding it: emails as by maskEmail, payment card numbers keeping the
+// last 4 digits, and phone numbers as by maskPhone. Other values are returned
+// unchanged.
+//
+// Examples:
+//   - "john.doe@example.com" → "jo****@example.com"
+//   - "4111 1111 1111 1111" → "****1111"
+//   - "+6281234567890" → "+62********90"
+//   - "active" → "active"
+func maskSensitiveValue(s string) string {
+	if masked, ok := maskRecognizedValue(s); ok {
+		return masked
+	}
+	return s
+}
+
+// maskRecognizedValue masks s if it looks like an email, payment card number,
+// or phone number, and reports whether it did.
+func maskRecognizedValue(s string) (string, bool) {
+	switch {
+	case looksLikeEmail(s):
+		return maskEmail(s), true
+	case looksLikeCard(s):
+		return "****" + lastDigits(s, 4), true
+	case looksLikePhone(s):
+		return maskPhone(s), true
+	}
+	return s, false
+}
+
+// looksLikeEmail reports whether s is a single local@domain.tld address.
+func looksLikeEmail(s string) bool {
+	local, domain, ok := strings.Cut(s, "@")
+	if !ok || local == "" || strings.ContainsAny(s, " \t\n") || strings.Contains(domain, "@") {
+		return false
+	}
+	dot := strings.LastIndexByte(domain, '.')
+	return dot > 0 && dot < len(domain)-1
+}
+
+// looksLikeCard reports whether s is a 13 to 19 digit number, optionally
+// grouped with spaces or dashes, that passes the Luhn check.
+func looksLikeCard(s string) bool {
+	var sum, n int
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			d := int(c - '0')
+			if n%2 == 1 {
+				if d *= 2; d > 9 {
+					d -= 9
+				}
+			}
+			sum += d
+			n++
+		case (c == ' ' || c == '-') && i > 0 && i < len(s)-1:
+		default:
+			return false
+		}
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
+
+// lastDigits returns the last n digits of s, skipping other characters.
+func lastDigits(s string, n int) string {
+	digits := make([]byte, n)
+	for i := len(s) - 1; i >= 0 && n > 0; i-- {
+		if s[i] >= '0' && s[i] <= '9' {
+			n--
+			digits[n] = s[i]
+		}
+	}
+	return string(digits[n:])
+}
+
+// looksLikePhone reports whether s is a phone number in international
+// (+62...) or national trunk-prefixed (08...) form.
+func looksLikePhone(s string) bool {
+	if len(s) < minPhoneDigits || (s[0] != '+' && s[0] != '0') {
+		return false
+	}
+	var n int
+	for i, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			n++
+		case c == '+' && i == 0, c == ' ', c == '-', c == '.', c == '(', c == ')':
+		default:
+			return false
+		}
+	}
+	return n >= minPhoneDigits && n <= 15
+}
+
+// maskEmail masks the local part of an email address, keeping its first
+// 2 characters and the domain visible. Values that are not a single
+// local@domain pair are fully masked.
+//
+// Examples:
+//   - "john.doe@example.com" → "jo****@example.com"
+//   - "jd@example.com" → "****@example.com"
+//   - "not-an-email" → "****"
+func maskEmail(s string) string {
+	local, domain, ok := strings.Cut(s, "@")
+	if !ok || local == "" || domain == "" || strings.Contains(domain, "@") {
+		return "****"
+	}
+	if len(local) <= 2 {
+		return "****@" + domain
+	}
+	return local[:2] + "****@" + domain
+}
+
+// minPhoneDigits is the fewest digits a phone number needs to be partially revealed.
+const minPhoneDigits = 7
+
+// maskPhone masks a phone number, keeping the country code (the 2 digits after
+// a leading '+') and the last 2 digits visible. Spaces, dashes, dots, and
+// parentheses are ignored when counting digits and kept in the visible parts.
+// Numbers with fewer than 7 digits, or with other characters, are fully masked.
+//
+// Examples:
+//   - "+6281234567890" → "+62********90"
+//   - "+62 812-3456-7890" → "+62********90"
+//   - "081234567890" → "********90"
+//   - "12345" → "****"
+func maskPhone(s string) string {
+	var digits []int
+	for i, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			digits = append(digits, i)
+		case c == '+' && i == 0, c == ' ', c == '-', c == '.', c == '(', c == ')':
+		default:
+			return "****"
+		}
+	}
+	if len(digits) < minPhoneDigits {
+		return "****"
+	}
+	prefix := ""
+	if s[0] == '+' {
+		prefix = s[:digits[1]+1]
+	}
+	return prefix + "********" + s[digits[len(digits)-2]:]
+}
+
 // shouldMaskField determines if a field should be masked based on its name.
 // Returns maskFull for sensitive fields (password, secret, token),
 // maskPartial for identifiable fields (username, email), or maskNone.
 func shouldMaskField(fieldName string) maskType {
-	// Normalize: lowercase and replace dashes with underscores
-	lower := strings.ToLower(strings.ReplaceAll(fieldName, "-", "_"))
-	// Check for full masking patterns first (higher priority)
-	for _, pattern := range fullMaskFields {
-		if strings.Contains(lower, pattern) {
-			return maskFull
+	return sensitiveFields.match(fieldName)
+}
+
+// sensitiveFields matches field names against fullMaskFields and partialMaskFields.
+var sensitiveFields = newFieldMatcher(fullMaskFields, partialMaskFields)
+
+// fieldMatcher matches field names against sensitive field patterns.
+// Names are normalized by lowercasing them and replacing dashes with
+// underscores, then checked for an exact match before scanning for the
+// patterns as substrings. Full masking wins over partial masking.
+type fieldMatcher struct {
+	exact   map[string]maskType // Result for each pattern as a whole name
+	byFirst [256][]fieldPattern // Patterns indexed by their first byte
+
+	// cache holds scan results by normalized name, see remember
+	cache     sync.Map // string -> maskType
+	cacheSize atomic.Int64
+}
+
+// maxFieldCacheSize bounds the number of field names fieldMatcher caches.
+const maxFieldCacheSize = 4096
+
+// fieldPattern is a sensitive field pattern and how its matches are masked.
+type fieldPattern struct {
+	s  []byte
+	mt maskType
+}
+
+// newFieldMatcher builds a fieldMatcher for the given full and partial patterns.
+func newFieldMatcher(full, partial []string) *fieldMatcher {
+	m := &fieldMatcher{exact: make(map[string]maskType, len(full)+len(partial))}
+	for _, p := range full {
+		m.byFirst[p[0]] = append(m.byFirst[p[0]], fieldPattern{s: []byte(p), mt: maskFull})
+	}
+	for _, p := range partial {
+		m.byFirst[p[0]] = append(m.byFirst[p[0]], fieldPattern{s: []byte(p), mt: maskPartial})
+	}
+	// A pattern may itself contain a pattern of the other kind, so resolve
+	// exact matches with the substring scan instead of assuming their kind
+	for _, p := range append(append([]string(nil), full...), partial...) {
+		m.exact[p] = m.scan([]byte(p))
+	}
+	return m
+}
+
+// match returns how a field with the given name should be masked.
+func (m *fieldMatcher) match(name string) maskType {
+	var buf [64]byte
+	lower, ok := normalizeFieldName(buf[:0], name)
+	if !ok {
+		// Non-ASCII names need Unicode case folding, e.g. the Kelvin sign to 'k'
+		lower = []byte(strings.ToLower(strings.ReplaceAll(name, "-", "_")))
+	}
+	if mt, ok := m.exact[string(lower)]; ok {
+		return mt
+	}
+	if mt, ok := m.cache.Load(string(lower)); ok {
+		return mt.(maskType)
+	}
+	mt := m.scan(lower)
+	m.remember(string(lower), mt)
+	return mt
+}
+
+// remember caches the result for a normalized name, evicting an arbitrary
+// entry once the cache holds maxFieldCacheSize names, so attacker-controlled
+// keys such as JSON body members can't grow it without bound.
+func (m *fieldMatcher) remember(key string, mt maskType) {
+	if m.cacheSize.Add(1) > maxFieldCacheSize {
+		m.cache.Range(func(k, _ any) bool {
+			if _, loaded := m.cache.LoadAndDelete(k); loaded {
+				m.cacheSize.Add(-1)
+			}
+			return false
+		})
+	}
+	if _, loaded := m.cache.LoadOrStore(key, mt); loaded {
+		m.cacheSize.Add(-1)
+	}
+}
+
+// scan looks for the patterns anywhere in the normalized name.
+func (m *fieldMatcher) scan(lower []byte) maskType {
+	mt := maskNone
+	for i := range lower {
+		for _, p := range m.byFirst[lower[i]] {
+			if !bytes.HasPrefix(lower[i:], p.s) {
+				continue
+			}
+			if p.mt == maskFull {
+				return maskFull
+			}
+			mt = p.mt
 		}
 	}
-	// Check for partial masking patterns
-	for _, pattern := range partialMaskFields {
-		if strings.Contains(lower, pattern) {
-			return maskPartial
+	return mt
+}
+
+// normalizeFieldName appends name to dst, lowercased with dashes replaced by
+// underscores. Returns false if name is not ASCII.
+func normalizeFieldName(dst []byte, name string) ([]byte, bool) {
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= utf8.RuneSelf:
+			return nil, false
+		case c >= 'A' && c <= 'Z':
+			c += 'a' - 'A'
+		case c == '-':
+			c = '_'
 		}
+		dst = append(dst, c)
 	}
-	return maskNone
+	return dst, true
 }
 
-// maskJSONString parses a JSON string and masks sensitive fields.
+// maskJSONString parses a JSON string and masks sensitive fields with the
+// global logger's configuration, for the package-level helpers.
+// The document is rewritten token by token, so object keys keep their
+// original order and numbers keep their exact literal. Nesting deeper than
+// MaskingConfig.MaxDepth is replaced with "<max-depth>".
 // Returns the original string if parsing fails.
 func maskJSONString(jsonStr string) string {
+	return maskJSONCounted(jsonStr, globalMaskingConfig(), nil)
+}
+
+// maskJSONCounted is maskJSONString with cfg, the configuration of the
+// logger doing the logging, counting the masked values in count, which may
+// be nil.
+func maskJSONCounted(jsonStr string, cfg *MaskingConfig, count *maskCounter) string {
 	if jsonStr == "" {
 		return jsonStr
 	}
-	var data interface{}
-	if err := json.Unmarshal([]byte(jsonStr), &data); err != nil {
+	masked, ok := maskJSONDepth(jsonStr, maskNone, 0, cfg, count)
+	if !ok {
 		// Not valid JSON, return as-is
 		return jsonStr
 	}
-	masked := maskJSONValue(data)
-	result, err := json.Marshal(masked)
+	return masked
+}
+
+// maskJSONDepth masks a JSON document whose root sits at the given depth
+// with cfg, masking it with mt if it is a string, and counting masked values
+// in count, which may be nil.
+// Returns false if jsonStr is not valid JSON.
+func maskJSONDepth(jsonStr string, mt maskType, depth int, cfg *MaskingConfig, count *maskCounter) (string, bool) {
+	m := jsonMaskerPool.Get().(*jsonMasker)
+	defer m.release()
+	m.reset(jsonStr, cfg)
+	m.count = count
+
+	if err := m.value(mt, depth); err != nil {
+		return "", false
+	}
+	// Reject trailing data, like json.Unmarshal
+	if _, err := m.dec.Token(); err != io.EOF {
+		return "", false
+	}
+	return m.buf.String(), true
+}
+
+// maskJSONStream masks the JSON document read from src into dst with cfg,
+// flushing the output every jsonStreamFlushSize bytes.
+func maskJSONStream(dst io.Writer, src io.Reader, cfg *MaskingConfig) error {
+	m := jsonMaskerPool.Get().(*jsonMasker)
+	defer m.release()
+	m.resetReader(src, cfg)
+	m.out = dst
+
+	if err := m.value(maskNone, 0); err != nil {
+		return err
+	}
+	if _, err := m.dec.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("goslogx: trailing data after JSON value")
+		}
+		return err
+	}
+	_, err := m.out.Write(m.buf.Bytes())
+	return err
+}
+
+// jsonStreamFlushSize is the output size at which a streaming jsonMasker
+// writes its buffer out.
+const jsonStreamFlushSize = 32 << 10
+
+// maxPooledJSONBuffer caps the buffer size of pooled jsonMaskers, so one
+// huge document doesn't pin its buffer in memory.
+const maxPooledJSONBuffer = 64 << 10
+
+// jsonMaskerPool reuses jsonMaskers, and with them their output buffer and
+// encoder, across maskJSONDepth calls.
+var jsonMaskerPool = sync.Pool{
+	New: func() any {
+		m := &jsonMasker{}
+		m.enc = json.NewEncoder(&m.buf)
+		return m
+	},
+}
+
+// jsonMasker streams a JSON document from dec into buf, masking string
+// values by the name of the key they belong to.
+type jsonMasker struct {
+	dec      *json.Decoder
+	src      strings.Reader
+	out      io.Writer // Receives buf as it fills when streaming, else nil
+	buf      bytes.Buffer
+	enc      *json.Encoder // Writes to buf
+	maxDepth int
+	recurse  bool           // Mask JSON documents embedded in string values
+	cfg      *MaskingConfig // Configuration of the logger doing the logging
+	count    *maskCounter   // Counts masked values; nil when off
+}
+
+// release returns m to jsonMaskerPool unless its buffer grew too large.
+func (m *jsonMasker) release() {
+	m.dec = nil
+	m.out = nil
+	m.cfg = nil
+	m.count = nil
+	m.src.Reset("")
+	if m.buf.Cap() <= maxPooledJSONBuffer {
+		jsonMaskerPool.Put(m)
+	}
+}
+
+// reset prepares m to mask jsonStr with cfg.
+func (m *jsonMasker) reset(jsonStr string, cfg *MaskingConfig) {
+	m.src.Reset(jsonStr)
+	m.resetReader(&m.src, cfg)
+	m.buf.Grow(len(jsonStr))
+}
+
+// resetReader prepares m to mask the JSON document read from r with cfg.
+func (m *jsonMasker) resetReader(r io.Reader, cfg *MaskingConfig) {
+	m.dec = json.NewDecoder(r)
+	// Keep numbers as their original literals, so large integers
+	// don't lose precision or turn into exponent notation
+	m.dec.UseNumber()
+	m.buf.Reset()
+	m.maxDepth = cfg.depthLimit()
+	m.recurse = cfg.RecurseEncodedJSON
+	m.cfg = cfg
+}
+
+// value copies the next JSON value at the given depth, masking it with mt
+// if it is a string. Objects and arrays at or beyond maxDepth are skipped
+// and replaced with maxDepthPlaceholder.
+func (m *jsonMasker) value(mt maskType, depth int) error {
+	tok, err := m.dec.Token()
 	if err != nil {
-		return jsonStr
+		return err
+	}
+	return m.token(tok, mt, depth)
+}
+
+// token copies the value starting with tok, see value.
+func (m *jsonMasker) token(tok json.Token, mt maskType, depth int) error {
+	switch t := tok.(type) {
+	case json.Delim:
+		if depth >= m.maxDepth {
+			if err := m.skip(); err != nil {
+				return err
+			}
+			return m.write(maxDepthPlaceholder)
+		}
+		if t == '{' {
+			return m.object(depth)
+		}
+		return m.array(mt, depth)
+	case string:
+		if mt == maskNone && m.recurse && looksLikeJSON(t) {
+			// Double-encoded payload: mask the embedded document and re-encode it.
+			// Its root takes the string's place, so MaxDepth still applies
+			if masked, ok := maskJSONDepth(t, maskNone, depth, m.cfg, m.count); ok {
+				return m.write(masked)
+			}
+		}
+		return m.write(m.count.mask(t, mt))
+	default:
+		return m.write(t)
+	}
+}
+
+// object copies the members of an object whose '{' was just read. Members
+// past MaxFields are skipped and replaced with a "<truncated>" marker.
+func (m *jsonMasker) object(depth int) error {
+	m.buf.WriteByte('{')
+	i, truncated := 0, false
+	for n := 0; m.dec.More(); n++ {
+		tok, err := m.dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		if m.cfg.truncates(n) {
+			truncated = true
+			if err := m.skipValue(); err != nil {
+				return err
+			}
+			continue
+		}
+		if m.cfg.redacts(key) {
+			m.count.add()
+			if err := m.skipValue(); err != nil {
+				return err
+			}
+			continue
+		}
+		if i++; i > 1 {
+			m.buf.WriteByte(',')
+		}
+		if err := m.write(key); err != nil {
+			return err
+		}
+		m.buf.WriteByte(':')
+		if err := m.member(key, depth+1); err != nil {
+			return err
+		}
+		if err := m.flush(); err != nil {
+			return err
+		}
+	}
+	// Closing '}'
+	if _, err := m.dec.Token(); err != nil {
+		return err
+	}
+	if truncated {
+		if i > 0 {
+			m.buf.WriteByte(',')
+		}
+		m.buf.WriteString(`"` + truncatedKey + `":true`)
+	}
+	m.buf.WriteByte('}')
+	return nil
+}
+
+// member copies the value of the object member key, masked by the
+// FieldMasker if it handles it and by the key's name otherwise.
+func (m *jsonMasker) member(key string, depth int) error {
+	mt := m.cfg.fieldMask(key)
+	if m.cfg.FieldMasker == nil {
+		return m.value(mt, depth)
+	}
+	// The masker gets the whole value, so it is decoded rather than streamed
+	var raw json.RawMessage
+	if err := m.dec.Decode(&raw); err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	if masked, ok := m.cfg.FieldMasker(key, v); ok {
+		m.count.add()
+		if err := m.write(masked); err != nil {
+			// Never fall back to the unmasked value
+			m.buf.WriteString(`"****"`)
+		}
+		return nil
+	}
+	switch v.(type) {
+	case map[string]any, []any:
+		masked, ok := maskJSONDepth(string(raw), mt, depth, m.cfg, m.count)
+		if !ok {
+			return errors.New("goslogx: invalid JSON value")
+		}
+		m.buf.WriteString(masked)
+		return nil
+	}
+	return m.token(v, mt, depth)
+}
+
+// array copies the elements of an array whose '[' was just read. In
+// MaskingAllowlist mode, string elements get the array's own masking mt.
+func (m *jsonMasker) array(mt maskType, depth int) error {
+	if m.cfg.Mode != MaskingAllowlist {
+		mt = maskNone
+	}
+	m.buf.WriteByte('[')
+	for i := 0; m.dec.More(); i++ {
+		if i > 0 {
+			m.buf.WriteByte(',')
+		}
+		if err := m.value(mt, depth+1); err != nil {
+			return err
+		}
+		if err := m.flush(); err != nil {
+			return err
+		}
+	}
+	// Closing ']'
+	if _, err := m.dec.Token(); err != nil {
+		return err
+	}
+	m.buf.WriteByte(']')
+	return nil
+}
+
+// flush writes buf to out once it reaches jsonStreamFlushSize, when streaming.
+func (m *jsonMasker) flush() error {
+	if m.out == nil || m.buf.Len() < jsonStreamFlushSize {
+		return nil
+	}
+	_, err := m.out.Write(m.buf.Bytes())
+	m.buf.Reset()
+	return err
+}
+
+// skip consumes the rest of an object or array whose opening delimiter was just read.
+func (m *jsonMasker) skip() error {
+	for open := 1; open > 0; {
+		tok, err := m.dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			open++
+		case json.Delim('}'), json.Delim(']'):
+			open--
+		}
+	}
+	return nil
+}
+
+// skipValue consumes the next value, including any nested objects or arrays.
+func (m *jsonMasker) skipValue() error {
+	tok, err := m.dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); ok && (d == '{' || d == '[') {
+		return m.skip()
+	}
+	return nil
+}
+
+// write appends the JSON encoding of a scalar value.
+func (m *jsonMasker) write(v any) error {
+	switch t := v.(type) {
+	case json.Number:
+		// Already validated by the decoder
+		m.buf.WriteString(string(t))
+		return nil
+	case bool:
+		if t {
+			m.buf.WriteString("true")
+		} else {
+			m.buf.WriteString("false")
+		}
+		return nil
+	case nil:
+		m.buf.WriteString("null")
+		return nil
+	}
+	if err := m.enc.Encode(v); err != nil {
+		return err
+	}
+	// Drop the newline Encode terminates each value with
+	m.buf.Truncate(m.buf.Len() - 1)
+	return nil
+}
+
+// looksLikeJSON reports whether s starts like a JSON object or array.
+func looksLikeJSON(s string) bool {
+	s = strings.TrimSpace(s)
+	return len(s) > 1 && (s[0] == '{' || s[0] == '[')
+}
+
+// convert applies the first DataConverter that handles v, reporting
+// whether one did.
+func (c *MaskingConfig) convert(v any) (any, bool) {
+	for _, convert := range c.DataConverters {
+		if converted, ok := convert(v); ok {
+			return converted, true
+		}
+	}
+	return nil, false
+}
+
+// redacts reports whether fields named name are dropped, see RedactFields.
+func (c *MaskingConfig) redacts(name string) bool {
+	return containsFold(c.RedactFields, name)
+}
+
+// truncates reports whether a map is cut short before its key at index i,
+// or with i keys, see MaxFields.
+func (c *MaskingConfig) truncates(i int) bool {
+	return c.MaxFields > 0 && i >= c.MaxFields
+}
+
+// masks reports whether data logged at lvl is masked, see UnmaskedLevels.
+func (c *MaskingConfig) masks(lvl zapcore.Level) bool {
+	return !slices.Contains(c.UnmaskedLevels, lvl)
+}
+
+// redactsHeader reports whether HTTP headers named name are dropped, see
+// RedactHeaders.
+func (c *MaskingConfig) redactsHeader(name string) bool {
+	return containsFold(c.RedactHeaders, name)
+}
+
+// headerMask is fieldMask for HTTP header names: Cookie and Set-Cookie values
+// are masked cookie by cookie, keeping their structure visible.
+func (c *MaskingConfig) headerMask(name string) maskType {
+	if c.Mode != MaskingAllowlist {
+		switch {
+		case strings.EqualFold(name, "Cookie"):
+			return maskCookie
+		case strings.EqualFold(name, "Set-Cookie"):
+			return maskSetCookie
+		}
+	}
+	return c.fieldMask(name)
+}
+
+// fieldMask returns how string values of the field name are masked by name:
+// by the sensitive name patterns, including those of the configured Profiles,
+// or in MaskingAllowlist mode fully unless name is listed in AllowFields.
+func (c *MaskingConfig) fieldMask(name string) maskType {
+	if c.Mode == MaskingAllowlist {
+		return c.allowlistMask(name)
+	}
+	return c.withProfiles(name, shouldMaskField(name))
+}
+
+// byName is fieldMask for a field whose pattern-based result deny is known,
+// e.g. cached in its fieldMeta.
+func (c *MaskingConfig) byName(name string, deny maskType) maskType {
+	if c.Mode == MaskingAllowlist {
+		return c.allowlistMask(name)
+	}
+	return c.withProfiles(name, deny)
+}
+
+// allowlistMask returns maskFull in MaskingAllowlist mode for names not
+// listed in AllowFields, and maskNone otherwise.
+func (c *MaskingConfig) allowlistMask(name string) maskType {
+	if c.Mode != MaskingAllowlist || containsFold(c.AllowFields, name) {
+		return maskNone
+	}
+	return maskFull
+}
+
+// containsFold reports whether name matches one of names, ignoring case.
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// globalMaskingConfig returns the masking configuration of the global logger.
+func globalMaskingConfig() *MaskingConfig {
+	if l := globalLog.Load(); l != nil {
+		return &l.config.Masking
 	}
-	return string(result)
+	return &defaultMaskingConfig
 }
 
-// maskJSONValue recursively masks sensitive fields in JSON data.
+// depthLimit returns MaxDepth, or defaultMaxDepth when it is unset.
+func (c *MaskingConfig) depthLimit() int {
+	if c.MaxDepth > 0 {
+		return c.MaxDepth
+	}
+	return defaultMaxDepth
+}
+
+// maskJSONValue recursively masks sensitive fields in JSON data with the
+// global logger's configuration.
 func maskJSONValue(value interface{}) interface{} {
+	return maskJSONValueDepth(value, 0, globalMaskingConfig())
+}
+
+// maskJSONValueDepth masks JSON data at the given depth with cfg, replacing
+// objects and arrays nested at or beyond its MaxDepth with "<max-depth>".
+func maskJSONValueDepth(value interface{}, depth int, cfg *MaskingConfig) interface{} {
+	maxDepth := cfg.depthLimit()
 	switch v := value.(type) {
 	case map[string]interface{}:
-		return maskJSONMap(v)
+		if depth >= maxDepth {
+			return maxDepthPlaceholder
+		}
+		return maskJSONMapDepth(v, depth, cfg)
 	case []interface{}:
+		if depth >= maxDepth {
+			return maxDepthPlaceholder
+		}
 		// Handle arrays
 		result := make([]interface{}, len(v))
 		for i, item := range v {
-			result[i] = maskJSONValue(item)
+			result[i] = maskJSONValueDepth(item, depth+1, cfg)
 		}
 		return result
 	default:
@@ -317,12 +2043,37 @@ func maskJSONValue(value interface{}) interface{} {
 	}
 }
 
-// maskJSONMap masks sensitive fields in a JSON object (map).
-// Recursively processes nested objects.
+// maskJSONMap masks sensitive fields in a JSON object (map) with the global
+// logger's configuration. Recursively processes nested objects.
 func maskJSONMap(data map[string]interface{}) map[string]interface{} {
+	return maskJSONMapDepth(data, 0, globalMaskingConfig())
+}
+
+// maskJSONMapDepth masks a JSON object at the given depth with cfg. Objects
+// with more than MaxFields keys keep their first keys in sorted order and get
+// a "<truncated>" marker.
+func maskJSONMapDepth(data map[string]interface{}, depth int, cfg *MaskingConfig) map[string]interface{} {
 	result := make(map[string]interface{})
+	if cfg.truncates(len(data)) {
+		keys := slices.Sorted(maps.Keys(data))
+		truncated := make(map[string]interface{}, cfg.MaxFields)
+		for _, key := range keys[:cfg.MaxFields] {
+			truncated[key] = data[key]
+		}
+		data = truncated
+		result[truncatedKey] = true
+	}
 	for key, value := range data {
-		maskType := shouldMaskField(key)
+		if cfg.redacts(key) {
+			continue
+		}
+		if cfg.FieldMasker != nil {
+			if masked, ok := cfg.FieldMasker(key, value); ok {
+				result[key] = masked
+				continue
+			}
+		}
+		maskType := cfg.fieldMask(key)
 		switch v := value.(type) {
 		case string:
 			if maskType == maskFull {
@@ -332,12 +2083,9 @@ func maskJSONMap(data map[string]interface{}) map[string]interface{} {
 			} else {
 				result[key] = v
 			}
-		case map[string]interface{}:
-			// Recursive for nested objects
-			result[key] = maskJSONMap(v)
-		case []interface{}:
-			// Handle arrays
-			result[key] = maskJSONValue(v)
+		case map[string]interface{}, []interface{}:
+			// Recursive for nested objects and arrays
+			result[key] = maskJSONValueDepth(v, depth+1, cfg)
 		default:
 			result[key] = v
 		}
@@ -345,18 +2093,24 @@ func maskJSONMap(data map[string]interface{}) map[string]interface{} {
 	return result
 }
 
-// maskHttpHeaders masks sensitive values in HTTP headers or query parameters.
-// Returns a new map with masked values.
-func maskHttpHeaders(headers map[string][]string) map[string][]string {
+// maskHttpHeaders masks sensitive values in HTTP headers or query parameters
+// with cfg. Returns a new map with masked values.
+func maskHttpHeaders(headers map[string][]string, cfg *MaskingConfig) map[string][]string {
 	result := make(map[string][]string)
 	for key, values := range headers {
-		maskType := shouldMaskField(key)
+		if cfg.redacts(key) || cfg.redactsHeader(key) {
+			continue
+		}
+		maskType := cfg.headerMask(key)
 		if maskType != maskNone && len(values) > 0 {
 			masked := make([]string, len(values))
 			for i, v := range values {
-				if maskType == maskFull {
+				switch maskType {
+				case maskFull:
 					masked[i] = "****"
-				} else {
+				case maskCookie, maskSetCookie:
+					masked[i] = maskString(v, maskType)
+				default:
 					masked[i] = maskMiddle(v)
 				}
 			}
@@ -375,75 +2129,77 @@ func maskHttpHeaders(headers map[string][]string) map[string][]string {
 // Behavior:
 //   - nil → zap.Skip()
 //   - zapcore.ObjectMarshaler → zap.Object()
+//   - ValidationErrors and other zapcore.ArrayMarshalers → zap.Array()
 //   - struct (direct or in interface{}) → zap.Object() with maskedObject wrapper
 //   - slice/array → zap.Array() with maskedArray for struct elements
 //   - other types → zap.Any()
 func dataField(key string, v any) zap.Field {
+	return maskedField(key, v, nil)
+}
+
+// maskedField is dataField with an explicit masking configuration,
+// used by Logger methods so per-logger settings such as MaxDepth apply.
+// A nil cfg means defaultMaskingConfig.
+func maskedField(key string, v any, cfg *MaskingConfig) zap.Field {
+	return countedField(key, v, cfg, nil)
+}
+
+// unmaskedField is maskedField without masking, for levels listed in
+// MaskingConfig.UnmaskedLevels. Values are still converted by
+// DataConverters, so they log the same shape as when masked.
+func unmaskedField(key string, v any, cfg *MaskingConfig) zap.Field {
+	if converted, ok := cfg.convert(v); ok {
+		v = converted
+	}
+	switch val := v.(type) {
+	case nil:
+		return zap.Skip()
+	case RawValue:
+		return zap.Any(key, val.v)
+	case dtoMarshaler:
+		// Skip their MarshalLogObject, which masks
+		return zap.Reflect(key, val)
+	case validationErrors:
+		return zap.Reflect(key, []FieldError(val))
+	}
+	return zap.Any(key, v)
+}
+
+// countedField is maskedField counting the values it masks in count,
+// which may be nil.
+func countedField(key string, v any, cfg *MaskingConfig, count *maskCounter) zap.Field {
 	if v == nil {
 		return zap.Skip()
 	}
+	if cfg != nil && len(cfg.DataConverters) > 0 {
+		if converted, ok := cfg.convert(v); ok {
+			if converted == nil {
+				return zap.Skip()
+			}
+			v = converted
+		}
+	}
 	// Fast path: type switch for common types and ObjectMarshaler
+	// The DTOs and ValidationErrors come before ObjectMarshaler and
+	// ArrayMarshaler, which they implement with the global configuration, so
+	// that they are masked with cfg instead
 	switch val := v.(type) {
+	case RawValue:
+		return zap.Any(key, val.v)
+	case dtoMarshaler:
+		// HTTPData, DBData, MQData and GenericData, or pointers to them
+		return zap.Object(key, maskedObject{v: val, cfg: cfg, count: count})
+	case validationErrors:
+		return zap.Array(key, maskedArray{v: reflect.ValueOf(val), cfg: cfg, count: count})
+	case *GenericDataBuilder:
+		if val == nil {
+			return zap.Skip()
+		}
+		return zap.Object(key, maskedObject{v: val.Build(), cfg: cfg, count: count})
 	case zapcore.ObjectMarshaler:
 		return zap.Object(key, val)
-	case HTTPData:
-		// // Check if HTTPData needs masking
-		// needsMasking := false
-		// if val.Headers != nil {
-		// 	for k := range val.Headers {
-		// 		if shouldMaskField(k) != maskNone {
-		// 			needsMasking = true
-		// 			break
-		// 		}
-		// 	}
-		// }
-		// if !needsMasking && val.Body != nil {
-		// 	if bodyStr, ok := val.Body.(string); ok && len(bodyStr) > 0 && (bodyStr[0] == '{' || bodyStr[0] == '[') {
-		// 		needsMasking = true
-		// 	}
-		// }
-		// if needsMasking {
-		// 	return zap.Object(key, httpDataMasked{val})
-		// }
-		// // No masking needed, use default reflection (faster)
-		// return zap.Any(key, val)
-		return zap.Object(key, maskedObject{val})
-	case *HTTPData:
-		// if val == nil {
-		// 	return zap.Skip()
-		// }
-		// // Check if HTTPData needs masking
-		// needsMasking := false
-		// if val.Headers != nil {
-		// 	for k := range val.Headers {
-		// 		if shouldMaskField(k) != maskNone {
-		// 			needsMasking = true
-		// 			break
-		// 		}
-		// 	}
-		// }
-		// if !needsMasking && val.Body != nil {
-		// 	if bodyStr, ok := val.Body.(string); ok && len(bodyStr) > 0 && (bodyStr[0] == '{' || bodyStr[0] == '[') {
-		// 		needsMasking = true
-		// 	}
-		// }
-		// if needsMasking {
-		// 	return zap.Object(key, httpDataMasked{*val})
-		// }
-		// return zap.Any(key, val)
-		return zap.Object(key, maskedObject{val})
-	case DBData:
-		return zap.Object(key, maskedObject{val})
-	case *DBData:
-		return zap.Object(key, maskedObject{val})
-	case MQData:
-		return zap.Object(key, maskedObject{val})
-	case *MQData:
-		return zap.Object(key, maskedObject{val})
-	case GenericData:
-		return zap.Object(key, maskedObject{val})
-	case *GenericData:
-		return zap.Object(key, maskedObject{val})
+	case zapcore.ArrayMarshaler:
+		return zap.Array(key, val)
 	}
 	// Slow path: use reflection for unknown types
 	rv := reflect.ValueOf(v)
@@ -463,21 +2219,25 @@ func dataField(key string, v any) zap.Field {
 			if elemType.Kind() == reflect.Ptr {
 				elemType = elemType.Elem()
 			}
-			// If elements are structs, use maskedArray for masking
-			if elemType.Kind() == reflect.Struct {
-				return zap.Array(key, maskedArray{rv})
+			// If elements are structs, or may be as in mixed []any slices,
+			// use maskedArray for masking
+			switch elemType.Kind() {
+			case reflect.Struct, reflect.Interface, reflect.Map:
+				return zap.Array(key, maskedArray{v: rv, cfg: cfg, count: count})
 			}
 		}
 		// For empty slices or primitive slices, use zap.Any
 		return zap.Any(key, v)
 	}
-	// If it's a struct, wrap it with maskedObject
+	// If it's a struct, wrap it with maskedObject.
+	// Pointers are passed through so the root is tracked for cycles
 	if rv.Kind() == reflect.Struct {
-		return zap.Object(key, maskedObject{rv.Interface()})
+		return zap.Object(key, maskedObject{v: v, cfg: cfg, count: count})
 	}
-	// For maps, use zap.Any (will be reflected)
-	if rv.Kind() == reflect.Map {
-		return zap.Any(key, v)
+	// For maps with string keys, mask values by key name.
+	// Other maps use zap.Any (will be reflected)
+	if isStringKeyedMap(rv) && !rv.IsNil() {
+		return zap.Object(key, maskedMap{v: rv, cfg: cfg, count: count})
 	}
 	// For other types (primitives, etc), use zap.Any
 	return zap.Any(key, v)
@@ -1,8 +1,13 @@
 package goslogx
 
 import (
+	"io"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+
+	"go.uber.org/zap/zapcore"
 )
 
 // Test maskJSONValue for all types
@@ -152,7 +157,15 @@ func TestGetStructMetaEdgeCases(t *testing.T) {
 				Field2 string `json:"field_2,omitempty"`
 				Field3 string `json:"-"`
 			}{},
-			fields: 3, // All exported fields are included, json:"-" just changes the name
+			fields: 2, // json:"-" fields are omitted
+		},
+		{
+			name: "Struct with json dash name",
+			value: struct {
+				Field1 string `json:"-,"`
+				Field2 string
+			}{},
+			fields: 2, // json:"-," names the field "-"
 		},
 		{
 			name: "Struct with masking tags",
@@ -232,6 +245,95 @@ func TestMaskJSONStringEdgeCases(t *testing.T) {
 	}
 }
 
+// Test that maskJSONString preserves key order and yields a fixed output
+func TestMaskJSONStringKeyOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Unsorted keys",
+			input:    `{"zeta":1,"password":"secret","alpha":"a","email":"john.doe@example.com"}`,
+			expected: `{"zeta":1,"password":"****","alpha":"a","email":"jo****om"}`,
+		},
+		{
+			name:     "Nested objects and arrays",
+			input:    `{"user":{"name":"John","token":"t"},"items":[{"b":2,"a":1}],"ok":true,"none":null}`,
+			expected: `{"user":{"name":"John","token":"****"},"items":[{"b":2,"a":1}],"ok":true,"none":null}`,
+		},
+		{
+			name:     "Whitespace is compacted",
+			input:    "{\n  \"b\": 1,\n  \"a\": [1, 2]\n}",
+			expected: `{"b":1,"a":[1,2]}`,
+		},
+		{
+			name:     "Trailing data",
+			input:    `{"password":"secret"} {"password":"secret"}`,
+			expected: `{"password":"secret"} {"password":"secret"}`,
+		},
+		{
+			name:     "Truncated",
+			input:    `{"password":"secret"`,
+			expected: `{"password":"secret"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Repeat to catch any nondeterministic ordering
+			for i := 0; i < 10; i++ {
+				if result := maskJSONString(tt.input); result != tt.expected {
+					t.Fatalf("maskJSONString(%s) = %s, want %s", tt.input, result, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+// Test that maskJSONString keeps numeric literals exactly
+func TestMaskJSONStringNumericPrecision(t *testing.T) {
+	input := `{"id":10000000000000001,"amount":1234567.891234567,"rate":1e-7,"neg":-0.50,"password":"secret"}`
+	expected := `{"id":10000000000000001,"amount":1234567.891234567,"rate":1e-7,"neg":-0.50,"password":"****"}`
+	if result := maskJSONString(input); result != expected {
+		t.Errorf("maskJSONString(%s) = %s, want %s", input, result, expected)
+	}
+}
+
+// Test masking of JSON documents embedded in string values
+func TestMaskJSONStringEncodedJSON(t *testing.T) {
+	input := `{"payload":"{\"user\":{\"password\":\"secret\"},\"id\":1}","token":"{\"a\":1}","note":"{not json"}`
+
+	t.Run("Disabled", func(t *testing.T) {
+		if result := maskJSONString(input); result != `{"payload":"{\"user\":{\"password\":\"secret\"},\"id\":1}","token":"****","note":"{not json"}` {
+			t.Errorf("Expected embedded JSON to be left as-is, got %s", result)
+		}
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		prev := globalLog.Load()
+		defer globalLog.Store(prev)
+		globalLog.Store(setupLog(WithOutput(io.Discard), WithRecurseEncodedJSON(true)))
+
+		expected := `{"payload":"{\"user\":{\"password\":\"****\"},\"id\":1}","token":"****","note":"{not json"}`
+		if result := maskJSONString(input); result != expected {
+			t.Errorf("maskJSONString() = %s, want %s", result, expected)
+		}
+	})
+
+	t.Run("MaxDepth", func(t *testing.T) {
+		prev := globalLog.Load()
+		defer globalLog.Store(prev)
+		globalLog.Store(setupLog(WithOutput(io.Discard), WithRecurseEncodedJSON(true), WithMaskingMaxDepth(2)))
+
+		// The embedded object sits at depth 1 and its "user" object at depth 2
+		expected := `{"payload":"{\"user\":\"\\u003cmax-depth\\u003e\",\"id\":1}","token":"****","note":"{not json"}`
+		if result := maskJSONString(input); result != expected {
+			t.Errorf("maskJSONString() = %s, want %s", result, expected)
+		}
+	})
+}
+
 // Test maskJSONMap edge cases
 func TestMaskJSONMapEdgeCases(t *testing.T) {
 	tests := []struct {
@@ -320,6 +422,90 @@ func TestShouldMaskFieldPatterns(t *testing.T) {
 }
 
 // Test maskMiddle edge cases
+// shouldMaskFieldLinear is the original linear scan, kept as the reference
+// the precomputed fieldMatcher must agree with.
+func shouldMaskFieldLinear(fieldName string) maskType {
+	lower := strings.ToLower(strings.ReplaceAll(fieldName, "-", "_"))
+	for _, pattern := range fullMaskFields {
+		if strings.Contains(lower, pattern) {
+			return maskFull
+		}
+	}
+	for _, pattern := range partialMaskFields {
+		if strings.Contains(lower, pattern) {
+			return maskPartial
+		}
+	}
+	return maskNone
+}
+
+func TestShouldMaskFieldMatchesLinearScan(t *testing.T) {
+	fields := []string{
+		"", "id", "name", "full_name", "normal_field", "created_at",
+		"Password", "X-Auth-Token", "USER-EMAIL", "userEmail", "api_key", "API-KEY",
+		"user_token_email", "email_password", "phone_secret", "my_pwd_hash",
+		"Authorization", "x-api-key", "client_id", "ClientID", "mobile_number",
+		"private_key_pem", "access_key_id", "SECRET_\u212aEY", "pässword", "émail",
+		strings.Repeat("a", 100) + "_token", strings.Repeat("b", 100),
+	}
+	for _, p := range append(append([]string(nil), fullMaskFields...), partialMaskFields...) {
+		fields = append(fields, p, strings.ToUpper(p), "x_"+p+"_y")
+	}
+	for _, field := range fields {
+		if got, want := shouldMaskField(field), shouldMaskFieldLinear(field); got != want {
+			t.Errorf("shouldMaskField(%q) = %v, linear scan = %v", field, got, want)
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, func() { shouldMaskField("X-Request-Id") }); allocs != 0 {
+		t.Errorf("Expected no allocations for ASCII names, got %.0f", allocs)
+	}
+}
+
+func TestFieldMatcherCache(t *testing.T) {
+	m := newFieldMatcher(fullMaskFields, partialMaskFields)
+	fields := []string{"X-Auth-Token", "user_email", "created_at", "pässword", "x_api_key_y"}
+	for i := 0; i < 3; i++ {
+		for _, field := range fields {
+			if got, want := m.match(field), shouldMaskFieldLinear(field); got != want {
+				t.Errorf("Pass %d: match(%q) = %v, linear scan = %v", i, field, got, want)
+			}
+		}
+	}
+
+	t.Run("Bounded", func(t *testing.T) {
+		m := newFieldMatcher(fullMaskFields, partialMaskFields)
+		for i := 0; i < 2*maxFieldCacheSize; i++ {
+			m.match("field_" + strconv.Itoa(i))
+		}
+		var n int
+		m.cache.Range(func(_, _ any) bool { n++; return true })
+		if n > maxFieldCacheSize || m.cacheSize.Load() > maxFieldCacheSize {
+			t.Errorf("Expected at most %d cached names, got %d (counter %d)", maxFieldCacheSize, n, m.cacheSize.Load())
+		}
+		if got := m.match("session_token"); got != maskFull {
+			t.Errorf("Expected full mask after eviction, got %v", got)
+		}
+	})
+}
+
+func BenchmarkShouldMaskField_Repeated(b *testing.B) {
+	fields := []string{"X-Request-Id", "Content-Type", "user_agent", "created_at", "order_total"}
+	b.Run("Cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			shouldMaskField(fields[i%len(fields)])
+		}
+	})
+	b.Run("Scan", func(b *testing.B) {
+		var buf [64]byte
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			lower, _ := normalizeFieldName(buf[:0], fields[i%len(fields)])
+			sensitiveFields.scan(lower)
+		}
+	})
+}
+
 func TestMaskMiddleEdgeCases(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -342,3 +528,102 @@ func TestMaskMiddleEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+// Test maskEmail with valid and invalid addresses
+func TestMaskEmail(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Valid email", "john.doe@example.com", "jo****@example.com"},
+		{"Short local part", "jd@example.com", "****@example.com"},
+		{"No at sign", "not-an-email", "****"},
+		{"Multiple at signs", "a@b@example.com", "****"},
+		{"Empty local part", "@example.com", "****"},
+		{"Empty domain", "john@", "****"},
+		{"Empty string", "", "****"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := maskEmail(tt.input)
+			if result != tt.expected {
+				t.Errorf("maskEmail(%s) = %s, want %s", tt.input, result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("StructTag", func(t *testing.T) {
+		type contact struct {
+			Email string `json:"email" log:"masked:email"`
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		if err := (maskedObject{v: contact{Email: "john.doe@example.com"}}).MarshalLogObject(enc); err != nil {
+			t.Fatalf("MarshalLogObject failed: %v", err)
+		}
+		if enc.Fields["email"] != "jo****@example.com" {
+			t.Errorf("Expected email tag to keep the domain, got %v", enc.Fields["email"])
+		}
+	})
+}
+
+// Test maskPhone with several number formats
+func TestMaskPhone(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"International", "+6281234567890", "+62********90"},
+		{"International with separators", "+62 812-3456-7890", "+62********90"},
+		{"Separators in visible suffix", "+44 (20) 7946 0 9", "+44********0 9"},
+		{"No leading plus", "081234567890", "********90"},
+		{"Dotted", "0812.3456.7890", "********90"},
+		{"Too short", "12345", "****"},
+		{"Too short with plus", "+62 123", "****"},
+		{"Letters", "+62 812 CALL ME", "****"},
+		{"Plus not leading", "62+81234567890", "****"},
+		{"Empty string", "", "****"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := maskPhone(tt.input)
+			if result != tt.expected {
+				t.Errorf("maskPhone(%s) = %s, want %s", tt.input, result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("StructTag", func(t *testing.T) {
+		type contact struct {
+			Mobile string `json:"mobile" log:"masked:phone"`
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		if err := (maskedObject{v: contact{Mobile: "+6281234567890"}}).MarshalLogObject(enc); err != nil {
+			t.Fatalf("MarshalLogObject failed: %v", err)
+		}
+		if enc.Fields["mobile"] != "+62********90" {
+			t.Errorf("Expected phone tag masking, got %v", enc.Fields["mobile"])
+		}
+	})
+}
+
+// TestMaskJSONStringAllocs guards the pooled masker: before output buffers and
+// encoders were reused, masking this document took 30 allocations.
+func TestMaskJSONStringAllocs(t *testing.T) {
+	if raceEnabled || testing.Short() {
+		t.Skip("allocation counts are only stable in full runs without -race")
+	}
+	const doc = `{"username":"admin@example.com","password":"secret123"}`
+	want := maskJSONString(doc)
+	allocs := testing.AllocsPerRun(100, func() {
+		if got := maskJSONString(doc); got != want {
+			t.Fatalf("Expected stable output %s, got %s", want, got)
+		}
+	})
+	if allocs > 26 {
+		t.Errorf("Expected at most 26 allocations per call, got %.0f", allocs)
+	}
+}
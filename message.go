@@ -0,0 +1,153 @@
+package goslogx
+
+import (
+	"strings"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// messagePunct holds the characters trimmed from the ends of a message word
+// before it is scanned, such as quotes and trailing commas.
+const messagePunct = "\"'`()[]{}<>,;.!?"
+
+// messageEncoder masks sensitive values in entry messages before passing
+// them to the wrapped encoder, see MaskingConfig.ScanMessage.
+type messageEncoder struct {
+	zapcore.Encoder
+}
+
+// Clone implements zapcore.Encoder.
+func (e messageEncoder) Clone() zapcore.Encoder {
+	return messageEncoder{Encoder: e.Encoder.Clone()}
+}
+
+// EncodeEntry implements zapcore.Encoder.
+func (e messageEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	ent.Message = scrubMessage(ent.Message)
+	return e.Encoder.EncodeEntry(ent, fields)
+}
+
+// scrubMessage masks sensitive values in the free-form message msg: payment
+// card numbers, words that look like an email or phone number, as
+// maskSensitiveValue does, and values following a sensitive key, as in
+// "password=hunter2" or "token: abc123". Keys are matched by the sensitive
+// name patterns.
+//
+// Examples:
+//   - "login failed for john.doe@example.com" → "login failed for jo****@example.com"
+//   - "auth with password=hunter2 failed" → "auth with password=**** failed"
+//   - "charged 4111 1111 1111 1111" → "charged ****1111"
+func scrubMessage(msg string) string {
+	msg = scrubCardNumbers(msg)
+	var b strings.Builder
+	last, changed := 0, false
+	pending := maskNone // Mask for the word following a "key:" word
+	for start := 0; start < len(msg); {
+		if isMessageSpace(msg[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(msg) && !isMessageSpace(msg[end]) {
+			end++
+		}
+		i, j := trimPunct(msg, start, end)
+		start = end
+		word := msg[i:j]
+		if word == "" {
+			continue
+		}
+		var masked string
+		masked, pending = scrubWord(word, pending)
+		if masked != word {
+			b.WriteString(msg[last:i])
+			b.WriteString(masked)
+			last, changed = j, true
+		}
+	}
+	if !changed {
+		return msg
+	}
+	b.WriteString(msg[last:])
+	return b.String()
+}
+
+// scrubWord masks the message word with pending if it follows a sensitive
+// key, and otherwise by its key or value. Returns the mask for the next word,
+// which is set when word is a sensitive key ending in ':' or '='.
+func scrubWord(word string, pending maskType) (string, maskType) {
+	if pending != maskNone {
+		return maskString(word, pending), maskNone
+	}
+	if k := strings.IndexAny(word, "=:"); k > 0 {
+		if mt := shouldMaskField(word[:k]); mt != maskNone {
+			value := word[k+1:]
+			if value == "" {
+				return word, mt
+			}
+			i, j := trimPunct(value, 0, len(value))
+			return word[:k+1] + value[:i] + maskString(value[i:j], mt) + value[j:], maskNone
+		}
+	}
+	if masked, ok := maskRecognizedValue(word); ok {
+		return masked, maskNone
+	}
+	return word, maskNone
+}
+
+// scrubCardNumbers masks payment card numbers in s, which may be grouped with
+// single spaces or dashes, keeping their last 4 digits.
+func scrubCardNumbers(s string) string {
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			continue
+		}
+		// Extend the run over digits and single separators between digits
+		end := i + 1
+		for end < len(s) {
+			if isDigit(s[end]) {
+				end++
+			} else if (s[end] == ' ' || s[end] == '-') && end+1 < len(s) && isDigit(s[end+1]) {
+				end += 2
+			} else {
+				break
+			}
+		}
+		if looksLikeCard(s[i:end]) {
+			b.WriteString(s[last:i])
+			b.WriteString("****" + lastDigits(s[i:end], 4))
+			last = end
+		}
+		i = end
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// trimPunct returns the bounds of s[start:end] without leading and trailing
+// messagePunct characters.
+func trimPunct(s string, start, end int) (int, int) {
+	for start < end && strings.IndexByte(messagePunct, s[start]) >= 0 {
+		start++
+	}
+	for end > start && strings.IndexByte(messagePunct, s[end-1]) >= 0 {
+		end--
+	}
+	return start, end
+}
+
+// isMessageSpace reports whether c separates words in a message.
+func isMessageSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// isDigit reports whether c is an ASCII digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,58 @@
+package goslogx
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestScrubMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{"Email", "login failed for john.doe@example.com.", "login failed for jo****@example.com."},
+		{"KeyValue", "failed to auth with password=hunter2", "failed to auth with password=****"},
+		{"QuotedValue", `retrying with token="abc123", attempt 2`, `retrying with token="****", attempt 2`},
+		{"KeyColon", "api_key: sk-live-123 rejected", "api_key: sk****23 rejected"},
+		{"CardGrouped", "charged 4111 1111 1111 1111 for order 42", "charged ****1111 for order 42"},
+		{"CardPlain", "card 4111111111111111", "card ****1111"},
+		{"Phone", "sms sent to +6281234567890", "sms sent to +62********90"},
+		{"NotACard", "order 1234567890123 shipped", "order 1234567890123 shipped"},
+		{"Plain", "user created: id=42 status=active", "user created: id=42 status=active"},
+		{"URL", "GET https://example.com/health", "GET https://example.com/health"},
+		{"Empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scrubMessage(tt.msg); got != tt.want {
+				t.Errorf("scrubMessage(%q) = %q, want %q", tt.msg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageScanning(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := setupLog(
+		WithOutput(buf),
+		WithMessageScanning(true),
+		WithSensitiveValues([]string{"tok_9f8e7d6c"}),
+	)
+	logger.Info("trace-1", "auth", MESSAGE_TYPE_EVENT, "reset link for jane.doe@example.com sent with tok_9f8e7d6c", nil)
+	logger.Warning("trace-2", "auth", "login failed for jane.doe@example.com", nil)
+
+	entries := decodeEntries(t, buf.Bytes())
+	if msg := entries[0][KeyMessage]; msg != "reset link for ja****@example.com sent with ****" {
+		t.Errorf("Expected the email and registered secret to be masked, got %v", msg)
+	}
+	if msg := entries[1][KeyMessage]; msg != "login failed for ja****@example.com" {
+		t.Errorf("Expected the email to be masked, got %v", msg)
+	}
+
+	buf.Reset()
+	setupLog(WithOutput(buf)).Info("trace-3", "auth", MESSAGE_TYPE_EVENT, "sent to jane.doe@example.com", nil)
+	if msg := decodeEntries(t, buf.Bytes())[0][KeyMessage]; msg != "sent to jane.doe@example.com" {
+		t.Errorf("Expected messages to be logged verbatim by default, got %v", msg)
+	}
+}
@@ -0,0 +1,331 @@
+package goslogx
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Default settings for the HTTP middleware.
+const (
+	// DefaultTraceHeader is the request header the middleware reads the trace ID from.
+	DefaultTraceHeader = "X-Trace-Id"
+	// DefaultMaxBodySize is the maximum number of body bytes captured for logging.
+	DefaultMaxBodySize = 64 << 10
+)
+
+// MiddlewareConfig configures the HTTP logging middleware.
+// Zero values fall back to the defaults documented on each field.
+type MiddlewareConfig struct {
+	// Logger is the logger entries are written to.
+	// Default: the global logger
+	Logger *Logger
+
+	// Module is the module name attached to every entry.
+	// Default: "http"
+	Module string
+
+	// TraceHeader is the request header holding the trace ID.
+	// A new trace ID is generated when the header is absent.
+	// Default: DefaultTraceHeader
+	TraceHeader string
+
+	// MaxBodySize caps how many request/response body bytes are buffered for logging.
+	// Bodies larger than this are not logged, to avoid buffering huge uploads
+	// and leaking unmasked fragments of truncated JSON. Negative disables body capture.
+	// Default: DefaultMaxBodySize
+	MaxBodySize int
+
+	// TrustedProxies lists the reverse proxies and load balancers in front of
+	// the service, as IPs or CIDR ranges such as "10.0.0.0/8". X-Forwarded-For
+	// and X-Real-IP are only honoured on requests from these, since any client
+	// can set them; the logged client IP is then the rightmost X-Forwarded-For
+	// hop that is not a trusted proxy. Entries that fail to parse are ignored.
+	// Default: none (the client IP is the connection's remote address)
+	TrustedProxies []string
+}
+
+// Middleware logs every HTTP request and response using the default MiddlewareConfig.
+// See NewMiddleware for details.
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	http.ListenAndServe(":8080", goslogx.Middleware(mux))
+func Middleware(next http.Handler) http.Handler {
+	return NewMiddleware(MiddlewareConfig{})(next)
+}
+
+// NewMiddleware returns an http.Handler middleware that logs a REQUEST entry when
+// a request arrives and a RESPONSE entry when the handler completes.
+// Entries use HTTPData with masked headers and JSON bodies, the client IP,
+// the response status code, and the request duration.
+//
+// The trace ID is read from cfg.TraceHeader, or generated when absent.
+// It is set on the request header (so handlers can read it) and echoed on the response.
+//
+// Example:
+//
+//	handler := goslogx.NewMiddleware(goslogx.MiddlewareConfig{
+//	    Module:         "api-gateway",
+//	    TraceHeader:    "X-Request-Id",
+//	    MaxBodySize:    4096,
+//	    TrustedProxies: []string{"10.0.0.0/8"},
+//	})(mux)
+func NewMiddleware(cfg MiddlewareConfig) func(http.Handler) http.Handler {
+	if cfg.Module == "" {
+		cfg.Module = "http"
+	}
+	if cfg.TraceHeader == "" {
+		cfg.TraceHeader = DefaultTraceHeader
+	}
+	if cfg.MaxBodySize == 0 {
+		cfg.MaxBodySize = DefaultMaxBodySize
+	}
+	trusted := parseTrustedProxies(cfg.TrustedProxies)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger := cfg.Logger
+			if logger == nil {
+				logger = globalLog.Load()
+			}
+			start := time.Now()
+
+			traceID := r.Header.Get(cfg.TraceHeader)
+			if traceID == "" {
+				traceID = newTraceID()
+				r.Header.Set(cfg.TraceHeader, traceID)
+			}
+			w.Header().Set(cfg.TraceHeader, traceID)
+
+			clientIP := requestClientIP(r, trusted)
+			logger.Info(traceID, cfg.Module, MESSAGE_TYPE_REQUEST, "request received", HTTPData{
+				Method:   r.Method,
+				URL:      r.URL.RequestURI(),
+				Headers:  r.Header,
+				Body:     captureRequestBody(r, cfg.MaxBodySize),
+				ClientIP: clientIP,
+			})
+
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK, max: cfg.MaxBodySize}
+			next.ServeHTTP(rec, r)
+			elapsed := time.Since(start)
+
+			logger.Info(traceID, cfg.Module, MESSAGE_TYPE_RESPONSE, "request completed", HTTPData{
+				Method:     r.Method,
+				URL:        r.URL.RequestURI(),
+				StatusCode: rec.status,
+				Headers:    rec.Header(),
+				Body:       rec.loggedBody(),
+				Duration:   FormatDuration(elapsed),
+				DurationMs: DurationMillis(elapsed),
+				ClientIP:   clientIP,
+			})
+		})
+	}
+}
+
+// captureRequestBody reads up to max bytes of the request body for logging,
+// see loggableBody, and restores r.Body so the handler still sees the full
+// payload. Returns nil when there is no body or capture is disabled.
+func captureRequestBody(r *http.Request, max int) any {
+	if r.Body == nil || r.Body == http.NoBody || max < 0 {
+		return nil
+	}
+	captured, err := io.ReadAll(io.LimitReader(r.Body, int64(max)+1))
+	r.Body = readCloser{io.MultiReader(bytes.NewReader(captured), r.Body), r.Body}
+	if err != nil || len(captured) == 0 {
+		return nil
+	}
+	if len(captured) > max {
+		return truncatedBody(max)
+	}
+	return loggableBody(r.Header.Get("Content-Type"), captured)
+}
+
+// loggableBody returns body as logged for its Content-Type. JSON is logged
+// as is and masked by field name like any HTTPData.Body, and form-encoded
+// bodies as a map masked by key, like a map passed as data. Other media
+// types, such as multipart uploads or plain text, can't be masked field by
+// field, so only their type and size are logged.
+func loggableBody(contentType string, body []byte) any {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return string(body)
+	case mediaType == "application/x-www-form-urlencoded":
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			return formBody(form)
+		}
+	case (mediaType == "" || mediaType == "text/plain") && looksLikeJSON(string(body)):
+		// Handlers often write JSON without setting a Content-Type, which
+		// leaves it empty or sniffed as text/plain
+		return string(body)
+	}
+	if mediaType == "" {
+		return fmt.Sprintf("<body of %d bytes>", len(body))
+	}
+	return fmt.Sprintf("<%s body of %d bytes>", mediaType, len(body))
+}
+
+// formBody returns form as a map to log, with single values unwrapped from
+// their slice.
+func formBody(form url.Values) map[string]any {
+	fields := make(map[string]any, len(form))
+	for k, v := range form {
+		if len(v) == 1 {
+			fields[k] = v[0]
+		} else {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// truncatedBody is the placeholder logged for bodies larger than the capture limit.
+func truncatedBody(max int) string {
+	return fmt.Sprintf("<body exceeds %d bytes>", max)
+}
+
+// readCloser pairs a replacement reader with the original body's Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// responseRecorder captures the status code and a bounded copy of the response body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	max         int
+	truncated   bool
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter, copying up to max bytes for logging.
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	if r.max >= 0 && !r.truncated {
+		if r.body.Len()+len(p) > r.max {
+			r.truncated = true
+			r.body.Reset()
+		} else {
+			r.body.Write(p)
+		}
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher when the underlying writer supports it.
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// loggedBody returns the response body as logged for its Content-Type, see
+// loggableBody, a placeholder if it was too large, or nil if nothing was
+// written.
+func (r *responseRecorder) loggedBody() any {
+	if r.truncated {
+		return truncatedBody(r.max)
+	}
+	if r.body.Len() == 0 {
+		return nil
+	}
+	return loggableBody(r.Header().Get("Content-Type"), r.body.Bytes())
+}
+
+// requestClientIP returns the originating client IP: the connection's remote
+// address, or, when that is one of the trusted proxies, the rightmost
+// X-Forwarded-For hop that is not, falling back to X-Real-IP.
+func requestClientIP(r *http.Request, trusted []netip.Prefix) string {
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+	if !isTrustedProxy(remote, trusted) {
+		return remote
+	}
+	// Hops are appended by each proxy, so the ones to the left of the last
+	// untrusted hop may have been set by the client
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if i == 0 || !isTrustedProxy(hop, trusted) {
+			return hop
+		}
+	}
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+		return ip
+	}
+	return remote
+}
+
+// parseTrustedProxies parses MiddlewareConfig.TrustedProxies, skipping
+// entries that are neither an IP nor a CIDR range.
+func parseTrustedProxies(proxies []string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, p := range proxies {
+		p = strings.TrimSpace(p)
+		if prefix, err := netip.ParsePrefix(p); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(p); err == nil {
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+	return prefixes
+}
+
+// isTrustedProxy reports whether ip falls within one of the trusted ranges.
+func isTrustedProxy(ip string, trusted []netip.Prefix) bool {
+	if len(trusted) == 0 {
+		return false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// newTraceID generates a random 128-bit trace ID encoded as 32 hex characters.
+func newTraceID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,198 @@
+package goslogx
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// decodeEntries unmarshals newline-delimited JSON log entries.
+func decodeEntries(t *testing.T, b []byte) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(b), []byte("\n")) {
+		if len(line) > 0 {
+			entries = append(entries, decodeEntry(t, line))
+		}
+	}
+	return entries
+}
+
+func TestMiddleware(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := setupLog(WithOutput(buf))
+
+	var seenBody, seenTrace string
+	// httptest requests come from 192.0.2.1
+	handler := NewMiddleware(MiddlewareConfig{Logger: logger, Module: "api", TrustedProxies: []string{"192.0.2.1", "10.0.0.0/8"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		seenBody = string(b)
+		seenTrace = r.Header.Get(DefaultTraceHeader)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"token":"newtoken","id":"usr-001"}`))
+	}))
+
+	t.Run("RequestAndResponse", func(t *testing.T) {
+		buf.Reset()
+		body := `{"username":"johndoe123","password":"supersecret"}`
+		req := httptest.NewRequest(http.MethodPost, "/login?x=1", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer abc")
+		req.Header.Set(DefaultTraceHeader, "trace-123")
+		req.Header.Set("X-Forwarded-For", "203.0.113.42, 10.0.0.1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if seenBody != body {
+			t.Errorf("Handler should see the full body, got %q", seenBody)
+		}
+		out := buf.String()
+		for _, leaked := range []string{"supersecret", "Bearer abc", "newtoken"} {
+			if strings.Contains(out, leaked) {
+				t.Errorf("Sensitive value %q leaked: %s", leaked, out)
+			}
+		}
+
+		entries := decodeEntries(t, buf.Bytes())
+		if len(entries) != 2 {
+			t.Fatalf("Expected 2 entries, got %d: %s", len(entries), out)
+		}
+		req0, res := entries[0], entries[1]
+		if req0["msg_type"] != "REQUEST" || res["msg_type"] != "RESPONSE" {
+			t.Errorf("Unexpected msg types: %v / %v", req0["msg_type"], res["msg_type"])
+		}
+		if req0["trace_id"] != "trace-123" || res["trace_id"] != "trace-123" || req0["module"] != "api" {
+			t.Errorf("Unexpected trace/module: %v / %v", req0, res)
+		}
+		reqData := req0["data"].(map[string]any)
+		if reqData["method"] != "POST" || reqData["url"] != "/login?x=1" || reqData["client_ip"] != "203.0.113.42" {
+			t.Errorf("Unexpected request data: %v", reqData)
+		}
+		resData := res["data"].(map[string]any)
+		if resData["status_code"] != float64(http.StatusCreated) || resData["duration"] == "" {
+			t.Errorf("Unexpected response data: %v", resData)
+		}
+		if ms, ok := resData["duration_ms"].(float64); !ok || ms <= 0 {
+			t.Errorf("Unexpected response data: %v", resData)
+		}
+		if rec.Header().Get(DefaultTraceHeader) != "trace-123" {
+			t.Errorf("Expected trace ID echoed on response, got %q", rec.Header().Get(DefaultTraceHeader))
+		}
+	})
+
+	t.Run("GeneratedTraceID", func(t *testing.T) {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		entries := decodeEntries(t, buf.Bytes())
+		traceID, _ := entries[0]["trace_id"].(string)
+		if len(traceID) != 32 || traceID != seenTrace || entries[1]["trace_id"] != traceID {
+			t.Errorf("Expected a generated 32-char trace ID shared by handler and entries, got %q / %q", traceID, seenTrace)
+		}
+	})
+
+	t.Run("MaxBodySize", func(t *testing.T) {
+		buf.Reset()
+		small := NewMiddleware(MiddlewareConfig{Logger: logger, MaxBodySize: 8})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			seenBody = string(b)
+			_, _ = w.Write([]byte("0123456789"))
+		}))
+		body := `{"password":"supersecret"}`
+		small.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+		if seenBody != body {
+			t.Errorf("Handler should see the full body, got %q", seenBody)
+		}
+		if strings.Contains(buf.String(), "supersecret") || strings.Contains(buf.String(), "0123456789") {
+			t.Errorf("Oversized bodies should not be logged: %s", buf.String())
+		}
+		if strings.Count(buf.String(), "<body exceeds 8 bytes>") != 2 {
+			t.Errorf("Expected truncation placeholders, got %s", buf.String())
+		}
+	})
+}
+
+func TestMiddlewareBodies(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := setupLog(WithOutput(buf))
+	handler := NewMiddleware(MiddlewareConfig{Logger: logger})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte("<p>welcome back, johndoe123</p>"))
+	}))
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        any
+	}{
+		{"JSON", "application/json", `{"password":"supersecret"}`, `{"password":"****"}`},
+		{"JSONWithoutType", "", `{"password":"supersecret"}`, `{"password":"****"}`},
+		{"Form", "application/x-www-form-urlencoded", "username=johndoe123&password=supersecret&remember=1&tag=a&tag=b",
+			map[string]any{"username": "jo****23", "password": "****", "remember": "1", "tag": []any{"a", "b"}}},
+		{"Multipart", "multipart/form-data; boundary=x", "--x\r\nContent-Disposition: form-data; name=\"password\"\r\n\r\nsupersecret\r\n--x--",
+			"<multipart/form-data body of 74 bytes>"},
+		{"PlainText", "text/plain", "password=supersecret", "<text/plain body of 20 bytes>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			if strings.Contains(buf.String(), "supersecret") {
+				t.Errorf("Password leaked: %s", buf.String())
+			}
+			entries := decodeEntries(t, buf.Bytes())
+			if got := entries[0]["data"].(map[string]any)["body"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected request body %v, got %v", tt.want, got)
+			}
+			if got := entries[1]["data"].(map[string]any)["body"]; got != "<text/html body of 31 bytes>" {
+				t.Errorf("Expected response body placeholder, got %v", got)
+			}
+		})
+	}
+}
+
+func TestRequestClientIP(t *testing.T) {
+	trusted := parseTrustedProxies([]string{"3.3.3.3", "10.0.0.0/8", "not-an-ip", "2001:db8::/32"})
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		remote   string
+		trusted  []netip.Prefix
+		expected string
+	}{
+		{"RemoteAddr", nil, "3.3.3.3:1234", nil, "3.3.3.3"},
+		{"RemoteAddrNoPort", nil, "3.3.3.3", nil, "3.3.3.3"},
+		{"UntrustedForwardedFor", map[string]string{"X-Forwarded-For": "1.1.1.1"}, "5.5.5.5:1234", trusted, "5.5.5.5"},
+		{"UntrustedRealIP", map[string]string{"X-Real-IP": "4.4.4.4"}, "5.5.5.5:1234", trusted, "5.5.5.5"},
+		{"NoTrustedProxies", map[string]string{"X-Forwarded-For": "1.1.1.1"}, "3.3.3.3:1234", nil, "3.3.3.3"},
+		{"ForwardedFor", map[string]string{"X-Forwarded-For": "1.1.1.1"}, "3.3.3.3:1234", trusted, "1.1.1.1"},
+		{"SpoofedHop", map[string]string{"X-Forwarded-For": "6.6.6.6, 1.1.1.1, 10.1.2.3"}, "3.3.3.3:1234", trusted, "1.1.1.1"},
+		{"AllHopsTrusted", map[string]string{"X-Forwarded-For": "10.0.0.1, 10.0.0.2"}, "3.3.3.3:1234", trusted, "10.0.0.1"},
+		{"IPv6Proxy", map[string]string{"X-Forwarded-For": "1.1.1.1"}, "[2001:db8::1]:443", trusted, "1.1.1.1"},
+		{"RealIP", map[string]string{"X-Real-IP": "4.4.4.4"}, "3.3.3.3:1234", trusted, "4.4.4.4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			if ip := requestClientIP(req, tt.trusted); ip != tt.expected {
+				t.Errorf("requestClientIP() = %s, want %s", ip, tt.expected)
+			}
+		})
+	}
+}
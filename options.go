@@ -1,8 +1,10 @@
 package goslogx
 
 import (
+	"context"
 	"io"
 	"os"
+	"time"
 
 	"go.uber.org/zap/zapcore"
 )
@@ -13,6 +15,25 @@ type Config struct {
 	// It will be included in all log entries as "application_name".
 	ServiceName string
 
+	// Module is the module logged when a call passes an empty module.
+	// Default: "" (empty)
+	Module string
+
+	// HostInfo adds "hostname" and "pid" fields to every entry, resolved
+	// once when the logger is created. Set via WithHostInfo.
+	// Default: false
+	HostInfo bool
+
+	// Sequence adds a "seq" field numbering written entries 1, 2, 3, and so
+	// on, shared by loggers derived with With. Set via WithSequence.
+	// Default: false
+	Sequence bool
+
+	// StrictMsgType makes Info and Debug log unrecognized message types as
+	// MESSAGE_TYPE_UNKNOWN, warning once per value. Set via WithStrictMsgType.
+	// Default: false
+	StrictMsgType bool
+
 	// Level is the minimum log level that will be output.
 	// Default: zapcore.InfoLevel
 	Level zapcore.Level
@@ -21,10 +42,166 @@ type Config struct {
 	// Default: os.Stdout
 	Output io.Writer
 
+	// Outputs are additional writers that receive every log entry.
+	// When non-empty, Outputs replaces Output. Set via WithOutputs.
+	Outputs []io.Writer
+
+	// ErrorOutput, when set, receives Warning, Error and Fatal entries
+	// while Info and Debug entries keep going to Output/Outputs.
+	// Default: nil (all levels go to Output)
+	ErrorOutput io.Writer
+
 	Debug bool
 
+	// Encoding selects the output format: EncodingJSON, EncodingConsole, or
+	// EncodingLogfmt. Set via WithEncoder or WithConsoleEncoder.
+	// Default: EncodingJSON
+	Encoding string
+
+	// PrettyJSON indents JSON entries with two spaces, spreading each over
+	// several lines. Meant for local debugging only: line-based log
+	// ingestion expects one entry per line. Only applies to EncodingJSON.
+	// Set via WithPrettyJSON.
+	// Default: false
+	PrettyJSON bool
+
+	// FieldOrder lists top-level keys to move to the front of JSON entries,
+	// in that order; the other fields follow as usual. Only applies to
+	// EncodingJSON. Set via WithFieldOrder.
+	// Default: nil (zap's order)
+	FieldOrder []string
+
+	// Source adds the caller's file:line and function to Info and Debug entries.
+	// Warning, Error, and Fatal entries always include the source.
+	// Default: false
+	Source bool
+
+	// SeverityMapping overrides the severity field value per level.
+	// Levels not in the map keep the built-in values: DEBUG, INFO, WARNING,
+	// ERROR, CRITICAL (Fatal), and DEFAULT (Panic, DPanic).
+	// Default: nil
+	SeverityMapping map[zapcore.Level]string
+
+	// ErrorCauses adds a "causes" array to entries that log an error, holding
+	// the message of each error in the Unwrap chain down to the root cause.
+	// Default: false
+	ErrorCauses bool
+
+	// CompactErrors replaces the stack trace of Error and Fatal entries with
+	// "error_cause", the root cause's message, and "error_origin", the
+	// file:line where the error was created, or else where it was logged.
+	// Set via WithCompactErrors.
+	// Default: false (full stack trace)
+	CompactErrors bool
+
+	// SamplingInitial and SamplingThereafter enable sampling when
+	// SamplingThereafter > 0: each second, the first SamplingInitial entries
+	// with the same level and message are logged, then 1 in every
+	// SamplingThereafter. Set via WithSampling.
+	// Default: 0 (no sampling)
+	SamplingInitial    int
+	SamplingThereafter int
+
+	// ErrorDedupWindow, when positive, coalesces entries repeating the same
+	// error value within the window into the first entry plus a summary
+	// entry with a "repeated" count. Set via WithErrorDedup.
+	// Default: 0 (no deduplication)
+	ErrorDedupWindow time.Duration
+
+	// RequiredFields lists fields every entry must carry with a non-empty
+	// value; entries missing one are followed by a warning entry, or panic
+	// when ValidationPanic is set. Set via WithValidateFields.
+	// Default: nil (no validation)
+	RequiredFields  []string
+	ValidationPanic bool
+
+	// Clock supplies entry timestamps. Set via WithClock.
+	// Default: nil (the system clock)
+	Clock zapcore.Clock
+
+	// Location is the time zone entry timestamps are formatted in.
+	// Set via WithTimezone.
+	// Default: nil (the clock's, UTC unless the host is configured otherwise)
+	Location *time.Location
+
+	// FlattenSeparator, when set, flattens the data field into top-level
+	// keys joined by it, such as data.user.email. Set via WithFlattenKeys.
+	// Default: "" (nested objects)
+	FlattenSeparator string
+
+	// TraceExtractor finds the active trace in the context passed to the
+	// Ctx logging functions and the slog handler. Set via WithTraceExtractor.
+	// Default: nil
+	TraceExtractor TraceExtractor
+
+	// BaseContext is the context used by the logging functions that take
+	// none, and by the Ctx functions when given a nil one. Set via
+	// WithBaseContext.
+	// Default: nil
+	BaseContext context.Context
+
+	// CoreWrappers wrap the zap core, in order, before sampling and error
+	// deduplication are applied. Set via WithWrapCore.
+	// Default: nil
+	CoreWrappers []func(zapcore.Core) zapcore.Core
+
+	// Closers are called by Logger.Close, in order, after buffered entries
+	// are flushed, e.g. to close a file output. Set via WithCloser.
+	// Default: nil
+	Closers []func() error
+
+	// Async buffers writes in memory and flushes them from a background
+	// goroutine every AsyncFlushInterval or when AsyncBufferSize bytes are
+	// buffered. Set via WithAsync.
+	// Default: false
+	Async              bool
+	AsyncBufferSize    int
+	AsyncFlushInterval time.Duration
+
+	// Repanic makes RecoverAndLog panic again with the recovered value
+	// after logging it, instead of returning normally.
+	// Default: false
+	Repanic bool
+
+	// ExitFunc replaces os.Exit as the function Fatal calls with exit
+	// code 1 after writing its entry. Set via WithExitFunc.
+	// Default: nil (os.Exit)
+	ExitFunc func(code int)
+
+	// OnError and OnFatal are called with each Error and Fatal entry
+	// before it is written. Set via WithOnError and WithOnFatal.
+	// Default: nil
+	OnError func(ErrorEntry)
+	OnFatal func(ErrorEntry)
+
+	// CallerMaxDepth caps how many stack frames are searched for the first
+	// caller outside goslogx when reporting the source. Raise it if calls go
+	// through deeply nested wrappers.
+	// Default: 32
+	CallerMaxDepth int
+
+	// CallerSkip, when positive, is the fixed number of stack frames skipped
+	// to find the source, instead of searching for the first caller outside
+	// goslogx on every call. Set via WithCallerSkip.
+	// Default: 0 (detect the caller)
+	CallerSkip int
+
+	// StackTraceFormat selects how stack traces are written:
+	// StackTraceInline or StackTraceMultiline.
+	// Default: StackTraceInline
+	StackTraceFormat string
+
+	// StackTraceDepth caps stack traces at their top N frames, followed by
+	// a "... (M more)" line. Set via WithErrorStackDepth.
+	// Default: 0 (unlimited)
+	StackTraceDepth int
+
 	// Masking controls automatic field masking behavior.
 	Masking MaskingConfig
+
+	// FieldKeys holds the JSON keys used for the well-known log fields.
+	// Default: trace_id, module, msg_type, severity, data, error, etc.
+	FieldKeys FieldKeys
 }
 
 // MaskingConfig controls field masking behavior.
@@ -33,8 +210,162 @@ type MaskingConfig struct {
 	// When true, struct fields tagged with log:"masked:*" will be masked.
 	// Default: true
 	Enabled bool
+
+	// MaxDepth caps how deeply nested structs, slices, and JSON values are
+	// traversed while masking. Deeper values are replaced with "<max-depth>".
+	// Default: 32
+	MaxDepth int
+
+	// AutoMaskByName masks untagged struct string fields whose JSON name matches
+	// a sensitive pattern (password, token, email, ...), the same way JSON bodies
+	// and headers are masked. Explicit log:"masked:*" tags always win.
+	// Default: false
+	AutoMaskByName bool
+
+	// Profiles lists the built-in masking profiles, such as
+	// MaskingProfilePCI, whose sensitive field patterns are matched in
+	// addition to the default ones. Set via WithMaskingProfile.
+	// Default: nil
+	Profiles []string
+
+	// RecurseEncodedJSON masks JSON documents embedded in JSON string values,
+	// such as a "payload" field holding an escaped JSON object, and re-encodes
+	// them into the string. The embedded document counts toward MaxDepth.
+	// Default: false
+	RecurseEncodedJSON bool
+
+	// MaskSQLLiterals scrubs string literals compared with = in SQL
+	// statements, such as DBData.Statement, replacing them with '****'.
+	// Default: false
+	MaskSQLLiterals bool
+
+	// NormalizeSQL adds a normalized form of SQL statements, with literals
+	// replaced by ? placeholders, next to them, e.g. DBData.Statement is
+	// accompanied by statement_normalized. Useful to group and dedupe queries.
+	// Default: false
+	NormalizeSQL bool
+
+	// MaxPayloadBytes caps string and []byte values held in interface fields,
+	// such as HTTPData.Body and MQData.Payload, after masking. Longer values
+	// are cut and end with a "<truncated N bytes>" marker. Structured
+	// payloads, such as maps, are bounded by MaxDepth and MaxFields instead.
+	// Default: 0 (unlimited)
+	MaxPayloadBytes int
+
+	// MaxFields caps how many keys of each map, including JSON objects in
+	// bodies and payloads, are masked and logged. The rest are dropped and a
+	// "<truncated>": true marker is added, so a huge map can't blow up the
+	// entry size or the time spent masking it.
+	// Default: 0 (unlimited)
+	MaxFields int
+
+	// DurationFormat selects how time.Duration struct fields are logged:
+	// DurationFormatString, DurationFormatMillis, or DurationFormatNanos.
+	// Set via WithDurationFormat.
+	// Default: "" (DurationFormatString)
+	DurationFormat string
+
+	// AnonymizeIP zeroes the host part of IP addresses in fields tagged
+	// log:"masked:ip", such as HTTPData.ClientIP: the last octet of IPv4
+	// addresses and the last 80 bits of IPv6 addresses.
+	// Default: false
+	AnonymizeIP bool
+
+	// MaskInvalidIP replaces values of log:"masked:ip" fields that don't
+	// parse as an IP address with "****" when AnonymizeIP is set, instead of
+	// logging them unchanged.
+	// Default: false
+	MaskInvalidIP bool
+
+	// EmitMaskCount adds a masked_field_count field to entries logged with
+	// data, counting the values masked or redacted in their data, so masking
+	// coverage can be monitored without inspecting values.
+	// Default: false
+	EmitMaskCount bool
+
+	// UnmaskedLevels lists levels whose entries log their data argument
+	// unmasked, see WithUnmaskedLevels. Unmasked data may expose secrets and
+	// personal data, so never set it in production.
+	// Default: nil (data is masked at every level)
+	UnmaskedLevels []zapcore.Level
+
+	// RedactFields lists field names, matched case-insensitively, that are
+	// dropped from the output together with their values, unlike masking,
+	// which keeps the field and replaces its value. Applies to struct fields,
+	// map keys, JSON bodies, headers, and fields bound with With.
+	// Default: nil
+	RedactFields []string
+
+	// RedactHeaders lists HTTP header names, matched case-insensitively, that
+	// are dropped from HTTPData.Headers and MaskingLogHttpHeaders output
+	// rather than masked, such as Cookie.
+	// Default: nil
+	RedactHeaders []string
+
+	// FlattenHeaders logs each HTTPData header as a string instead of an
+	// array: single values as is, multiple values joined with ", ". Values
+	// are masked before they are joined.
+	// Default: false
+	FlattenHeaders bool
+
+	// SensitiveValues lists known secret values, such as the current API
+	// token, replaced with "****" wherever they appear in a log entry,
+	// whatever the field, including the message and errors.
+	// Default: nil
+	SensitiveValues []string
+
+	// ScanMessage masks sensitive values in the free-form message of each
+	// entry, such as emails, payment card numbers, and values following a
+	// sensitive key as in "password=hunter2". Off by default as it scans
+	// every message. SensitiveValues are masked in messages either way.
+	// Default: false
+	ScanMessage bool
+
+	// Mode selects which string values are masked by field name:
+	// MaskingDenylist masks fields matching the sensitive name patterns,
+	// MaskingAllowlist masks every string field not listed in AllowFields.
+	// Struct tags always take precedence. Set via WithAllowFields.
+	// Default: MaskingDenylist
+	Mode MaskingMode
+
+	// AllowFields lists field names, matched case-insensitively, whose
+	// values are shown as-is in MaskingAllowlist mode.
+	// Default: nil
+	AllowFields []string
+
+	// FieldMasker, when set, is consulted for every field before the
+	// built-in rules, see WithFieldMasker.
+	// Default: nil
+	FieldMasker FieldMasker
+
+	// DataConverters convert values of types the built-in rules don't know,
+	// such as protobuf messages, into values they can mask, see
+	// WithDataConverter.
+	// Default: nil
+	DataConverters []DataConverter
 }
 
+// DataConverter converts v into a value that masking understands, such as a
+// map[string]any, reporting whether it did. See WithDataConverter.
+type DataConverter func(v any) (converted any, ok bool)
+
+// FieldMasker masks the value of the field fieldName, reporting whether it
+// handled it. When handled is false, the built-in rules apply as usual.
+// See WithFieldMasker.
+type FieldMasker func(fieldName string, value any) (masked any, handled bool)
+
+// MaskingMode selects how MaskingConfig decides which fields to mask by name.
+type MaskingMode int
+
+const (
+	// MaskingDenylist masks fields whose names look sensitive, such as
+	// password or email, and shows everything else.
+	MaskingDenylist MaskingMode = iota
+	// MaskingAllowlist fully masks every string field except those listed in
+	// MaskingConfig.AllowFields, for services that must never leak by omission.
+	MaskingAllowlist
+)
+
 // Option configures a Logger.
 type Option func(*Config)
 
@@ -43,26 +374,136 @@ type Option func(*Config)
 //
 // Example:
 //
-//	logger, _ := goslogx.New(goslogx.WithServiceName("my-service"))
+//	logger := goslogx.New(goslogx.WithServiceName("my-service"))
 func WithServiceName(name string) Option {
 	return func(c *Config) {
 		c.ServiceName = name
 	}
 }
 
+// WithModule sets the module logged when a call passes an empty module,
+// for services that log everything under one name. A non-empty module
+// passed to a call still takes precedence.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithModule("billing"),
+//	)
+//	logger.Info(traceID, "", goslogx.MESSAGE_TYPE_EVENT, "invoice sent", nil)
+//	// {"module":"billing",...}
+func WithModule(module string) Option {
+	return func(c *Config) {
+		c.Module = module
+	}
+}
+
+// WithHostInfo adds the machine's hostname and the process ID to every
+// entry as "hostname" and "pid", to tell apart instances of a service
+// logging to the same place. Both are resolved once when the logger is
+// created; the hostname is omitted if it cannot be determined.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithHostInfo(true),
+//	)
+//	// {"application_name":"my-service","hostname":"web-1","pid":4242,...}
+func WithHostInfo(enabled bool) Option {
+	return func(c *Config) {
+		c.HostInfo = enabled
+	}
+}
+
+// WithSequence adds a "seq" field to every entry, counting up from 1 in the
+// order entries are written. It restores the order of entries whose
+// timestamps are equal at millisecond resolution, or that were reordered on
+// their way to the log store. Loggers derived with With share the counter;
+// entries dropped by sampling or deduplication don't use up a number.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithSequence(true),
+//	)
+//	// {"msg":"request received",...,"seq":1}
+//	// {"msg":"request served",...,"seq":2}
+func WithSequence(enabled bool) Option {
+	return func(c *Config) {
+		c.Sequence = enabled
+	}
+}
+
+// WithStrictMsgType validates the message type passed to Info and Debug.
+// Values other than the MESSAGE_TYPE_* constants, such as a typo'd
+// MsgType("REQEUST"), are logged as MESSAGE_TYPE_UNKNOWN so downstream
+// filters keep working, and a warning naming the value is logged the first
+// time it is seen.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithStrictMsgType(true),
+//	)
+func WithStrictMsgType(strict bool) Option {
+	return func(c *Config) {
+		c.StrictMsgType = strict
+	}
+}
+
 // WithOutput sets the output writer for logs.
-// By default, logs are written to os.Stdout.
+// By default, logs are written to os.Stdout. To discard all output, NewNop
+// is cheaper than WithOutput(io.Discard), which still encodes every entry.
 //
 // Example:
 //
 //	file, _ := os.OpenFile("app.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-//	logger, _ := goslogx.New(
+//	logger := goslogx.New(
 //	    goslogx.WithServiceName("my-service"),
 //	    goslogx.WithOutput(file),
 //	)
 func WithOutput(w io.Writer) Option {
 	return func(c *Config) {
 		c.Output = w
+		c.Outputs = nil
+	}
+}
+
+// WithOutputs fans logs out to several writers at once, e.g. stdout and a file.
+// Each writer gets its own stack trace formatting wrapper.
+//
+// WithOutput and WithOutputs do not merge: whichever option is applied last wins.
+//
+// Example:
+//
+//	file, _ := os.OpenFile("app.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithOutputs(os.Stdout, file),
+//	)
+func WithOutputs(ws ...io.Writer) Option {
+	return func(c *Config) {
+		c.Outputs = ws
+	}
+}
+
+// WithErrorOutput routes Warning, Error and Fatal entries to w,
+// leaving Info and Debug entries on the regular output.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithOutput(os.Stdout),
+//	    goslogx.WithErrorOutput(os.Stderr),
+//	)
+func WithErrorOutput(w io.Writer) Option {
+	return func(c *Config) {
+		c.ErrorOutput = w
 	}
 }
 
@@ -76,31 +517,1109 @@ func WithDebug(debug bool) Option {
 	}
 }
 
-// WithMasking enables automatic field masking.
-// When enabled, struct fields tagged with log:"masked:full" or log:"masked:partial"
-// will be automatically masked in log output.
+// WithConsoleEncoder switches output to a colorized, human-readable
+// key=value format intended for local development. Masking still applies.
+// Pass false to keep the default JSON output.
 //
 // Example:
 //
-//	logger, _ := goslogx.New(
+//	logger := goslogx.New(
 //	    goslogx.WithServiceName("my-service"),
-//	    goslogx.WithMasking(true),
+//	    goslogx.WithConsoleEncoder(true),
 //	)
-func WithMasking(masking bool) Option {
+func WithConsoleEncoder(console bool) Option {
+	encoding := EncodingJSON
+	if console {
+		encoding = EncodingConsole
+	}
 	return func(c *Config) {
-		c.Masking.Enabled = masking
+		c.Encoding = encoding
+	}
+}
+
+// WithEncoder selects the output format: EncodingJSON (the default),
+// EncodingConsole, or EncodingLogfmt for platforms and log routers that
+// expect key=value lines. Logfmt output flattens nested data into
+// dot-separated keys, such as data.user.email, and quotes values holding
+// spaces, '=', or quotes. Masking applies to every format. Unknown
+// encodings fall back to JSON.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithEncoder(goslogx.EncodingLogfmt),
+//	)
+//	// level=info time=2024-01-02T03:04:05Z msg="order placed" application_name=my-service trace_id=trace-001 ...
+func WithEncoder(encoding string) Option {
+	return func(c *Config) {
+		c.Encoding = encoding
+	}
+}
+
+// WithPrettyJSON indents JSON entries with two spaces, which is easier to
+// read while debugging locally. Keep it off in production: each entry spans
+// several lines, which breaks line-based log ingestion. Masking still
+// applies. It only applies to the JSON encoding.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithPrettyJSON(true),
+//	)
+//	// {
+//	//   "level": "info",
+//	//   "time": "2024-01-02T03:04:05Z",
+//	//   ...
+//	// }
+func WithPrettyJSON(pretty bool) Option {
+	return func(c *Config) {
+		c.PrettyJSON = pretty
+	}
+}
+
+// WithFieldOrder moves the listed top-level fields, such as severity,
+// trace_id, module, and msg, to the front of every JSON entry, in the given
+// order, for operators reading raw logs. Fields not listed follow in their
+// usual order, and listed fields an entry lacks are skipped. Keys are
+// matched as written, after WithFieldKeys remapping. Reordering re-parses
+// each entry, so it costs some throughput; it only applies to the JSON
+// encoding.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithFieldOrder([]string{goslogx.KeySeverity, goslogx.KeyTraceID, goslogx.KeyModule, goslogx.KeyMessage}),
+//	)
+//	// {"severity":"INFO","trace_id":"trace-001","module":"orders","msg":"order placed","level":"info",...}
+func WithFieldOrder(keys []string) Option {
+	return func(c *Config) {
+		c.FieldOrder = keys
+	}
+}
+
+// WithSource adds the caller's source location to Info and Debug entries,
+// which omit it by default to keep high-volume logs lean.
+// Warning, Error, and Fatal entries always include the source.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithSource(true),
+//	)
+func WithSource(source bool) Option {
+	return func(c *Config) {
+		c.Source = source
+	}
+}
+
+// WithSeverityMapping overrides the value of the severity field for the given
+// levels, e.g. to use syslog names or numeric levels. Unspecified levels keep
+// the built-in values. Calling it again adds to the previous overrides.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithSeverityMapping(map[zapcore.Level]string{
+//	        zapcore.WarnLevel:  "warning",
+//	        zapcore.ErrorLevel: "err",
+//	        zapcore.FatalLevel: "crit",
+//	    }),
+//	)
+func WithSeverityMapping(mapping map[zapcore.Level]string) Option {
+	return func(c *Config) {
+		if c.SeverityMapping == nil {
+			c.SeverityMapping = make(map[zapcore.Level]string, len(mapping))
+		}
+		for lvl, s := range mapping {
+			c.SeverityMapping[lvl] = s
+		}
+	}
+}
+
+// WithErrorCauses adds the messages of an error's Unwrap chain, from the
+// outermost error to the root cause, as a "causes" array to Error, WarningErr
+// and Fatal entries. Chains built with fmt.Errorf("...: %w", err) and
+// github.com/pkg/errors are both followed.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithErrorCauses(true),
+//	)
+//	// fmt.Errorf("handler: %w", fmt.Errorf("query: %w", sql.ErrNoRows)) is logged with
+//	// "causes":["handler: query: sql: no rows in result set","query: sql: no rows in result set","sql: no rows in result set"]
+func WithErrorCauses(causes bool) Option {
+	return func(c *Config) {
+		c.ErrorCauses = causes
+	}
+}
+
+// WithCompactErrors logs Error and Fatal entries with scalar fields instead
+// of the full stack trace, for log tools that can't parse the bracketed
+// stack_trace: "error_cause" holds the message of the root cause, and
+// "error_origin" the file:line of the top frame of the stack recorded by a
+// github.com/pkg/errors error, or of the caller if there is none.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithCompactErrors(true),
+//	)
+//	// pkgerrors.Wrap(err, "load user") is logged with
+//	// "error":"load user: sql: no rows in result set",
+//	// "error_cause":"sql: no rows in result set","error_origin":"repo/user.go:42"
+func WithCompactErrors(compact bool) Option {
+	return func(c *Config) {
+		c.CompactErrors = compact
+	}
+}
+
+// WithSampling caps the volume of repeated entries, e.g. from a hot loop.
+// Each second, the first initial entries with the same level and message are
+// logged, then only every thereafter-th one. Entries are grouped by message
+// only, not by fields: all Error entries share the message "error occurred".
+// A thereafter less than 1 disables sampling.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithSampling(100, 100),
+//	)
+func WithSampling(initial, thereafter int) Option {
+	return func(c *Config) {
+		c.SamplingInitial = initial
+		c.SamplingThereafter = thereafter
+	}
+}
+
+// WithErrorDedup coalesces Error and WarningErr entries whose error value
+// repeats within window. The first entry is written as usual; repeats are
+// counted instead of written, and once the window elapses or a different
+// error is logged, the entry is written again with a "repeated" field holding
+// the number of suppressed repeats. Call Sync before exiting to flush a
+// pending summary. A window less than or equal to 0 disables deduplication.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithErrorDedup(10*time.Second),
+//	)
+//	defer logger.Sync()
+func WithErrorDedup(window time.Duration) Option {
+	return func(c *Config) {
+		c.ErrorDedupWindow = window
+	}
+}
+
+// WithValidateFields checks, during development, that every entry carries
+// each of fields, such as trace_id and module, with a non-empty value.
+// Fields bound with With count. An entry missing one is still written,
+// followed by a WARNING entry listing "missing_fields"; with
+// WithValidationPanic the log call panics instead. Only enable it in
+// development: without it no check runs at all.
+//
+// Example:
+//
+//	opts := []goslogx.Option{goslogx.WithServiceName("my-service")}
+//	if os.Getenv("APP_ENV") == "development" {
+//	    opts = append(opts,
+//	        goslogx.WithValidateFields([]string{goslogx.KeyTraceID, goslogx.KeyModule}),
+//	        goslogx.WithValidationPanic(true),
+//	    )
+//	}
+//	logger := goslogx.New(opts...)
+//	logger.Info("", "users", goslogx.MESSAGE_TYPE_EVENT, "user created", nil) // panics
+func WithValidateFields(fields []string) Option {
+	return func(c *Config) {
+		c.RequiredFields = append(c.RequiredFields, fields...)
+	}
+}
+
+// WithValidationPanic makes entries missing a field required by
+// WithValidateFields panic instead of logging a warning.
+func WithValidationPanic(panics bool) Option {
+	return func(c *Config) {
+		c.ValidationPanic = panics
+	}
+}
+
+// WithClock sets the clock entry timestamps are taken from, e.g. a frozen
+// clock so tests can assert on exact output.
+//
+// Example:
+//
+//	type fixedClock struct{ t time.Time }
+//
+//	func (c fixedClock) Now() time.Time                         { return c.t }
+//	func (c fixedClock) NewTicker(d time.Duration) *time.Ticker { return time.NewTicker(d) }
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithClock(fixedClock{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}),
+//	)
+//	// every entry has "time":"2024-01-02T03:04:05Z"
+func WithClock(clock zapcore.Clock) Option {
+	return func(c *Config) {
+		c.Clock = clock
+	}
+}
+
+// WithFlattenKeys flattens the data field into top-level keys joined by
+// separator, for backends that prefer flat keys over nested objects. Array
+// elements are keyed by their index. Masking applies as usual; only the
+// shape of the output changes. An empty separator keeps nested objects.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithFlattenKeys("."),
+//	)
+//	// {"data":{"user":{"email":"ja****@example.com"},"roles":["admin"]}} is logged as
+//	// {"data.user.email":"ja****@example.com","data.roles.0":"admin"}
+func WithFlattenKeys(separator string) Option {
+	return func(c *Config) {
+		c.FlattenSeparator = separator
+	}
+}
+
+// WithTimezone formats entry timestamps in loc instead of the clock's time
+// zone, e.g. for operators who read logs in local time. The instant logged is
+// the same, only its offset changes. A nil loc keeps the clock's time zone.
+//
+// Example:
+//
+//	loc, err := time.LoadLocation("Asia/Jakarta")
+//	if err != nil {
+//	    // handle error
+//	}
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithTimezone(loc),
+//	)
+//	// "time":"2024-01-02T10:04:05+07:00" instead of "time":"2024-01-02T03:04:05Z"
+func WithTimezone(loc *time.Location) Option {
+	return func(c *Config) {
+		c.Location = loc
+	}
+}
+
+// WithTraceExtractor correlates entries with distributed traces. When extract
+// finds an active trace in the context given to InfoCtx, ErrorCtx, and the
+// other Ctx functions, its trace ID replaces the traceID argument and its span
+// ID is added as "span_id". The slog handler adds both to records logged with
+// a context. The otelx subpackage provides an OpenTelemetry extractor.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithTraceExtractor(func(ctx context.Context) (string, string, bool) {
+//	        span, ok := ctx.Value(spanKey{}).(*Span)
+//	        if !ok {
+//	            return "", "", false
+//	        }
+//	        return span.TraceID, span.ID, true
+//	    }),
+//	)
+//	logger.InfoCtx(ctx, traceID, "handler", goslogx.MESSAGE_TYPE_EVENT, "request received", nil)
+func WithTraceExtractor(extract TraceExtractor) Option {
+	return func(c *Config) {
+		c.TraceExtractor = extract
+	}
+}
+
+// WithBaseContext sets a default context for the logging functions that
+// don't take one, such as Info and Error, and for the Ctx functions given a
+// nil context. Its fields added with ContextWithFields are logged, and the
+// TraceExtractor is applied to it, as if every call were made with ctx. It
+// bridges code still written against context-free calls with context-based
+// correlation; a context passed to a Ctx function replaces it.
+//
+// Example:
+//
+//	base := goslogx.ContextWithFields(context.Background(), map[string]any{"region": "ap-southeast-1"})
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithBaseContext(base),
+//	)
+//	logger.Info("trace-001", "worker", goslogx.MESSAGE_TYPE_EVENT, "job done", nil)
+//	// {"msg":"job done","region":"ap-southeast-1",...}
+func WithBaseContext(ctx context.Context) Option {
+	return func(c *Config) {
+		c.BaseContext = ctx
+	}
+}
+
+// WithWrapCore wraps the zap core that encodes and writes entries, e.g. to
+// count or tee written entries. It is the extension point used by optional
+// integrations such as the promx subpackage. Wrappers are applied in the
+// order they were given, around WithValidateFields and inside
+// WithErrorDedup and WithSampling, so they only see the entries those let
+// through.
+//
+// With WithErrorOutput, the wrapped core is a tee of the two outputs, whose
+// Write goes to both regardless of level. A wrapper must therefore leave
+// routing to the wrapped core's Check, adding itself to the returned
+// CheckedEntry if it needs to see written entries, rather than call its Write.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithWrapCore(func(core zapcore.Core) zapcore.Core {
+//	        return zapcore.NewTee(core, auditCore)
+//	    }),
+//	)
+func WithWrapCore(wrap func(zapcore.Core) zapcore.Core) Option {
+	return func(c *Config) {
+		c.CoreWrappers = append(c.CoreWrappers, wrap)
+	}
+}
+
+// WithCloser registers close to be called by Logger.Close once buffered
+// entries are flushed, for outputs that hold resources, such as an open
+// file. It is the hook used by optional integrations such as the rotatex
+// subpackage. Closers run in the order they were given.
+//
+// Example:
+//
+//	f, _ := os.OpenFile("app.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithOutput(f),
+//	    goslogx.WithCloser(f.Close),
+//	)
+//	defer logger.Close()
+func WithCloser(close func() error) Option {
+	return func(c *Config) {
+		c.Closers = append(c.Closers, close)
+	}
+}
+
+// WithAsync buffers log output in memory, taking writes off the hot path.
+// Buffers are flushed every flushInterval, when bufferSize bytes are pending,
+// and on Sync; entries above Error are flushed immediately. A bufferSize or
+// flushInterval of 0 uses 256 kB or 30 seconds respectively.
+//
+// Buffered entries are lost if the process dies without calling Close, so
+// defer Close in main.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithAsync(64*1024, time.Second),
+//	)
+//	defer logger.Close()
+func WithAsync(bufferSize int, flushInterval time.Duration) Option {
+	return func(c *Config) {
+		c.Async = true
+		c.AsyncBufferSize = bufferSize
+		c.AsyncFlushInterval = flushInterval
+	}
+}
+
+// WithRepanic makes the function returned by RecoverAndLog panic again with
+// the recovered value once it is logged, e.g. to let a supervisor or the
+// HTTP server's own recovery handle it. By default the panic is swallowed
+// and the deferring function returns normally.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithRepanic(true),
+//	)
+func WithRepanic(repanic bool) Option {
+	return func(c *Config) {
+		c.Repanic = repanic
+	}
+}
+
+// WithExitFunc makes Fatal call exit with code 1, instead of os.Exit, once its
+// entry is written, so tests can check that it was called without ending the
+// test process. If exit returns, so does Fatal, and the caller carries on.
+//
+// Example:
+//
+//	var code int
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithExitFunc(func(c int) { code = c }),
+//	)
+//	logger.Fatal("trace-001", "main", err) // code == 1
+func WithExitFunc(exit func(code int)) Option {
+	return func(c *Config) {
+		c.ExitFunc = exit
+	}
+}
+
+// WithOnError calls hook with every entry logged by Error and ErrorCtx,
+// synchronously, just before it is written, e.g. to send an alert. It is not
+// called for entries below the configured level. A panic in hook is
+// recovered and logged as a warning, and the entry is written regardless.
+// Keep hook fast, or hand the entry off to another goroutine.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithOnError(func(e goslogx.ErrorEntry) {
+//	        alerts <- fmt.Sprintf("[%s] %s: %v", e.TraceID, e.Module, e.Err)
+//	    }),
+//	)
+func WithOnError(hook func(ErrorEntry)) Option {
+	return func(c *Config) {
+		c.OnError = hook
+	}
+}
+
+// WithOnFatal calls hook with the entry logged by Fatal and FatalCtx,
+// synchronously, just before it is written and the process exits, e.g. to
+// flush a crash reporter. A panic in hook is recovered and logged as a
+// warning, so it doesn't prevent the exit.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithOnFatal(func(e goslogx.ErrorEntry) {
+//	        sentry.CaptureException(e.Err)
+//	        sentry.Flush(2 * time.Second)
+//	    }),
+//	)
+func WithOnFatal(hook func(ErrorEntry)) Option {
+	return func(c *Config) {
+		c.OnFatal = hook
+	}
+}
+
+// WithCallerMaxDepth sets how many stack frames are searched for the first
+// caller outside goslogx when reporting the source location.
+// Values less than 1 keep the default of 32.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithCallerMaxDepth(64),
+//	)
+func WithCallerMaxDepth(depth int) Option {
+	return func(c *Config) {
+		if depth > 0 {
+			c.CallerMaxDepth = depth
+		}
+	}
+}
+
+// WithCallerSkip reports the source a fixed n frames above the goslogx
+// method that writes the entry, skipping the stack walk that otherwise finds
+// the first caller outside goslogx on every call. Use it when every log call
+// goes through the same, known number of wrappers:
+//
+//   - 1 reports the caller of a Logger method, such as logger.Error
+//   - 2 reports the caller of a package-level function, such as goslogx.Error,
+//     or of a Logger Ctx method, such as logger.ErrorCtx
+//   - 3 reports the caller of a package-level Ctx function, such as goslogx.ErrorCtx
+//
+// Add 1 for each wrapper function of your own. A wrong n reports the wrong
+// source, so prefer the default detection unless profiles show its cost.
+// RecoverAndLog always detects the function that panicked.
+//
+// Example:
+//
+//	// All logging goes through this helper, which calls goslogx.Error
+//	func logError(traceID string, err error) {
+//	    goslogx.Error(traceID, "app", err)
+//	}
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithCallerSkip(3),
+//	)
+func WithCallerSkip(n int) Option {
+	return func(c *Config) {
+		c.CallerSkip = n
+	}
+}
+
+// WithErrorStackDepth keeps only the top n frames of stack traces, those
+// nearest to where the error was created or logged, replacing the rest with
+// a "... (M more)" line, so deep call chains don't bloat log lines. It
+// applies to both stack trace formats. An n of 0 or less keeps every frame.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithErrorStackDepth(10),
+//	)
+//	// "stack_trace":"[main.load | /app/main.go:42 | ... | ... (23 more)]"
+func WithErrorStackDepth(n int) Option {
+	return func(c *Config) {
+		c.StackTraceDepth = n
+	}
+}
+
+// WithStackTraceFormat selects how stack traces are written.
+// StackTraceInline (default) collapses a stack into a single bracketed,
+// pipe-separated line; StackTraceMultiline keeps the original
+// newline-separated frames for viewers that render them.
+// Unknown formats are ignored.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithStackTraceFormat(goslogx.StackTraceMultiline),
+//	)
+func WithStackTraceFormat(format string) Option {
+	return func(c *Config) {
+		switch format {
+		case StackTraceInline, StackTraceMultiline:
+			c.StackTraceFormat = format
+		}
+	}
+}
+
+// WithDurationFormat selects how time.Duration and *time.Duration struct
+// fields in data are logged. DurationFormatString (default) writes them like
+// FormatDuration, e.g. "125ms"; DurationFormatMillis as a number of
+// milliseconds like DurationMillis, e.g. 125, for aggregation; and
+// DurationFormatNanos as the raw integer nanoseconds. Unknown formats are
+// ignored.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithDurationFormat(goslogx.DurationFormatMillis),
+//	)
+//	// struct{ Latency time.Duration `json:"latency"` }{125 * time.Millisecond}
+//	// is logged as {"latency":125} instead of {"latency":"125ms"}
+func WithDurationFormat(format string) Option {
+	return func(c *Config) {
+		switch format {
+		case DurationFormatString, DurationFormatMillis, DurationFormatNanos:
+			c.Masking.DurationFormat = format
+		}
+	}
+}
+
+// WithFieldKeys remaps the keys of well-known log fields, identified by their
+// default names (e.g. "trace_id", "severity", "level", "time", "msg").
+// Unspecified and unknown keys keep their defaults.
+//
+// A remapping that is empty or collides with another key is ignored and
+// reported with a warning entry, so no field is silently dropped.
+// To reuse a key that is already taken, remap its owner as well.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithFieldKeys(map[string]string{
+//	        "trace_id": "traceId",
+//	        "level":    "zap_level",
+//	        "severity": "level",
+//	    }),
+//	)
+func WithFieldKeys(keys map[string]string) Option {
+	return func(c *Config) {
+		for name, key := range keys {
+			c.FieldKeys.set(name, key)
+		}
+	}
+}
+
+// WithMasking enables automatic field masking.
+// When enabled, struct fields tagged with log:"masked:full" or log:"masked:partial"
+// will be automatically masked in log output.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithMasking(true),
+//	)
+func WithMasking(masking bool) Option {
+	return func(c *Config) {
+		c.Masking.Enabled = masking
+	}
+}
+
+// WithMaskingMaxDepth sets how deeply nested values are traversed while masking.
+// Values nested deeper than depth are logged as "<max-depth>", which protects
+// against stack exhaustion on hostile or pathological input.
+// Values less than 1 keep the default of 32.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithMaskingMaxDepth(8),
+//	)
+func WithMaskingMaxDepth(depth int) Option {
+	return func(c *Config) {
+		if depth > 0 {
+			c.Masking.MaxDepth = depth
+		}
+	}
+}
+
+// WithAutoMaskByName enables name-based masking for struct fields without a
+// log:"masked:*" tag, so an untagged Password field is masked just like a
+// "password" key in a JSON body. Tag a field with log:"masked:none" to exempt it.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithAutoMaskByName(true),
+//	)
+func WithAutoMaskByName(auto bool) Option {
+	return func(c *Config) {
+		c.Masking.AutoMaskByName = auto
+	}
+}
+
+// WithMaskingProfile applies a built-in masking profile for a compliance
+// regime: MaskingProfilePCI, MaskingProfileHIPAA, MaskingProfileGDPR, or
+// MaskingProfileDefault. Its sensitive field patterns, such as cvv for PCI
+// or medical_record for HIPAA, are masked in addition to the default ones,
+// and it turns on the value scanning it needs, such as WithMessageScanning,
+// which later options can turn off again. Repeated calls combine profiles.
+// Unknown profiles are ignored.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithMaskingProfile(goslogx.MaskingProfilePCI),
+//	)
+//	// {"card_number":"4111111111111111","cvv":"123"} is logged as
+//	// {"card_number":"****","cvv":"****"}
+func WithMaskingProfile(profile string) Option {
+	return func(c *Config) {
+		p, ok := maskingProfiles[profile]
+		if !ok {
+			return
+		}
+		c.Masking.Profiles = append(c.Masking.Profiles, profile)
+		p.apply(&c.Masking)
+	}
+}
+
+// WithRecurseEncodedJSON enables masking of JSON documents that are embedded,
+// already encoded, inside string values of JSON bodies.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithRecurseEncodedJSON(true),
+//	)
+//	// {"payload":"{\"password\":\"secret\"}"} is logged as
+//	// {"payload":"{\"password\":\"****\"}"}
+func WithRecurseEncodedJSON(recurse bool) Option {
+	return func(c *Config) {
+		c.Masking.RecurseEncodedJSON = recurse
+	}
+}
+
+// WithSQLLiteralMasking enables scrubbing of string literals compared with =
+// in SQL statements logged via DBData. Literals are recognized in the dialect
+// of DBData.Driver, such as Postgres dollar-quoted strings or MySQL
+// double-quoted and backslash-escaped strings.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithSQLLiteralMasking(true),
+//	)
+//	// SELECT * FROM users WHERE password = 'hunter2' is logged as
+//	// SELECT * FROM users WHERE password = '****'
+func WithSQLLiteralMasking(mask bool) Option {
+	return func(c *Config) {
+		c.Masking.MaskSQLLiterals = mask
+	}
+}
+
+// WithSQLNormalization adds a statement_normalized field to DBData entries,
+// holding the statement with its literals replaced by ? placeholders.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithSQLNormalization(true),
+//	)
+//	// SELECT * FROM users WHERE id IN (1, 2, 3) is accompanied by
+//	// "statement_normalized":"SELECT * FROM users WHERE id IN (?)"
+func WithSQLNormalization(normalize bool) Option {
+	return func(c *Config) {
+		c.Masking.NormalizeSQL = normalize
+	}
+}
+
+// WithMaxPayloadBytes caps logged bodies and payloads, such as HTTPData.Body
+// and MQData.Payload, at n bytes. Values are masked before they are cut, so
+// a sensitive value straddling the limit is never partially revealed.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithMaxPayloadBytes(4096),
+//	)
+//	// A 10 KiB message payload is logged as its first 4096 bytes followed by
+//	// "<truncated 6144 bytes>"
+func WithMaxPayloadBytes(n int) Option {
+	return func(c *Config) {
+		c.Masking.MaxPayloadBytes = n
+	}
+}
+
+// WithMaxFields caps how many keys of each map are logged, including JSON
+// objects in bodies and payloads. Maps are kept to their first n keys, in
+// sorted order for Go maps and document order for JSON, followed by a
+// "<truncated>": true marker. Non-positive n means unlimited.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithMaxFields(100),
+//	)
+//	// A map with 10,000 keys is logged as its first 100 keys followed by
+//	// "<truncated>":true
+func WithMaxFields(n int) Option {
+	return func(c *Config) {
+		c.Masking.MaxFields = n
+	}
+}
+
+// WithAnonymizeIP anonymizes client IP addresses, such as HTTPData.ClientIP
+// and struct fields of type net.IP, by zeroing the last octet of IPv4 addresses and the last 80 bits of IPv6
+// addresses, as commonly required for GDPR compliance. Values that aren't IP
+// addresses are logged unchanged unless WithMaskInvalidIP is set.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithAnonymizeIP(true),
+//	)
+//	// "client_ip":"203.0.113.42" is logged as "client_ip":"203.0.113.0"
+//	// "client_ip":"2001:db8:85a3::8a2e:370:7334" as "client_ip":"2001:db8:85a3::"
+func WithAnonymizeIP(anonymize bool) Option {
+	return func(c *Config) {
+		c.Masking.AnonymizeIP = anonymize
+	}
+}
+
+// WithMaskInvalidIP fully masks client IP values that don't parse as an IP
+// address when WithAnonymizeIP is set, so malformed or spoofed values, such
+// as a forged X-Forwarded-For header, are never logged as-is.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithAnonymizeIP(true),
+//	    goslogx.WithMaskInvalidIP(true),
+//	)
+//	// "client_ip":"not-an-ip" is logged as "client_ip":"****"
+func WithMaskInvalidIP(mask bool) Option {
+	return func(c *Config) {
+		c.Masking.MaskInvalidIP = mask
+	}
+}
+
+// WithMaskCount adds a masked_field_count field to entries with data, giving
+// the number of values masked or redacted in their data. Values with nothing
+// sensitive to mask, such as a non-matching scanned string, are not counted.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithMaskCount(true),
+//	)
+//	// {"data":{"email":"jo****@example.com","password":"****"},"masked_field_count":2}
+func WithMaskCount(emit bool) Option {
+	return func(c *Config) {
+		c.Masking.EmitMaskCount = emit
+	}
+}
+
+// WithUnmaskedLevels logs the data argument of entries at the given levels,
+// such as Debug, without masking, e.g. to see full payloads in local debug
+// builds while other levels stay masked. Other values, such as fields bound
+// with With, are still masked.
+//
+// Security: unmasked data may hold passwords, tokens, and personal data that
+// then end up wherever logs are shipped. Only use it in development.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithDebug(true),
+//	    goslogx.WithUnmaskedLevels(zapcore.DebugLevel),
+//	)
+func WithUnmaskedLevels(levels ...zapcore.Level) Option {
+	return func(c *Config) {
+		c.Masking.UnmaskedLevels = append(c.Masking.UnmaskedLevels, levels...)
+	}
+}
+
+// WithRedactFields drops fields with the given names from log entries
+// entirely, for data that must be absent from logs rather than masked.
+// Names are matched case-insensitively against the whole field name.
+// Repeated calls add to the list.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithRedactFields([]string{"ssn", "date_of_birth"}),
+//	)
+//	// {"name":"Jane","ssn":"123-45-6789"} is logged as {"name":"Jane"}
+func WithRedactFields(names []string) Option {
+	return func(c *Config) {
+		c.Masking.RedactFields = append(c.Masking.RedactFields, names...)
+	}
+}
+
+// WithRedactHeaders drops the given HTTP headers from HTTPData.Headers and
+// MaskingLogHttpHeaders output entirely, instead of showing their values as
+// "****". Names are matched case-insensitively, and repeated calls add to
+// the list. Other headers are masked by name as usual.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithRedactHeaders([]string{"Cookie", "Set-Cookie"}),
+//	)
+//	// {"Authorization":["****"],"Cookie":["session=abc"]} is logged as
+//	// {"Authorization":["****"]}
+func WithRedactHeaders(names []string) Option {
+	return func(c *Config) {
+		c.Masking.RedactHeaders = append(c.Masking.RedactHeaders, names...)
+	}
+}
+
+// WithHeaderFlatten logs HTTPData headers as strings rather than arrays of
+// strings, for ingestors that dislike array values: single-value headers are
+// logged as is, and multi-value headers joined with ", ". Each value is
+// masked before joining.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithHeaderFlatten(true),
+//	)
+//	// {"Accept":["text/html","application/json"],"Content-Type":["application/json"]}
+//	// is logged as {"Accept":"text/html, application/json","Content-Type":"application/json"}
+func WithHeaderFlatten(flatten bool) Option {
+	return func(c *Config) {
+		c.Masking.FlattenHeaders = flatten
+	}
+}
+
+// WithSensitiveValues masks the given secret values wherever they appear in
+// log entries, in any field or in the message, including as part of a longer
+// string. Use it for secrets known at startup, such as API tokens, that may
+// leak through fields with unremarkable names. Empty values are ignored, and
+// repeated calls add to the list.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithSensitiveValues([]string{os.Getenv("API_TOKEN")}),
+//	)
+//	// "url":"https://api.example.com/?token=s3cr3t" is logged as
+//	// "url":"https://api.example.com/?token=****"
+func WithSensitiveValues(values []string) Option {
+	return func(c *Config) {
+		c.Masking.SensitiveValues = append(c.Masking.SensitiveValues, values...)
+	}
+}
+
+// WithMessageScanning masks sensitive values in log messages, which are
+// otherwise logged verbatim: words that look like an email, payment card, or
+// phone number, and values following a sensitive key, as in
+// "password=hunter2" or "token: abc123". It is off by default as it scans
+// every message.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithMessageScanning(true),
+//	)
+//	// "failed to auth jane@example.com with password=hunter2" is logged as
+//	// "failed to auth ja****@example.com with password=****"
+func WithMessageScanning(scan bool) Option {
+	return func(c *Config) {
+		c.Masking.ScanMessage = scan
+	}
+}
+
+// WithAllowFields switches masking to MaskingAllowlist mode: every string
+// value in logged structs, maps, JSON bodies, and headers is replaced with
+// "****" unless its field name is in names. Names are matched
+// case-insensitively against the whole field name, and repeated calls add
+// to the list. Explicit log:"masked:*" struct tags still apply.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithAllowFields([]string{"id", "status"}),
+//	)
+//	// {"id":"usr-1","status":"active","name":"Jane"} is logged as
+//	// {"id":"usr-1","status":"active","name":"****"}
+func WithAllowFields(names []string) Option {
+	return func(c *Config) {
+		c.Masking.Mode = MaskingAllowlist
+		c.Masking.AllowFields = append(c.Masking.AllowFields, names...)
+	}
+}
+
+// WithFieldMasker adds custom masking for fields the built-in rules don't
+// cover, e.g. showing only the bank name of an account string. masker is
+// called for every struct field, map key, and JSON member not removed by
+// WithRedactFields, before struct tags and name-based rules; JSON objects
+// and arrays are passed as map[string]any and []any, and numbers as
+// json.Number. When it returns handled=true, masked is logged in place of
+// the value and nested values are not masked further; otherwise the
+// built-in rules apply. It must be safe for concurrent use.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithFieldMasker(func(name string, value any) (any, bool) {
+//	        account, ok := value.(string)
+//	        if !ok || name != "bank_account" {
+//	            return nil, false
+//	        }
+//	        bank, _, _ := strings.Cut(account, ":")
+//	        return bank + ":****", true
+//	    }),
+//	)
+//	// {"bank_account":"ACME:1234567890"} is logged as {"bank_account":"ACME:****"}
+func WithFieldMasker(masker FieldMasker) Option {
+	return func(c *Config) {
+		c.Masking.FieldMasker = masker
+	}
+}
+
+// WithDataConverter converts data values, and values held in interface
+// fields such as HTTPData.Body and MQData.Payload, before they are masked.
+// convert is called with each such value and returns ok=false for values it
+// doesn't handle; the first converter that handles a value wins, and its
+// result is masked like any other value. It is the extension point used by
+// optional integrations such as the protox subpackage, and must be safe
+// for concurrent use.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    goslogx.WithDataConverter(func(v any) (any, bool) {
+//	        row, ok := v.(*sql.Row)
+//	        if !ok {
+//	            return nil, false
+//	        }
+//	        return rowToMap(row), true
+//	    }),
+//	)
+func WithDataConverter(convert DataConverter) Option {
+	return func(c *Config) {
+		c.Masking.DataConverters = append(c.Masking.DataConverters, convert)
+	}
+}
+
+// WithConfig replaces the whole configuration with cfg, so output, level,
+// masking, field keys, and so on can be supplied in one struct, e.g. loaded
+// from a config file. Options after it still apply on top. Empty
+// ServiceName, Output, Encoding, StackTraceFormat, CallerMaxDepth,
+// Masking.MaxDepth, and FieldKeys entries take their defaults; every other
+// field, booleans such as Masking.Enabled included, is used as given.
+//
+// Example:
+//
+//	logger := goslogx.New(goslogx.WithConfig(goslogx.Config{
+//	    ServiceName: "my-service",
+//	    Level:       zapcore.DebugLevel,
+//	    Output:      os.Stderr,
+//	    Masking: goslogx.MaskingConfig{
+//	        Enabled:      true,
+//	        RedactFields: []string{"ssn"},
+//	    },
+//	    FieldKeys: goslogx.FieldKeys{TraceID: "trace"},
+//	}))
+func WithConfig(cfg Config) Option {
+	return func(c *Config) {
+		defaults := defaultConfig()
+		*c = cfg
+		if c.ServiceName == "" {
+			c.ServiceName = defaults.ServiceName
+		}
+		if c.Output == nil {
+			c.Output = defaults.Output
+		}
+		if c.Encoding == "" {
+			c.Encoding = defaults.Encoding
+		}
+		if c.StackTraceFormat == "" {
+			c.StackTraceFormat = defaults.StackTraceFormat
+		}
+		if c.CallerMaxDepth <= 0 {
+			c.CallerMaxDepth = defaults.CallerMaxDepth
+		}
+		if c.Masking.MaxDepth <= 0 {
+			c.Masking.MaxDepth = defaults.Masking.MaxDepth
+		}
+		for _, e := range c.FieldKeys.entries() {
+			if *e.ptr == "" {
+				*e.ptr = e.name
+			}
+		}
 	}
 }
 
 // defaultConfig returns the default logger configuration.
 func defaultConfig() *Config {
 	return &Config{
-		ServiceName: "unknown",
-		Level:       zapcore.InfoLevel,
-		Output:      os.Stdout,
-		Debug:       true,
+		ServiceName:      "unknown",
+		Level:            zapcore.InfoLevel,
+		Output:           os.Stdout,
+		Debug:            true,
+		Encoding:         EncodingJSON,
+		StackTraceFormat: StackTraceInline,
+		CallerMaxDepth:   defaultCallerMaxDepth,
+		FieldKeys:        defaultFieldKeys(),
 		Masking: MaskingConfig{
-			Enabled: true,
+			Enabled:  true,
+			MaxDepth: defaultMaxDepth,
 		},
 	}
 }
@@ -2,7 +2,10 @@ package goslogx
 
 import (
 	"bytes"
+	"errors"
 	"os"
+	"reflect"
+	"strconv"
 	"testing"
 
 	"go.uber.org/zap/zapcore"
@@ -26,6 +29,35 @@ func TestOptions(t *testing.T) {
 		}
 	})
 
+	t.Run("WithOutputs", func(t *testing.T) {
+		t.Run("Set", func(t *testing.T) {
+			cfg := defaultConfig()
+			a, b := &bytes.Buffer{}, &bytes.Buffer{}
+			WithOutputs(a, b)(cfg)
+			if len(cfg.Outputs) != 2 || cfg.Outputs[0] != a || cfg.Outputs[1] != b {
+				t.Errorf("Expected Outputs to hold both buffers, got %v", cfg.Outputs)
+			}
+		})
+		t.Run("LaterWithOutputWins", func(t *testing.T) {
+			cfg := defaultConfig()
+			buf := &bytes.Buffer{}
+			WithOutputs(&bytes.Buffer{}, &bytes.Buffer{})(cfg)
+			WithOutput(buf)(cfg)
+			if len(cfg.Outputs) != 0 || cfg.Output != buf {
+				t.Errorf("Expected WithOutput to replace Outputs, got %v / %v", cfg.Outputs, cfg.Output)
+			}
+		})
+	})
+
+	t.Run("WithErrorOutput", func(t *testing.T) {
+		cfg := defaultConfig()
+		buf := &bytes.Buffer{}
+		WithErrorOutput(buf)(cfg)
+		if cfg.ErrorOutput != buf {
+			t.Errorf("Expected ErrorOutput to be the buffer, got %v", cfg.ErrorOutput)
+		}
+	})
+
 	t.Run("WithDebug", func(t *testing.T) {
 		t.Run("True", func(t *testing.T) {
 			cfg := defaultConfig()
@@ -43,6 +75,26 @@ func TestOptions(t *testing.T) {
 		})
 	})
 
+	t.Run("WithConsoleEncoder", func(t *testing.T) {
+		cfg := defaultConfig()
+		WithConsoleEncoder(true)(cfg)
+		if cfg.Encoding != EncodingConsole {
+			t.Errorf("Expected console encoding, got %s", cfg.Encoding)
+		}
+		WithConsoleEncoder(false)(cfg)
+		if cfg.Encoding != EncodingJSON {
+			t.Errorf("Expected json encoding, got %s", cfg.Encoding)
+		}
+	})
+
+	t.Run("WithEncoder", func(t *testing.T) {
+		cfg := defaultConfig()
+		WithEncoder(EncodingLogfmt)(cfg)
+		if cfg.Encoding != EncodingLogfmt {
+			t.Errorf("Expected logfmt encoding, got %s", cfg.Encoding)
+		}
+	})
+
 	t.Run("WithMasking", func(t *testing.T) {
 		t.Run("True", func(t *testing.T) {
 			cfg := defaultConfig()
@@ -61,6 +113,40 @@ func TestOptions(t *testing.T) {
 	})
 }
 
+func TestWithMaskingMaxDepth(t *testing.T) {
+	cfg := defaultConfig()
+	WithMaskingMaxDepth(5)(cfg)
+	if cfg.Masking.MaxDepth != 5 {
+		t.Errorf("Expected MaxDepth 5, got %d", cfg.Masking.MaxDepth)
+	}
+	WithMaskingMaxDepth(0)(cfg)
+	if cfg.Masking.MaxDepth != 5 {
+		t.Errorf("Expected non-positive depth to be ignored, got %d", cfg.Masking.MaxDepth)
+	}
+}
+
+func TestWithAutoMaskByName(t *testing.T) {
+	cfg := defaultConfig()
+	if cfg.Masking.AutoMaskByName {
+		t.Error("Expected AutoMaskByName to be off by default")
+	}
+	WithAutoMaskByName(true)(cfg)
+	if !cfg.Masking.AutoMaskByName {
+		t.Error("Expected AutoMaskByName to be true")
+	}
+}
+
+func TestWithRecurseEncodedJSON(t *testing.T) {
+	cfg := defaultConfig()
+	if cfg.Masking.RecurseEncodedJSON {
+		t.Error("Expected RecurseEncodedJSON to be off by default")
+	}
+	WithRecurseEncodedJSON(true)(cfg)
+	if !cfg.Masking.RecurseEncodedJSON {
+		t.Error("Expected RecurseEncodedJSON to be true")
+	}
+}
+
 func TestDefaultConfig(t *testing.T) {
 	cfg := defaultConfig()
 	if cfg.ServiceName != "unknown" {
@@ -76,3 +162,103 @@ func TestDefaultConfig(t *testing.T) {
 		t.Error("Expected default Masking.Enabled to be true")
 	}
 }
+
+func TestWithConfig(t *testing.T) {
+	t.Run("FullyPopulated", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithConfig(Config{
+			ServiceName:     "config-service",
+			Module:          "default-module",
+			Level:           zapcore.DebugLevel,
+			Output:          buf,
+			Debug:           true,
+			Encoding:        EncodingJSON,
+			Source:          true,
+			SeverityMapping: map[zapcore.Level]string{zapcore.DebugLevel: "TRACE"},
+			FieldKeys:       FieldKeys{TraceID: "trace", Module: "component"},
+			Masking: MaskingConfig{
+				Enabled:      true,
+				RedactFields: []string{"ssn"},
+			},
+		}))
+
+		logger.Debug("trace-001", "", MESSAGE_TYPE_EVENT, "debug enabled", nil)
+		entry := decodeEntry(t, buf.Bytes())
+		if entry["application_name"] != "config-service" || entry["severity"] != "TRACE" {
+			t.Errorf("Expected service name and severity from Config, got %v", entry)
+		}
+		if entry["trace"] != "trace-001" || entry["component"] != "default-module" {
+			t.Errorf("Expected remapped trace and module keys, got %v", entry)
+		}
+		if entry["source"] == nil {
+			t.Errorf("Expected source on a Debug entry, got %v", entry)
+		}
+
+		buf.Reset()
+		logger.Info("trace-001", "", MESSAGE_TYPE_EVENT, "configured", map[string]any{
+			"password": "secret",
+			"ssn":      "123-45-6789",
+			"status":   "ok",
+		})
+		entry = decodeEntry(t, buf.Bytes())
+		data := entry["data"].(map[string]any)
+		if data["password"] != "****" || data["status"] != "ok" {
+			t.Errorf("Expected masked data, got %v", data)
+		}
+		if _, ok := data["ssn"]; ok {
+			t.Errorf("Expected ssn to be redacted, got %v", data)
+		}
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		cfg := defaultConfig()
+		WithConfig(Config{Masking: MaskingConfig{Enabled: true}})(cfg)
+		defaults := defaultConfig()
+		if cfg.ServiceName != defaults.ServiceName || cfg.Output != defaults.Output ||
+			cfg.Encoding != defaults.Encoding || cfg.StackTraceFormat != defaults.StackTraceFormat ||
+			cfg.CallerMaxDepth != defaults.CallerMaxDepth || cfg.Masking.MaxDepth != defaults.Masking.MaxDepth {
+			t.Errorf("Expected empty fields to take their defaults, got %+v", cfg)
+		}
+		if cfg.FieldKeys != defaults.FieldKeys {
+			t.Errorf("Expected default field keys, got %+v", cfg.FieldKeys)
+		}
+		if cfg.Debug {
+			t.Error("Expected booleans to be used as given")
+		}
+	})
+
+	t.Run("LaterOptionsApply", func(t *testing.T) {
+		cfg := defaultConfig()
+		WithConfig(Config{ServiceName: "from-config"})(cfg)
+		WithServiceName("from-option")(cfg)
+		if cfg.ServiceName != "from-option" {
+			t.Errorf("Expected later options to override Config, got %s", cfg.ServiceName)
+		}
+	})
+}
+
+func TestWithCloser(t *testing.T) {
+	var calls []string
+	buf := &bytes.Buffer{}
+	logger := setupLog(
+		WithOutput(buf),
+		WithAsync(0, 0),
+		WithCloser(func() error {
+			// Buffered entries must already be flushed
+			calls = append(calls, "first:"+strconv.Itoa(bytes.Count(buf.Bytes(), []byte("\n"))))
+			return nil
+		}),
+		WithCloser(func() error {
+			calls = append(calls, "second")
+			return errors.New("close failed")
+		}),
+	)
+	logger.Info("trace-1", "mod", MESSAGE_TYPE_EVENT, "buffered", nil)
+
+	if err := logger.Close(); err == nil || err.Error() != "close failed" {
+		t.Errorf("Expected the closer's error, got %v", err)
+	}
+	if want := []string{"first:1", "second"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected closers to run in order after flushing, got %v", calls)
+	}
+}
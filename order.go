@@ -0,0 +1,99 @@
+package goslogx
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// orderedEncoder moves the top-level fields listed in order to the front of
+// each JSON entry encoded by the wrapped encoder, see WithFieldOrder.
+type orderedEncoder struct {
+	zapcore.Encoder
+	order []string
+}
+
+// Clone implements zapcore.Encoder.
+func (e *orderedEncoder) Clone() zapcore.Encoder {
+	return &orderedEncoder{Encoder: e.Encoder.Clone(), order: e.order}
+}
+
+// EncodeEntry implements zapcore.Encoder. Entries that can't be parsed are
+// left as encoded.
+func (e *orderedEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	if reordered, ok := reorderJSON(buf.Bytes(), e.order); ok {
+		buf.Reset()
+		buf.Write(reordered)
+	}
+	return buf, nil
+}
+
+// reorderJSON returns the JSON object in line, followed by the rest of
+// line such as its newline, with the members whose key is in order first, in
+// that order, and the others after them as they were. Members are copied
+// byte for byte, so values are not re-encoded.
+//
+// Example: reorderJSON(`{"a":1,"b":2,"c":3}`, []string{"c", "a"}) -> `{"c":3,"a":1,"b":2}`
+func reorderJSON(line []byte, order []string) ([]byte, bool) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, false
+	}
+	type member struct {
+		key  string
+		data []byte // `"key":value`
+	}
+	var members []member
+	prev := dec.InputOffset()
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, false
+		}
+		end := dec.InputOffset()
+		members = append(members, member{key: key, data: bytes.TrimLeft(line[prev:end], ", \t\r\n")})
+		prev = end
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, false
+	}
+	rest := line[dec.InputOffset():]
+
+	out := make([]byte, 0, len(line))
+	out = append(out, '{')
+	add := func(m member) {
+		if len(out) > 1 {
+			out = append(out, ',')
+		}
+		out = append(out, m.data...)
+	}
+	for i, key := range order {
+		if slices.Index(order, key) < i {
+			continue // Listed twice
+		}
+		for _, m := range members {
+			if m.key == key {
+				add(m)
+			}
+		}
+	}
+	for _, m := range members {
+		if !slices.Contains(order, m.key) {
+			add(m)
+		}
+	}
+	out = append(out, '}')
+	return append(out, rest...), true
+}
@@ -0,0 +1,82 @@
+package goslogx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// topLevelKeys returns the keys of the JSON object line in order.
+func topLevelKeys(t *testing.T, line []byte) []string {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if _, err := dec.Token(); err != nil {
+		t.Fatalf("Failed to decode %q: %v", line, err)
+	}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			t.Fatalf("Failed to decode %q: %v", line, err)
+		}
+		keys = append(keys, tok.(string))
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			t.Fatalf("Failed to decode %q: %v", line, err)
+		}
+	}
+	return keys
+}
+
+func TestFieldOrder(t *testing.T) {
+	order := []string{KeySeverity, KeyTraceID, KeyModule, KeyMessage}
+
+	t.Run("Leading", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf), WithFieldOrder(order)).With(map[string]any{"tenant": "acme"})
+		logger.Info("trace-1", "orders", MESSAGE_TYPE_EVENT, "order placed", map[string]any{"id": 42})
+		logger.Error("trace-2", "orders", errors.New("boom"))
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		if len(lines) != 2 {
+			t.Fatalf("Expected 2 entries, got %q", buf.String())
+		}
+		for _, line := range lines {
+			keys := topLevelKeys(t, line)
+			if len(keys) < len(order) || !reflect.DeepEqual(keys[:len(order)], order) {
+				t.Errorf("Expected keys to start with %v, got %v", order, keys)
+			}
+		}
+		// The rest keep their usual order
+		want := []string{KeyLevel, KeyTime, KeyApplicationName, "tenant", KeyMsgType, KeyData}
+		if keys := topLevelKeys(t, lines[0]); !reflect.DeepEqual(keys[len(order):], want) {
+			t.Errorf("Expected remaining keys %v, got %v", want, keys[len(order):])
+		}
+		var entry map[string]any
+		if err := json.Unmarshal(lines[0], &entry); err != nil || entry["data"].(map[string]any)["id"] != float64(42) {
+			t.Errorf("Expected values kept intact, got %s (%v)", lines[0], err)
+		}
+	})
+
+	t.Run("Reorder", func(t *testing.T) {
+		tests := []struct {
+			line, want string
+			order      []string
+		}{
+			{`{"a":1,"b":"x,}","c":{"d":[1,2]}}` + "\n", `{"c":{"d":[1,2]},"a":1,"b":"x,}"}` + "\n", []string{"c", "missing", "a", "c"}},
+			{`{}`, `{}`, []string{"a"}},
+			{`{"a":1,"a":2,"b":3}`, `{"b":3,"a":1,"a":2}`, []string{"b"}},
+		}
+		for _, tt := range tests {
+			got, ok := reorderJSON([]byte(tt.line), tt.order)
+			if !ok || string(got) != tt.want {
+				t.Errorf("reorderJSON(%q, %v) = %q, %v; want %q", tt.line, tt.order, got, ok, tt.want)
+			}
+		}
+		if _, ok := reorderJSON([]byte("not json"), []string{"a"}); ok {
+			t.Error("Expected invalid JSON to be left alone")
+		}
+	})
+}
@@ -0,0 +1,18 @@
+module github.com/muhammadluth/goslogx/otelx
+
+go 1.24.0
+
+require (
+	github.com/muhammadluth/goslogx v0.0.0
+	go.opentelemetry.io/otel/trace v1.39.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	go.opentelemetry.io/otel v1.39.0 // indirect
+	go.uber.org/multierr v1.11.0 // indirect
+	go.uber.org/zap v1.27.1 // indirect
+)
+
+replace github.com/muhammadluth/goslogx => ../
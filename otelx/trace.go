@@ -0,0 +1,47 @@
+// Package otelx correlates goslogx entries with OpenTelemetry traces.
+// It lives in its own module so that users of goslogx who do not trace
+// do not pull in the OpenTelemetry API.
+//
+// Basic Usage:
+//
+//	goslogx.New(
+//		goslogx.WithServiceName("user-service"),
+//		otelx.WithTracing(),
+//	)
+//	goslogx.InfoCtx(ctx, traceID, "handler", goslogx.MESSAGE_TYPE_EVENT, "request received", nil)
+package otelx
+
+import (
+	"context"
+
+	"github.com/muhammadluth/goslogx"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTracing returns a goslogx option that takes the trace_id of entries
+// logged with InfoCtx, ErrorCtx, and the other Ctx functions from the span
+// active in the context, and adds its span_id. Both are hex-encoded as in
+// the W3C traceparent header. Without a valid span, the traceID argument is
+// logged unchanged.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    otelx.WithTracing(),
+//	)
+//	ctx, span := tracer.Start(ctx, "GetUser")
+//	defer span.End()
+//	logger.InfoCtx(ctx, "", "handler", goslogx.MESSAGE_TYPE_EVENT, "fetching user", nil)
+func WithTracing() goslogx.Option {
+	return goslogx.WithTraceExtractor(SpanContext)
+}
+
+// SpanContext is a goslogx.TraceExtractor for OpenTelemetry spans.
+func SpanContext(ctx context.Context) (traceID string, spanID string, ok bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", "", false
+	}
+	return sc.TraceID().String(), sc.SpanID().String(), true
+}
@@ -0,0 +1,52 @@
+package otelx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/muhammadluth/goslogx"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestWithTracing verifies the hex trace and span IDs of the active span are logged
+func TestWithTracing(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := goslogx.New(goslogx.WithOutput(buf), WithTracing())
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantTraceID string
+		wantSpanID  any
+	}{
+		{"ActiveSpan", ctx, "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"},
+		{"NoSpan", context.Background(), "manual-trace", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			logger.InfoCtx(tt.ctx, "manual-trace", "handler", goslogx.MESSSAGE_TYPE_EVENT, "fetching user", nil)
+
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("Failed to unmarshal log entry: %v (%s)", err, buf.String())
+			}
+			if entry["trace_id"] != tt.wantTraceID {
+				t.Errorf("Expected trace_id %v, got %v", tt.wantTraceID, entry["trace_id"])
+			}
+			if entry["span_id"] != tt.wantSpanID {
+				t.Errorf("Expected span_id %v, got %v", tt.wantSpanID, entry["span_id"])
+			}
+		})
+	}
+}
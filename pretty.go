@@ -0,0 +1,64 @@
+package goslogx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// prettyIndent is the indentation of each nesting level with PrettyJSON.
+const prettyIndent = "  "
+
+// prettyJSONWriter indents the JSON entries written to it, see
+// Config.PrettyJSON. A write may hold several entries, one per line, when
+// buffered by WithAsync; lines that aren't valid JSON are written as is.
+type prettyJSONWriter struct {
+	io.Writer
+}
+
+// Write implements io.Writer.
+func (w prettyJSONWriter) Write(p []byte) (int, error) {
+	var buf bytes.Buffer
+	buf.Grow(2 * len(p))
+	for rest := p; len(rest) > 0; {
+		line := rest
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			line, rest = rest[:i], rest[i+1:]
+		} else {
+			rest = nil
+		}
+		if len(line) == 0 {
+			continue
+		}
+		if err := json.Indent(&buf, line, "", prettyIndent); err != nil {
+			buf.Write(line)
+		}
+		buf.WriteByte('\n')
+	}
+	if _, err := w.Writer.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	// Report p as fully written: the indented length differs from len(p)
+	return len(p), nil
+}
+
+// Sync implements zapcore.WriteSyncer.
+func (w prettyJSONWriter) Sync() error {
+	if syncer, ok := w.Writer.(zapcore.WriteSyncer); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
+
+// prettyJSONWriters wraps each non-nil writer in ws with a prettyJSONWriter.
+func prettyJSONWriters(ws []io.Writer) []io.Writer {
+	pretty := make([]io.Writer, 0, len(ws))
+	for _, w := range ws {
+		if w != nil {
+			pretty = append(pretty, prettyJSONWriter{Writer: w})
+		}
+	}
+	return pretty
+}
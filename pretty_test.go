@@ -0,0 +1,61 @@
+package goslogx
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrettyJSON(t *testing.T) {
+	data := map[string]any{"user": map[string]any{"password": "hunter2", "name": "jane"}}
+
+	buf := &bytes.Buffer{}
+	logger := setupLog(WithOutput(buf), WithPrettyJSON(true))
+	logger.Info("trace-1", "svc", MESSAGE_TYPE_EVENT, "first", data)
+	logger.Info("trace-2", "svc", MESSAGE_TYPE_EVENT, "second", nil)
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "{\n  \"") {
+		t.Errorf("Expected indented output, got %q", out)
+	}
+	if !strings.Contains(out, "\n  \"data\": {\n    \"user\": {\n      \"name\": \"jane\",") {
+		t.Errorf("Expected nested data indented by two spaces per level, got %s", out)
+	}
+	if strings.Contains(out, "hunter2") {
+		t.Errorf("Expected masking to apply, got %s", out)
+	}
+
+	dec := json.NewDecoder(buf)
+	for _, want := range []string{"first", "second"} {
+		var entry map[string]any
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatalf("Failed to decode entry: %v", err)
+		}
+		if entry[KeyMessage] != want {
+			t.Errorf("Expected message %q, got %v", want, entry[KeyMessage])
+		}
+	}
+
+	t.Run("Async", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf), WithPrettyJSON(true), WithAsync(64*1024, time.Hour))
+		logger.Info("trace-1", "svc", MESSAGE_TYPE_EVENT, "first", nil)
+		logger.Info("trace-2", "svc", MESSAGE_TYPE_EVENT, "second", nil)
+		if err := logger.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		if got := strings.Count(buf.String(), "{\n  \""); got != 2 {
+			t.Errorf("Expected each buffered entry to be indented, got %d in %s", got, buf.String())
+		}
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		setupLog(WithOutput(buf)).Info("trace-1", "svc", MESSAGE_TYPE_EVENT, "first", data)
+		if got := strings.Count(buf.String(), "\n"); got != 1 {
+			t.Errorf("Expected single-line output by default, got %q", buf.String())
+		}
+	})
+}
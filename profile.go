@@ -0,0 +1,99 @@
+package goslogx
+
+// Built-in masking profiles, see WithMaskingProfile.
+const (
+	// MaskingProfileDefault adds nothing to the built-in sensitive fields.
+	MaskingProfileDefault = "default"
+	// MaskingProfilePCI masks payment card data (PCI DSS), such as card
+	// numbers, CVVs, and track data, and scans messages for card numbers.
+	MaskingProfilePCI = "pci"
+	// MaskingProfileHIPAA masks protected health information (HIPAA), such
+	// as medical record numbers, diagnoses, and insurance IDs, and scans
+	// messages for emails, phone numbers, and card numbers.
+	MaskingProfileHIPAA = "hipaa"
+	// MaskingProfileGDPR masks personal data (GDPR), such as names,
+	// addresses, birth dates, and national IDs, scans messages for emails
+	// and phone numbers, and anonymizes log:"masked:ip" fields.
+	MaskingProfileGDPR = "gdpr"
+)
+
+// maskingProfile is a built-in set of sensitive field patterns, matched like
+// the default ones, and the value scanning a profile turns on.
+type maskingProfile struct {
+	fields *fieldMatcher
+	apply  func(c *MaskingConfig) // Enables the profile's value scanning
+}
+
+// maskingProfiles holds the built-in profiles by name. Patterns match
+// anywhere in a field name, so they are kept specific enough not to mask
+// unrelated fields, e.g. "card_number" rather than "pan".
+var maskingProfiles = map[string]maskingProfile{
+	MaskingProfileDefault: {
+		fields: newFieldMatcher(nil, nil),
+		apply:  func(*MaskingConfig) {},
+	},
+	MaskingProfilePCI: {
+		fields: newFieldMatcher(
+			[]string{
+				"card_number", "cardnumber", "card_no", "credit_card", "debit_card",
+				"cvv", "cvc", "security_code", "pin_code", "pin_block",
+				"track_data", "track1", "track2", "magstripe",
+			},
+			[]string{"cardholder", "card_holder", "expiry", "expiration", "exp_date"},
+		),
+		apply: func(c *MaskingConfig) { c.ScanMessage = true },
+	},
+	MaskingProfileHIPAA: {
+		fields: newFieldMatcher(
+			[]string{
+				"ssn", "social_security", "mrn", "medical_record", "diagnosis",
+				"prescription", "medication", "treatment", "lab_result",
+				"health_plan", "insurance_id", "beneficiary",
+			},
+			[]string{"patient", "date_of_birth", "birth_date", "birthdate", "dob", "address"},
+		),
+		apply: func(c *MaskingConfig) { c.ScanMessage = true },
+	},
+	MaskingProfileGDPR: {
+		fields: newFieldMatcher(
+			[]string{
+				"national_id", "passport", "tax_id", "ssn", "social_security",
+				"ethnicity", "religion", "biometric", "health_data",
+				"sexual_orientation", "political",
+			},
+			[]string{
+				"first_name", "last_name", "full_name", "surname",
+				"date_of_birth", "birth_date", "birthdate", "dob",
+				"address", "postcode", "postal_code", "zip_code", "geolocation",
+			},
+		),
+		apply: func(c *MaskingConfig) {
+			c.ScanMessage = true
+			c.AnonymizeIP = true
+		},
+	},
+}
+
+// withProfiles returns mt, how the field name is masked by the default
+// patterns, raised to full or partial masking if name matches a pattern of
+// one of the configured Profiles.
+func (c *MaskingConfig) withProfiles(name string, mt maskType) maskType {
+	for _, p := range c.Profiles {
+		if mt == maskFull {
+			break
+		}
+		profile, ok := maskingProfiles[p]
+		if !ok {
+			continue
+		}
+		switch profile.fields.match(name) {
+		case maskFull:
+			mt = maskFull
+		case maskPartial:
+			if mt == maskNone {
+				mt = maskPartial
+			}
+		}
+	}
+	return mt
+}
@@ -0,0 +1,104 @@
+package goslogx
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestMaskingProfiles(t *testing.T) {
+	data := map[string]any{
+		"card_number":    "4111111111111111",
+		"cvv":            "123",
+		"card_expiry":    "12/29",
+		"medical_record": "MRN-0042",
+		"patient_name":   "Jane Doe",
+		"passport":       "X1234567",
+		"first_name":     "Jane",
+		"password":       "hunter2",
+		"order_id":       "ord-42",
+	}
+	unmasked := map[string]any{
+		"card_number": "4111111111111111", "cvv": "123", "card_expiry": "12/29",
+		"medical_record": "MRN-0042", "patient_name": "Jane Doe", "passport": "X1234567",
+		"first_name": "Jane", "password": "****", "order_id": "ord-42",
+	}
+	with := func(masked map[string]any) map[string]any {
+		want := make(map[string]any, len(unmasked))
+		for k, v := range unmasked {
+			want[k] = v
+		}
+		for k, v := range masked {
+			want[k] = v
+		}
+		return want
+	}
+
+	tests := []struct {
+		name     string
+		profiles []string
+		want     map[string]any
+		scan     bool
+		anonIP   bool
+	}{
+		{"None", nil, unmasked, false, false},
+		{"Default", []string{MaskingProfileDefault}, unmasked, false, false},
+		{"Unknown", []string{"sox"}, unmasked, false, false},
+		{"PCI", []string{MaskingProfilePCI},
+			with(map[string]any{"card_number": "****", "cvv": "****", "card_expiry": "12****29"}), true, false},
+		{"HIPAA", []string{MaskingProfileHIPAA},
+			with(map[string]any{"medical_record": "****", "patient_name": "Ja****oe"}), true, false},
+		{"GDPR", []string{MaskingProfileGDPR},
+			with(map[string]any{"passport": "****", "first_name": "****"}), true, true},
+		{"Combined", []string{MaskingProfilePCI, MaskingProfileGDPR},
+			with(map[string]any{"card_number": "****", "cvv": "****", "card_expiry": "12****29", "passport": "****", "first_name": "****"}), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			opts := []Option{WithOutput(buf)}
+			for _, p := range tt.profiles {
+				opts = append(opts, WithMaskingProfile(p))
+			}
+			logger := setupLog(opts...)
+			logger.Info("trace-1", "checkout", MESSAGE_TYPE_REQUEST, "payment submitted", data)
+			if got := decodeEntries(t, buf.Bytes())[0]["data"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+			cfg := logger.config.Masking
+			if cfg.ScanMessage != tt.scan || cfg.AnonymizeIP != tt.anonIP {
+				t.Errorf("Expected ScanMessage=%v AnonymizeIP=%v, got %v %v", tt.scan, tt.anonIP, cfg.ScanMessage, cfg.AnonymizeIP)
+			}
+		})
+	}
+
+	t.Run("JSONBody", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		setupLog(WithOutput(buf), WithMaskingProfile(MaskingProfilePCI)).Info("trace-1", "checkout", MESSAGE_TYPE_REQUEST,
+			"payment submitted", HTTPData{Body: `{"card":{"card_number":"4111111111111111","cvv":"123"},"amount":100}`})
+		body := decodeEntries(t, buf.Bytes())[0]["data"].(map[string]any)["body"]
+		if want := `{"card":{"card_number":"****","cvv":"****"},"amount":100}`; body != want {
+			t.Errorf("Expected body %s, got %v", want, body)
+		}
+	})
+
+	t.Run("ComposesWithOptions", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf), WithMaskingProfile(MaskingProfilePCI), WithMessageScanning(false),
+			WithRedactFields([]string{"order_id"}), WithAutoMaskByName(true))
+		type payment struct {
+			CardNumber string `json:"card_number"`
+			Holder     string `json:"cardholder_name" log:"masked:none"`
+		}
+		logger.Info("trace-1", "checkout", MESSAGE_TYPE_REQUEST, "paid with 4111 1111 1111 1111",
+			map[string]any{"payment": payment{"4111111111111111", "Jane Doe"}, "order_id": "ord-42"})
+		entry := decodeEntries(t, buf.Bytes())[0]
+		want := map[string]any{"payment": map[string]any{"card_number": "****", "cardholder_name": "Jane Doe"}}
+		if !reflect.DeepEqual(entry["data"], want) {
+			t.Errorf("Expected %v, got %v", want, entry["data"])
+		}
+		if entry[KeyMessage] != "paid with 4111 1111 1111 1111" {
+			t.Errorf("Expected message scanning turned off again, got %v", entry[KeyMessage])
+		}
+	})
+}
@@ -0,0 +1,116 @@
+// Package promx exports Prometheus metrics about the entries goslogx writes.
+// It lives in its own module so that users of goslogx who do not need metrics
+// do not pull in the Prometheus client.
+//
+// Basic Usage:
+//
+//	goslogx.New(
+//		goslogx.WithServiceName("user-service"),
+//		promx.WithMetrics(prometheus.DefaultRegisterer),
+//	)
+package promx
+
+import (
+	"errors"
+
+	"github.com/muhammadluth/goslogx"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogLinesMetric is the name of the counter of written log entries.
+const LogLinesMetric = "goslogx_log_lines_total"
+
+// WithMetrics returns a goslogx option that counts every written entry in a
+// goslogx_log_lines_total counter labeled by level and module, registered
+// with reg. Entries dropped by the level filter are not counted.
+//
+// Loggers built with the same registerer share the counter. Like
+// prometheus.MustRegister, it panics if the counter cannot be registered,
+// e.g. because a different collector with the same name exists.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    promx.WithMetrics(prometheus.DefaultRegisterer),
+//	)
+//	// rate(goslogx_log_lines_total{level="error"}[5m])
+func WithMetrics(reg prometheus.Registerer) goslogx.Option {
+	counter := register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: LogLinesMetric,
+		Help: "Number of log entries written, by level and module.",
+	}, []string{"level", "module"}))
+
+	return func(c *goslogx.Config) {
+		goslogx.WithWrapCore(func(core zapcore.Core) zapcore.Core {
+			// Read the key when the core is built, after WithFieldKeys is applied
+			return &countingCore{Core: core, counter: counter, moduleKey: c.FieldKeys.Module}
+		})(c)
+	}
+}
+
+// register registers counter with reg, reusing a counter registered earlier
+// under the same name.
+func register(reg prometheus.Registerer, counter *prometheus.CounterVec) *prometheus.CounterVec {
+	err := reg.Register(counter)
+	if err == nil {
+		return counter
+	}
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(*prometheus.CounterVec); ok {
+			return existing
+		}
+	}
+	panic(err)
+}
+
+// countingCore wraps a zapcore.Core to count written entries.
+type countingCore struct {
+	zapcore.Core
+	counter   *prometheus.CounterVec
+	moduleKey string
+	module    string // module bound with With, if any
+}
+
+// With implements zapcore.Core.
+func (c *countingCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	if module, ok := moduleValue(fields, c.moduleKey); ok {
+		clone.module = module
+	}
+	return &clone
+}
+
+// Check implements zapcore.Core. Routing is left to the wrapped core, which
+// is a tee with goslogx.WithErrorOutput, so each output only gets the levels
+// it accepts; c adds itself to count the entry once an output has.
+func (c *countingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if downstream := c.Core.Check(ent, ce); downstream != nil {
+		return downstream.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core. It only counts the entry: the wrapped core
+// registered itself to write it in Check.
+func (c *countingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	module, ok := moduleValue(fields, c.moduleKey)
+	if !ok {
+		module = c.module
+	}
+	c.counter.WithLabelValues(ent.Level.String(), module).Inc()
+	return nil
+}
+
+// moduleValue returns the value of the module field, if fields have one.
+func moduleValue(fields []zapcore.Field, key string) (string, bool) {
+	for i := range fields {
+		if fields[i].Key == key && fields[i].Type == zapcore.StringType {
+			return fields[i].String, true
+		}
+	}
+	return "", false
+}
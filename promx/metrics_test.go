@@ -0,0 +1,105 @@
+package promx
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muhammadluth/goslogx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestWithMetrics verifies the counter increments once per entry, by level and module
+func TestWithMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	logger := goslogx.New(
+		goslogx.WithOutput(&bytes.Buffer{}),
+		goslogx.WithDebug(true),
+		WithMetrics(reg),
+	)
+
+	logger.Debug("trace-1", "worker", goslogx.MESSSAGE_TYPE_EVENT, "debug", nil)
+	logger.Info("trace-2", "worker", goslogx.MESSSAGE_TYPE_EVENT, "info", nil)
+	logger.Warning("trace-3", "worker", "warning", nil)
+	logger.Error("trace-4", "db", errors.New("boom"))
+	logger.Error("trace-5", "db", errors.New("boom again"))
+
+	want := `
+# HELP goslogx_log_lines_total Number of log entries written, by level and module.
+# TYPE goslogx_log_lines_total counter
+goslogx_log_lines_total{level="debug",module="worker"} 1
+goslogx_log_lines_total{level="error",module="db"} 2
+goslogx_log_lines_total{level="info",module="worker"} 1
+goslogx_log_lines_total{level="warn",module="worker"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), LogLinesMetric); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestWithMetricsSharedRegistry verifies loggers sharing a registry share the
+// counter, and that a remapped module key is honored
+func TestWithMetricsSharedRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	opt := WithMetrics(reg)
+	// A second registration reuses the counter instead of panicking
+	again := WithMetrics(reg)
+
+	for _, o := range []goslogx.Option{opt, again} {
+		handler := goslogx.NewSlogHandler(goslogx.WithOutput(&bytes.Buffer{}), o,
+			goslogx.WithFieldKeys(map[string]string{"module": "component"}))
+		slog.New(handler).Info("info", "component", "api")
+	}
+	if got := testutil.ToFloat64(mustCounter(t, reg).WithLabelValues("info", "api")); got != 2 {
+		t.Errorf("Expected 2 info entries for module api, got %v", got)
+	}
+}
+
+// TestWithMetricsErrorOutputDedupSampling verifies entries are written once,
+// to the output matching their level, and that entries suppressed by error
+// deduplication or dropped by sampling are not counted
+func TestWithMetricsErrorOutputDedupSampling(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	logger := goslogx.NewLogger(
+		goslogx.WithOutput(out),
+		goslogx.WithErrorOutput(errOut),
+		goslogx.WithErrorDedup(time.Minute),
+		goslogx.WithSampling(2, 100),
+		WithMetrics(reg),
+	)
+
+	for i := 0; i < 5; i++ {
+		logger.Info("trace-1", "worker", goslogx.MESSSAGE_TYPE_EVENT, "polling", nil)
+	}
+	for i := 0; i < 2; i++ {
+		logger.Error("trace-2", "db", errors.New("timeout"))
+	}
+	if got := strings.Count(out.String(), "\n"); got != 2 {
+		t.Errorf("Expected the 2 sampled info entries in the output, got %s", out.String())
+	}
+	if got := strings.Count(errOut.String(), "\n"); got != 1 {
+		t.Errorf("Expected the first error only in the error output, got %s", errOut.String())
+	}
+
+	counter := mustCounter(t, reg)
+	if got := testutil.ToFloat64(counter.WithLabelValues("info", "worker")); got != 2 {
+		t.Errorf("Expected the 2 sampled info entries to be counted, got %v", got)
+	}
+	if got := testutil.ToFloat64(counter.WithLabelValues("error", "db")); got != 1 {
+		t.Errorf("Expected the suppressed repeat not to be counted, got %v", got)
+	}
+}
+
+// mustCounter returns the log lines counter registered with reg.
+func mustCounter(t *testing.T, reg *prometheus.Registry) *prometheus.CounterVec {
+	t.Helper()
+	return register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: LogLinesMetric,
+		Help: "Number of log entries written, by level and module.",
+	}, []string{"level", "module"}))
+}
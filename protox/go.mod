@@ -0,0 +1,16 @@
+module github.com/muhammadluth/goslogx/protox
+
+go 1.24.0
+
+require (
+	github.com/muhammadluth/goslogx v0.0.0
+	google.golang.org/protobuf v1.36.10
+)
+
+require (
+	github.com/pkg/errors v0.9.1 // indirect
+	go.uber.org/multierr v1.11.0 // indirect
+	go.uber.org/zap v1.27.1 // indirect
+)
+
+replace github.com/muhammadluth/goslogx => ../
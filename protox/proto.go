@@ -0,0 +1,167 @@
+// Package protox masks protobuf messages logged through goslogx by their
+// field names, the same way JSON bodies are masked. It lives in its own
+// module so that users of goslogx who do not use protobuf do not pull in
+// the protobuf runtime.
+//
+// Basic Usage:
+//
+//	goslogx.New(
+//		goslogx.WithServiceName("user-service"),
+//		protox.WithProtobuf(),
+//	)
+//	goslogx.Info(traceID, "auth", goslogx.MESSAGE_TYPE_IN, "login", req) // req is a proto.Message
+package protox
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/muhammadluth/goslogx"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// wellKnownPrefix is the package of the well-known types, such as
+// google.protobuf.Timestamp, which are logged in their JSON form.
+const wellKnownPrefix = "google.protobuf."
+
+// WithProtobuf returns a goslogx option that logs proto.Message values, as
+// data or held in fields such as MQData.Payload, as objects keyed by their
+// proto field names, so that fields such as password are masked like any
+// other. Without it, messages are logged by reflection over the generated
+// Go struct, by their JSON tags.
+//
+// Fields marked with the standard [debug_redact = true] option are always
+// masked, as are fields whose options set any of the bool extensions given
+// as sensitiveOptions to true, for services that define their own option.
+// Enums are logged by name and well-known types, such as
+// google.protobuf.Timestamp, in their protojson form.
+//
+// Example:
+//
+//	// message LoginRequest {
+//	//   string username = 1;
+//	//   string password = 2;
+//	//   string otp = 3 [(acme.sensitive) = true];
+//	// }
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    protox.WithProtobuf(acmepb.E_Sensitive),
+//	)
+//	logger.Info(traceID, "auth", goslogx.MESSAGE_TYPE_IN, "login", req)
+//	// "data":{"otp":"****","password":"****","username":"johndoe"}
+func WithProtobuf(sensitiveOptions ...protoreflect.ExtensionType) goslogx.Option {
+	c := converter{sensitive: sensitiveOptions}
+	return goslogx.WithDataConverter(c.convert)
+}
+
+// converter converts protobuf messages into maps keyed by field name.
+type converter struct {
+	sensitive []protoreflect.ExtensionType // Bool field options that mark a field sensitive
+}
+
+// convert implements goslogx.DataConverter.
+func (c converter) convert(v any) (any, bool) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, false
+	}
+	return c.message(msg.ProtoReflect()), true
+}
+
+// message returns m as a map of its populated fields, nil for a nil
+// message, or the decoded protojson form of a well-known type.
+func (c converter) message(m protoreflect.Message) any {
+	if !m.IsValid() {
+		return nil
+	}
+	if strings.HasPrefix(string(m.Descriptor().FullName()), wellKnownPrefix) {
+		return wellKnown(m)
+	}
+	fields := make(map[string]any)
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := string(fd.Name())
+		if fd.IsExtension() {
+			name = "[" + string(fd.FullName()) + "]"
+		}
+		if c.redacted(fd) {
+			fields[name] = "****"
+			return true
+		}
+		fields[name] = c.field(fd, v)
+		return true
+	})
+	return fields
+}
+
+// field returns the value v of the field fd, converting lists and maps
+// element by element.
+func (c converter) field(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		elems := make([]any, list.Len())
+		for i := range elems {
+			elems[i] = c.singular(fd, list.Get(i))
+		}
+		return elems
+	case fd.IsMap():
+		entries := make(map[string]any, v.Map().Len())
+		v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			entries[k.String()] = c.singular(fd.MapValue(), mv)
+			return true
+		})
+		return entries
+	}
+	return c.singular(fd, v)
+}
+
+// singular returns the value v of a non-repeated field of fd's kind.
+func (c converter) singular(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return c.message(v.Message())
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	}
+	return v.Interface()
+}
+
+// redacted reports whether the field fd is always masked, by the
+// debug_redact option or one of the sensitive options.
+func (c converter) redacted(fd protoreflect.FieldDescriptor) bool {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return false
+	}
+	if opts.GetDebugRedact() {
+		return true
+	}
+	for _, xt := range c.sensitive {
+		if proto.HasExtension(opts, xt) {
+			if sensitive, ok := proto.GetExtension(opts, xt).(bool); ok && sensitive {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wellKnown returns the well-known type m decoded from its protojson form,
+// or nil if it can't be marshaled, e.g. an Any of an unregistered type.
+func wellKnown(m protoreflect.Message) any {
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m.Interface())
+	if err != nil {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,238 @@
+package protox
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/muhammadluth/goslogx"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// sensitiveFile declares the acme.sensitive bool field option:
+//
+//	extend google.protobuf.FieldOptions { bool sensitive = 50000; }
+var sensitiveFile = &descriptorpb.FileDescriptorProto{
+	Name:       proto.String("acme/options.proto"),
+	Package:    proto.String("acme"),
+	Dependency: []string{"google/protobuf/descriptor.proto"},
+	Syntax:     proto.String("proto3"),
+	Extension: []*descriptorpb.FieldDescriptorProto{{
+		Name:     proto.String("sensitive"),
+		Number:   proto.Int32(50000),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_BOOL.Enum(),
+		Extendee: proto.String(".google.protobuf.FieldOptions"),
+		JsonName: proto.String("sensitive"),
+	}},
+}
+
+// loginFile declares the messages logged by the tests:
+//
+//	message LoginRequest {
+//	  string username = 1;
+//	  string password = 2;
+//	  string otp = 3 [(acme.sensitive) = true];
+//	  string session = 4 [debug_redact = true];
+//	  Device device = 5;
+//	  repeated Device devices = 6;
+//	  map<string, string> labels = 7;
+//	  Channel channel = 8;
+//	  google.protobuf.Timestamp at = 9;
+//	}
+//	message Device { string name = 1; string api_key = 2; }
+//	enum Channel { CHANNEL_UNSPECIFIED = 0; CHANNEL_WEB = 1; }
+func loginFile(sensitive protoreflect.ExtensionType) *descriptorpb.FileDescriptorProto {
+	otpOptions := &descriptorpb.FieldOptions{}
+	proto.SetExtension(otpOptions, sensitive, true)
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     typ.Enum(),
+			JsonName: proto.String(name),
+		}
+	}
+	typed := func(f *descriptorpb.FieldDescriptorProto, typeName string) *descriptorpb.FieldDescriptorProto {
+		f.TypeName = proto.String(typeName)
+		return f
+	}
+	repeated := func(f *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+		f.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		return f
+	}
+	otp := field("otp", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING)
+	otp.Options = otpOptions
+	session := field("session", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING)
+	session.Options = &descriptorpb.FieldOptions{DebugRedact: proto.Bool(true)}
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("acme/login.proto"),
+		Package:    proto.String("acme"),
+		Dependency: []string{"acme/options.proto", "google/protobuf/timestamp.proto"},
+		Syntax:     proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("LoginRequest"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("username", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+				field("password", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+				otp,
+				session,
+				typed(field("device", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE), ".acme.Device"),
+				repeated(typed(field("devices", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE), ".acme.Device")),
+				repeated(typed(field("labels", 7, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE), ".acme.LoginRequest.LabelsEntry")),
+				typed(field("channel", 8, descriptorpb.FieldDescriptorProto_TYPE_ENUM), ".acme.Channel"),
+				typed(field("at", 9, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE), ".google.protobuf.Timestamp"),
+			},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("LabelsEntry"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+					field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+				},
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+		}, {
+			Name: proto.String("Device"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+				field("api_key", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+			},
+		}},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Channel"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("CHANNEL_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("CHANNEL_WEB"), Number: proto.Int32(1)},
+			},
+		}},
+	}
+}
+
+// newLoginRequest returns a populated dynamic LoginRequest and the
+// acme.sensitive option it uses.
+func newLoginRequest(t *testing.T) (*dynamicpb.Message, protoreflect.ExtensionType) {
+	t.Helper()
+	files := new(protoregistry.Files)
+	for _, path := range []string{"google/protobuf/descriptor.proto", "google/protobuf/timestamp.proto"} {
+		fd, err := protoregistry.GlobalFiles.FindFileByPath(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := files.RegisterFile(fd); err != nil {
+			t.Fatal(err)
+		}
+	}
+	optionsFd, err := protodesc.NewFile(sensitiveFile, files)
+	if err != nil {
+		t.Fatalf("Failed to build options.proto: %v", err)
+	}
+	if err := files.RegisterFile(optionsFd); err != nil {
+		t.Fatal(err)
+	}
+	sensitive := dynamicpb.NewExtensionType(optionsFd.Extensions().Get(0))
+	loginFd, err := protodesc.NewFile(loginFile(sensitive), files)
+	if err != nil {
+		t.Fatalf("Failed to build login.proto: %v", err)
+	}
+
+	device := func(name, apiKey string) protoreflect.Value {
+		m := dynamicpb.NewMessage(loginFd.Messages().ByName("Device"))
+		m.Set(m.Descriptor().Fields().ByName("name"), protoreflect.ValueOfString(name))
+		if apiKey != "" {
+			m.Set(m.Descriptor().Fields().ByName("api_key"), protoreflect.ValueOfString(apiKey))
+		}
+		return protoreflect.ValueOfMessage(m)
+	}
+	req := dynamicpb.NewMessage(loginFd.Messages().ByName("LoginRequest"))
+	fields := req.Descriptor().Fields()
+	req.Set(fields.ByName("username"), protoreflect.ValueOfString("johndoe"))
+	req.Set(fields.ByName("password"), protoreflect.ValueOfString("hunter2"))
+	req.Set(fields.ByName("otp"), protoreflect.ValueOfString("123456"))
+	req.Set(fields.ByName("session"), protoreflect.ValueOfString("sess-abc"))
+	req.Set(fields.ByName("device"), device("laptop", "key-123"))
+	devices := req.NewField(fields.ByName("devices")).List()
+	devices.Append(device("phone", ""))
+	req.Set(fields.ByName("devices"), protoreflect.ValueOfList(devices))
+	labels := req.NewField(fields.ByName("labels")).Map()
+	labels.Set(protoreflect.ValueOfString("env").MapKey(), protoreflect.ValueOfString("prod"))
+	labels.Set(protoreflect.ValueOfString("token").MapKey(), protoreflect.ValueOfString("abc"))
+	req.Set(fields.ByName("labels"), protoreflect.ValueOfMap(labels))
+	req.Set(fields.ByName("channel"), protoreflect.ValueOfEnum(1))
+	at := timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	req.Set(fields.ByName("at"), protoreflect.ValueOfMessage(at.ProtoReflect()))
+	return req, sensitive
+}
+
+// TestWithProtobuf verifies messages are logged by proto field name with sensitive fields masked
+func TestWithProtobuf(t *testing.T) {
+	req, sensitive := newLoginRequest(t)
+	buf := &bytes.Buffer{}
+	logger := goslogx.NewLogger(goslogx.WithOutput(buf), WithProtobuf(sensitive))
+
+	logger.Info("trace-1", "auth", goslogx.MESSAGE_TYPE_IN, "login", req)
+	logger.Info("trace-2", "auth", goslogx.MESSAGE_TYPE_IN, "queued", goslogx.MQData{Topic: "logins", Payload: req})
+	logger.Info("trace-3", "auth", goslogx.MESSAGE_TYPE_IN, "nil", (*timestamppb.Timestamp)(nil))
+	logger.Info("trace-4", "auth", goslogx.MESSAGE_TYPE_IN, "plain", map[string]any{"password": "hunter2"})
+
+	var entries []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var entry map[string]any
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatalf("Failed to decode entry: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(entries))
+	}
+
+	want := map[string]any{
+		"username": "jo****oe",
+		"password": "****",
+		"otp":      "****",
+		"session":  "****",
+		"device":   map[string]any{"name": "laptop", "api_key": "ke****23"},
+		"devices":  []any{map[string]any{"name": "phone"}},
+		"labels":   map[string]any{"env": "prod", "token": "****"},
+		"channel":  "CHANNEL_WEB",
+		"at":       "2024-01-02T03:04:05Z",
+	}
+	if data := entries[0]["data"]; !reflect.DeepEqual(data, want) {
+		t.Errorf("Expected data %v, got %v", want, data)
+	}
+	if payload := entries[1]["data"].(map[string]any)["payload"]; !reflect.DeepEqual(payload, want) {
+		t.Errorf("Expected payload %v, got %v", want, payload)
+	}
+	if data, ok := entries[2]["data"]; ok {
+		t.Errorf("Expected a nil message to omit data, got %v", data)
+	}
+	if data := entries[3]["data"].(map[string]any); data["password"] != "****" {
+		t.Errorf("Expected other values to be masked as before, got %v", data)
+	}
+}
+
+// TestWithProtobufOptions verifies custom options only mask when given
+func TestWithProtobufOptions(t *testing.T) {
+	req, _ := newLoginRequest(t)
+	data, ok := converter{}.convert(req)
+	if !ok {
+		t.Fatal("Expected a message to be converted")
+	}
+	fields := data.(map[string]any)
+	if fields["otp"] != "123456" || fields["session"] != "****" {
+		t.Errorf("Expected only debug_redact without sensitive options, got otp=%v session=%v", fields["otp"], fields["session"])
+	}
+	if _, ok := (converter{}).convert("not a message"); ok {
+		t.Error("Expected non-proto values to be left to goslogx")
+	}
+}
@@ -0,0 +1,7 @@
+//go:build race
+
+package goslogx
+
+// raceEnabled reports whether tests run with the race detector, which adds
+// allocations that AllocsPerRun bounds don't account for.
+const raceEnabled = true
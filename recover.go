@@ -0,0 +1,111 @@
+package goslogx
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxPanicStackSize caps the goroutine stack captured for a recovered panic.
+const maxPanicStackSize = 64 << 10
+
+// continueAfterFatal is a zapcore.CheckWriteHook that does nothing, so a
+// recovered panic can be logged at fatal level without exiting the process.
+type continueAfterFatal struct{}
+
+func (continueAfterFatal) OnWrite(*zapcore.CheckedEntry, []zap.Field) {}
+
+// exitAfterFatal is a zapcore.CheckWriteHook that calls the function set via
+// WithExitFunc with exit code 1, in place of zap's os.Exit.
+type exitAfterFatal func(code int)
+
+func (exit exitAfterFatal) OnWrite(*zapcore.CheckedEntry, []zap.Field) { exit(1) }
+
+// RecoverAndLog returns a function that recovers a panic, logs it at fatal
+// level with CRITICAL severity and the panicking goroutine's stack trace, and
+// then either returns normally or panics again with the same value if
+// WithRepanic(true) is set. It must be deferred directly, as shown below, for
+// recover to stop the panic. The process is not exited either way.
+//
+// Example:
+//
+//	func handle(w http.ResponseWriter, r *http.Request) {
+//	    defer logger.RecoverAndLog(traceID, "handler")()
+//	    // ...
+//	}
+func (l *Logger) RecoverAndLog(traceID string, module string) func() {
+	return func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		l.logPanic(traceID, module, r)
+		if l.config.Repanic {
+			panic(r)
+		}
+	}
+}
+
+// RecoverAndLog returns a function that recovers a panic and logs it using the
+// global logger. It must be deferred directly:
+//
+//	defer goslogx.RecoverAndLog(traceID, "worker")()
+func RecoverAndLog(traceID string, module string) func() {
+	return globalLog.Load().RecoverAndLog(traceID, module)
+}
+
+// logPanic logs a recovered panic value. It must be called by the deferred
+// function that recovered, so the source points at the panicking function.
+func (l *Logger) logPanic(traceID string, module string, r any) {
+	fields := getFields()
+	defer putFields(fields)
+
+	callerSkip := panicCallerSkip(l.config.CallerMaxDepth)
+	keys := &l.config.FieldKeys
+
+	err, ok := r.(error)
+	if !ok {
+		err = errors.New(fmt.Sprint(r))
+	}
+	logger := l.logger.WithOptions(
+		zap.AddCaller(),
+		zap.AddCallerSkip(callerSkip),
+		// The stack below replaces zap's, which would start in this function
+		zap.AddStacktrace(zapcore.InvalidLevel),
+		zap.WithFatalHook(continueAfterFatal{}),
+	)
+	fields = append(fields,
+		zap.String(keys.TraceID, traceID),
+		zap.String(keys.Module, l.module(module)),
+		errorField(keys.Error, err),
+		zap.String(keys.Severity, l.severity(zapcore.FatalLevel)),
+	)
+	fields = l.appendErrorDetails(fields, err)
+	fields = append(fields, l.stackField(goroutineStack()))
+	logger.Log(zapcore.FatalLevel, "panic recovered", fields...)
+}
+
+// panicCallerSkip is detectCallerSkip for a recovered panic: it also skips
+// the runtime's panic machinery, finding the function that panicked.
+func panicCallerSkip(maxDepth int) int {
+	return findCallerSkip(maxDepth, func(fn string) bool {
+		return isPackageFrame(fn) || strings.HasPrefix(fn, "runtime.")
+	})
+}
+
+// goroutineStack returns the current goroutine's stack as formatted by
+// runtime.Stack, growing the buffer up to maxPanicStackSize.
+func goroutineStack() string {
+	buf := make([]byte, 4<<10)
+	for {
+		n := runtime.Stack(buf, false)
+		if n < len(buf) || len(buf) >= maxPanicStackSize {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
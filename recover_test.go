@@ -0,0 +1,78 @@
+package goslogx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/muhammadluth/goslogx"
+)
+
+// guarded panics inside a function protected by RecoverAndLog,
+// storing the line of the panic in line.
+func guarded(line *int) {
+	defer goslogx.RecoverAndLog("trace-001", "worker")()
+	*line = callerLine() + 1
+	panic("something went wrong")
+}
+
+// TestRecoverAndLog verifies a recovered panic is logged and swallowed
+func TestRecoverAndLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf))()
+
+	var line int
+	guarded(&line)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to unmarshal log entry: %v (%s)", err, buf.String())
+	}
+	if entry["level"] != "fatal" || entry["severity"] != "CRITICAL" {
+		t.Errorf("Expected fatal/CRITICAL entry, got %v/%v", entry["level"], entry["severity"])
+	}
+	if entry["error"] != "something went wrong" {
+		t.Errorf("Expected recovered value as error, got %v", entry["error"])
+	}
+	want := fmt.Sprintf("recover_test.go:%d", line)
+	if source, _ := entry["source"].(string); !strings.HasSuffix(source, want) {
+		t.Errorf("Expected source ending in %s, got %q", want, source)
+	}
+	stack, _ := entry["stack_trace"].(string)
+	if !strings.HasPrefix(stack, "[goroutine ") || !strings.Contains(stack, "goslogx_test.guarded") {
+		t.Errorf("Expected formatted goroutine stack through guarded, got %q", stack)
+	}
+}
+
+// TestRecoverAndLogRepanic verifies WithRepanic panics again after logging
+func TestRecoverAndLogRepanic(t *testing.T) {
+	buf := &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf), goslogx.WithRepanic(true))()
+
+	defer func() {
+		if r := recover(); r != "something went wrong" {
+			t.Errorf("Expected the original panic value, got %v", r)
+		}
+		if !strings.Contains(buf.String(), "panic recovered") {
+			t.Errorf("Expected the panic to be logged before re-panicking, got %s", buf.String())
+		}
+	}()
+	var line int
+	guarded(&line)
+	t.Error("Expected guarded to panic again")
+}
+
+// TestRecoverAndLogNoPanic verifies nothing is logged without a panic
+func TestRecoverAndLogNoPanic(t *testing.T) {
+	buf := &bytes.Buffer{}
+	defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf))()
+
+	func() {
+		defer goslogx.RecoverAndLog("trace-002", "worker")()
+	}()
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output, got %s", buf.String())
+	}
+}
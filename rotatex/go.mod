@@ -0,0 +1,16 @@
+module github.com/muhammadluth/goslogx/rotatex
+
+go 1.24.0
+
+require (
+	github.com/muhammadluth/goslogx v0.0.0
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1
+)
+
+require (
+	github.com/pkg/errors v0.9.1 // indirect
+	go.uber.org/multierr v1.11.0 // indirect
+	go.uber.org/zap v1.27.1 // indirect
+)
+
+replace github.com/muhammadluth/goslogx => ../
@@ -0,0 +1,53 @@
+// Package rotatex writes goslogx entries to a file that is rotated by size
+// and pruned by age and count, using lumberjack. It lives in its own module
+// so that users of goslogx who log to stdout do not pull in lumberjack.
+//
+// Basic Usage:
+//
+//	logger := goslogx.New(
+//		goslogx.WithServiceName("user-service"),
+//		rotatex.WithFileRotation("/var/log/user-service/app.log", 100, 7, 30, true),
+//	)
+//	defer logger.Close()
+package rotatex
+
+import (
+	"github.com/muhammadluth/goslogx"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// WithFileRotation returns a goslogx option that writes entries to the file
+// at path instead of Output, creating it and its directory if needed. Once
+// the file would exceed maxSizeMB megabytes it is renamed to a timestamped
+// backup, e.g. app-2024-01-02T15-04-05.000.log, and a new file is started.
+// At most maxBackups backups, none older than maxAgeDays days, are kept, and
+// backups are gzipped when compress is set. A maxSizeMB of 0 uses 100 MB,
+// and a maxBackups or maxAgeDays of 0 keeps backups regardless of count or
+// age respectively.
+//
+// Stack traces are formatted as for any other output. Entries are written
+// to the file as they are logged, so Sync has nothing to flush; Close
+// closes the file after flushing any WithAsync buffer.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    rotatex.WithFileRotation("/var/log/my-service/app.log", 100, 7, 30, true),
+//	)
+//	defer logger.Close()
+func WithFileRotation(path string, maxSizeMB, maxBackups, maxAgeDays int, compress bool) goslogx.Option {
+	// Shared by every logger built with the option, so rotation is never
+	// done by two writers at once
+	file := &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    maxSizeMB,
+		MaxBackups: maxBackups,
+		MaxAge:     maxAgeDays,
+		Compress:   compress,
+	}
+	return func(c *goslogx.Config) {
+		goslogx.WithOutput(file)(c)
+		goslogx.WithCloser(file.Close)(c)
+	}
+}
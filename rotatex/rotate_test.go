@@ -0,0 +1,75 @@
+package rotatex
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/muhammadluth/goslogx"
+)
+
+// TestWithFileRotation verifies that writing past maxSizeMB rotates the file into a backup
+func TestWithFileRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	logger := goslogx.NewLogger(
+		goslogx.WithServiceName("rotate-test"),
+		WithFileRotation(path, 1, 3, 0, false),
+	)
+
+	// Just over 1 MB of entries, so exactly one rotation happens
+	data := map[string]any{"payload": strings.Repeat("x", 1024)}
+	for i := 0; i < 1100; i++ {
+		logger.Info("trace-1", "worker", goslogx.MESSAGE_TYPE_EVENT, "tick", data)
+	}
+	func() {
+		defer logger.RecoverAndLog("trace-2", "worker")()
+		panic("last entry")
+	}()
+	if err := logger.Sync(); err != nil {
+		t.Errorf("Sync failed: %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("Expected 1 backup file, got %v", backups)
+	}
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the current log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(current), "\n"), "\n")
+	last := lines[len(lines)-1]
+	if !strings.Contains(last, `"error":"last entry"`) || !strings.Contains(last, `"stack_trace":"[goroutine `) {
+		t.Errorf("Expected the last entry with an inline stack trace, got %q", last)
+	}
+}
+
+// TestWithFileRotationAsync verifies that Close flushes buffered entries before closing the file
+func TestWithFileRotationAsync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	logger := goslogx.NewLogger(
+		WithFileRotation(path, 10, 0, 0, false),
+		goslogx.WithAsync(0, 0),
+	)
+	for i := 0; i < 10; i++ {
+		logger.Info("trace-1", "worker", goslogx.MESSAGE_TYPE_EVENT, "buffered", nil)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the log file to be created with its directory: %v", err)
+	}
+	if n := strings.Count(string(content), `"msg":"buffered"`); n != 10 {
+		t.Errorf("Expected 10 entries after Close, got %d", n)
+	}
+}
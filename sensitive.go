@@ -0,0 +1,79 @@
+package goslogx
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// sensitiveEncoder replaces known secret values, see WithSensitiveValues,
+// anywhere in the entries encoded by the wrapped encoder: in the message,
+// field values, context fields, errors, and stack traces alike.
+type sensitiveEncoder struct {
+	zapcore.Encoder
+	replacer *strings.Replacer
+}
+
+// newSensitiveReplacer returns a replacer of each non-empty value in values
+// with "****", both as-is and as escaped in a JSON string, or nil if there
+// are none. Longer values are replaced first, so a value containing another
+// is masked whole.
+func newSensitiveReplacer(values []string) *strings.Replacer {
+	var olds []string
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		olds = append(olds, v)
+		if escaped := jsonEscape(v); escaped != v {
+			olds = append(olds, escaped)
+		}
+	}
+	if len(olds) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(olds, func(a, b string) int {
+		return cmp.Compare(len(b), len(a))
+	})
+	oldnew := make([]string, 0, 2*len(olds))
+	for _, old := range olds {
+		oldnew = append(oldnew, old, "****")
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+// jsonEscape returns s escaped as in a JSON string, without the quotes.
+func jsonEscape(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return s
+	}
+	// Trim the quotes and the newline added by Encode
+	return string(buf.Bytes()[1 : buf.Len()-2])
+}
+
+// Clone implements zapcore.Encoder.
+func (e *sensitiveEncoder) Clone() zapcore.Encoder {
+	return &sensitiveEncoder{Encoder: e.Encoder.Clone(), replacer: e.replacer}
+}
+
+// EncodeEntry implements zapcore.Encoder.
+func (e *sensitiveEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	line := buf.String()
+	if replaced := e.replacer.Replace(line); replaced != line {
+		buf.Reset()
+		buf.AppendString(replaced)
+	}
+	return buf, nil
+}
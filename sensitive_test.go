@@ -0,0 +1,57 @@
+package goslogx
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSensitiveValues(t *testing.T) {
+	const token = "tok_9f8e7d6c"
+	const quoted = `pa"ss\word`
+	buf := &bytes.Buffer{}
+	logger := setupLog(
+		WithOutput(buf),
+		WithSensitiveValues([]string{token, ""}),
+		WithSensitiveValues([]string{quoted}),
+	).With(map[string]any{"client": "svc-" + token})
+
+	logger.Info("trace-1", "payment", MESSAGE_TYPE_REQUEST, "calling with "+token, GenericData{
+		Service: "Stripe",
+		Fields:  map[string]any{"note": "retry after " + token + " expired", "attempt": 2},
+	})
+	logger.Error("trace-2", "payment", errors.New("bad credentials "+quoted))
+
+	out := buf.String()
+	if strings.Contains(out, token) || strings.Contains(out, `pa\"ss\\word`) {
+		t.Fatalf("Expected sensitive values to be masked, got %s", out)
+	}
+	entries := decodeEntries(t, buf.Bytes())
+	if msg := entries[0][KeyMessage]; msg != "calling with ****" {
+		t.Errorf("Expected the message to be masked, got %v", msg)
+	}
+	if client := entries[0]["client"]; client != "svc-****" {
+		t.Errorf("Expected the context field to be masked, got %v", client)
+	}
+	fields := entries[0][KeyData].(map[string]any)["fields"].(map[string]any)
+	if note := fields["note"]; note != "retry after **** expired" {
+		t.Errorf("Expected the unrelated field to be masked, got %v", note)
+	}
+	if service := entries[0][KeyData].(map[string]any)["service"]; service != "Stripe" {
+		t.Errorf("Expected other values to be kept, got %v", service)
+	}
+	if err := entries[1][KeyError]; err != "bad credentials ****" {
+		t.Errorf("Expected the JSON-escaped value to be masked, got %v", err)
+	}
+}
+
+func TestNewSensitiveReplacer(t *testing.T) {
+	if r := newSensitiveReplacer([]string{"", ""}); r != nil {
+		t.Errorf("Expected no replacer for empty values, got %v", r)
+	}
+	r := newSensitiveReplacer([]string{"abc", "abcdef"})
+	if got := r.Replace("x abcdef y abc"); got != "x **** y ****" {
+		t.Errorf("Expected the longer value to be masked whole, got %q", got)
+	}
+}
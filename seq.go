@@ -0,0 +1,44 @@
+package goslogx
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// seqCore wraps a zapcore.Core to number every written entry with a
+// monotonically increasing "seq" field, see WithSequence.
+// Cores derived with With share the counter of the core they came from.
+type seqCore struct {
+	zapcore.Core
+	key  string
+	next *atomic.Uint64
+}
+
+// newSeqCore wraps core so that each written entry carries its sequence
+// number, drawn from next. Cores sharing next number entries as one stream.
+func newSeqCore(core zapcore.Core, keys *FieldKeys, next *atomic.Uint64) zapcore.Core {
+	return &seqCore{Core: core, key: keys.Seq, next: next}
+}
+
+// With implements zapcore.Core.
+func (c *seqCore) With(fields []zapcore.Field) zapcore.Core {
+	return &seqCore{Core: c.Core.With(fields), key: c.key, next: c.next}
+}
+
+// Check implements zapcore.Core.
+func (c *seqCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core. Numbers start at 1.
+func (c *seqCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	// Copy fields rather than append in place: the caller owns the slice
+	withSeq := make([]zapcore.Field, len(fields), len(fields)+1)
+	copy(withSeq, fields)
+	return c.Core.Write(ent, append(withSeq, zap.Uint64(c.key, c.next.Add(1))))
+}
@@ -0,0 +1,74 @@
+package goslogx
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSequence(t *testing.T) {
+	t.Run("StrictlyIncreasing", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := setupLog(WithOutput(buf), WithSequence(true), WithAsync(1024, time.Hour))
+		child := logger.With(map[string]any{"request_id": "req-1"})
+		for i := 0; i < 5; i++ {
+			logger.Info("trace-1", "http", MESSAGE_TYPE_EVENT, "tick", nil)
+			child.Warning("trace-1", "http", "tock", nil)
+		}
+		logger.Error("trace-1", "http", errors.New("boom"))
+		_ = logger.Close()
+
+		entries := decodeEntries(t, buf.Bytes())
+		if len(entries) != 11 {
+			t.Fatalf("Expected 11 entries, got %d", len(entries))
+		}
+		for i, e := range entries {
+			if e[KeySeq] != float64(i+1) {
+				t.Errorf("Expected entry %d to have seq %d, got %v", i, i+1, e[KeySeq])
+			}
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		errBuf := &bytes.Buffer{}
+		// Locked, since the goroutines write concurrently
+		logger := setupLog(WithOutput(zapcore.Lock(zapcore.AddSync(buf))),
+			WithErrorOutput(zapcore.Lock(zapcore.AddSync(errBuf))), WithSequence(true))
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				logger.Info("trace-1", "worker", MESSAGE_TYPE_EVENT, "job done", nil)
+				logger.Warning("trace-1", "worker", "job slow", nil)
+			}()
+		}
+		wg.Wait()
+
+		var seqs []int
+		for _, e := range append(decodeEntries(t, buf.Bytes()), decodeEntries(t, errBuf.Bytes())...) {
+			seq, _ := e[KeySeq].(float64)
+			seqs = append(seqs, int(seq))
+		}
+		sort.Ints(seqs)
+		for i, seq := range seqs {
+			if seq != i+1 {
+				t.Fatalf("Expected seq values 1 to 40 shared across outputs, got %v", seqs)
+			}
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		setupLog(WithOutput(buf)).Info("trace-1", "http", MESSAGE_TYPE_EVENT, "tick", nil)
+		if seq, ok := decodeEntries(t, buf.Bytes())[0][KeySeq]; ok {
+			t.Errorf("Expected no seq field, got %v", seq)
+		}
+	})
+}
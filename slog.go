@@ -0,0 +1,267 @@
+package goslogx
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// slogHandler adapts goslogx to the log/slog Handler interface.
+// Attributes are redacted and masked by key like the entries of a data map,
+// and struct values go through the same masking path as the data argument.
+type slogHandler struct {
+	core    zapcore.Core   // Core carrying top-level attrs added via WithAttrs
+	keys    *FieldKeys     // Well-known field keys of the underlying logger
+	masking *MaskingConfig // Masking configuration of the underlying logger
+	source  bool           // Add source to Info and Debug records, see WithSource
+	// Severity overrides of the underlying logger, see WithSeverityMapping
+	severities map[zapcore.Level]string
+	// Trace correlation of the underlying logger, see WithTraceExtractor
+	traces TraceExtractor
+	groups []slogGroup // Groups opened via WithGroup, outermost first
+}
+
+// slogGroup is a group opened via WithGroup with the attrs added inside it.
+type slogGroup struct {
+	name  string
+	attrs []slog.Attr
+}
+
+// NewSlogHandler creates a slog.Handler backed by a new goslogx logger.
+// It applies the same options, masking rules, and field conventions as New,
+// but does not touch the global logger.
+//
+// Example:
+//
+//	slog.SetDefault(slog.New(goslogx.NewSlogHandler(
+//	    goslogx.WithServiceName("my-service"),
+//	)))
+//	slog.Info("user login", "username", "johndoe123", "password", "secret")
+//	// username and password are masked in the output
+func NewSlogHandler(opts ...Option) slog.Handler {
+	l := setupLog(opts...)
+	return &slogHandler{
+		core:       l.logger.Core(),
+		keys:       &l.config.FieldKeys,
+		masking:    &l.config.Masking,
+		source:     l.config.Source,
+		severities: l.config.SeverityMapping,
+		traces:     l.config.TraceExtractor,
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.Enabled(slogLevel(level))
+}
+
+// Handle implements slog.Handler.
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	lvl := slogLevel(r.Level)
+	ent := zapcore.Entry{
+		Level:   lvl,
+		Time:    r.Time,
+		Message: r.Message,
+	}
+	// Info and Debug entries carry no source unless enabled, matching Logger
+	if r.PC != 0 && (h.source || lvl >= zapcore.WarnLevel) {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ent.Caller = zapcore.NewEntryCaller(r.PC, frame.File, frame.Line, true)
+		ent.Caller.Function = frame.Function
+	}
+	ce := h.core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	fields := make([]zap.Field, 0, len(attrs)+3)
+	if h.traces != nil && ctx != nil {
+		if traceID, spanID, ok := h.traces(ctx); ok {
+			fields = append(fields, zap.String(h.keys.TraceID, traceID), zap.String(h.keys.SpanID, spanID))
+		}
+	}
+	fields = appendMapFields(fields, contextFields(ctx), h.masking)
+	fields = append(fields, zap.String(h.keys.Severity, severityFor(lvl, h.severities)))
+	if len(h.groups) > 0 {
+		group := slogGroupObject{groups: h.groups, attrs: attrs, cfg: h.masking}
+		if !group.empty() {
+			fields = append(fields, zap.Object(h.groups[0].name, group))
+		}
+	} else {
+		fields = appendSlogFields(fields, attrs, h.masking)
+	}
+	ce.Write(fields...)
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := *h
+	if len(h.groups) == 0 {
+		clone.core = h.core.With(appendSlogFields(nil, attrs, h.masking))
+		return &clone
+	}
+	// Attrs belong to the innermost open group
+	clone.groups = append([]slogGroup(nil), h.groups...)
+	last := &clone.groups[len(clone.groups)-1]
+	last.attrs = append(append([]slog.Attr(nil), last.attrs...), attrs...)
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(append([]slogGroup(nil), h.groups...), slogGroup{name: name})
+	return &clone
+}
+
+// slogLevel maps a slog level to the zap level.
+func slogLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// slogGroupObject marshals nested WithGroup frames, placing the record's
+// attrs inside the innermost group.
+type slogGroupObject struct {
+	groups []slogGroup
+	attrs  []slog.Attr
+	cfg    *MaskingConfig
+}
+
+// empty reports whether the group and all of its nested groups have no attrs.
+// Per slog semantics, empty groups are omitted.
+func (g slogGroupObject) empty() bool {
+	if len(g.attrs) > 0 {
+		return false
+	}
+	for _, frame := range g.groups {
+		if len(frame.attrs) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (g slogGroupObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range appendSlogFields(nil, g.groups[0].attrs, g.cfg) {
+		f.AddTo(enc)
+	}
+	if len(g.groups) > 1 {
+		inner := slogGroupObject{groups: g.groups[1:], attrs: g.attrs, cfg: g.cfg}
+		if !inner.empty() {
+			return enc.AddObject(g.groups[1].name, inner)
+		}
+		return nil
+	}
+	for _, f := range appendSlogFields(nil, g.attrs, g.cfg) {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// slogAttrs marshals the attrs of a slog group value as an object.
+type slogAttrs struct {
+	attrs []slog.Attr
+	cfg   *MaskingConfig
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (a slogAttrs) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range appendSlogFields(nil, a.attrs, a.cfg) {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// appendSlogFields converts slog attrs to zap fields with masking by key,
+// like the entries of a map passed as data: attrs and groups named in
+// RedactFields are dropped, and the FieldMasker sees every other non-group
+// attr first. Empty attrs are dropped and groups with an empty key are inlined.
+func appendSlogFields(fields []zap.Field, attrs []slog.Attr, cfg *MaskingConfig) []zap.Field {
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Equal(slog.Attr{}) || a.Key != "" && cfg.redacts(a.Key) {
+			continue
+		}
+		if a.Value.Kind() == slog.KindGroup {
+			group := a.Value.Group()
+			if len(group) == 0 {
+				continue
+			}
+			if a.Key == "" {
+				fields = appendSlogFields(fields, group, cfg)
+				continue
+			}
+			fields = append(fields, zap.Object(a.Key, slogAttrs{attrs: group, cfg: cfg}))
+			continue
+		}
+		if cfg.FieldMasker != nil {
+			if masked, ok := cfg.FieldMasker(a.Key, a.Value.Any()); ok {
+				fields = append(fields, zap.Any(a.Key, masked))
+				continue
+			}
+		}
+		fields = append(fields, slogField(a, cfg))
+	}
+	return fields
+}
+
+// slogField converts a single non-group attr to a zap field.
+// Keys matching a full mask pattern are replaced with "****" regardless of kind;
+// keys matching a partial pattern mask string values.
+func slogField(a slog.Attr, cfg *MaskingConfig) zap.Field {
+	mt := cfg.fieldMask(a.Key)
+	// In allowlist mode only strings are masked, see MaskingAllowlist
+	if mt == maskFull && (cfg.Mode != MaskingAllowlist || a.Value.Kind() == slog.KindString) {
+		return zap.String(a.Key, "****")
+	}
+	v := a.Value
+	switch v.Kind() {
+	case slog.KindString:
+		if mt == maskPartial {
+			return zap.String(a.Key, maskMiddle(v.String()))
+		}
+		return zap.String(a.Key, v.String())
+	case slog.KindInt64:
+		return zap.Int64(a.Key, v.Int64())
+	case slog.KindUint64:
+		return zap.Uint64(a.Key, v.Uint64())
+	case slog.KindFloat64:
+		return zap.Float64(a.Key, v.Float64())
+	case slog.KindBool:
+		return zap.Bool(a.Key, v.Bool())
+	case slog.KindDuration:
+		return zap.Duration(a.Key, v.Duration())
+	case slog.KindTime:
+		return zap.Time(a.Key, v.Time())
+	}
+	if err, ok := v.Any().(error); ok {
+		return zap.NamedError(a.Key, err)
+	}
+	return maskedField(a.Key, v.Any(), cfg)
+}
@@ -0,0 +1,235 @@
+package goslogx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+// decodeEntry unmarshals a single JSON log line.
+func decodeEntry(t *testing.T, b []byte) map[string]any {
+	t.Helper()
+	var entry map[string]any
+	if err := json.Unmarshal(b, &entry); err != nil {
+		t.Fatalf("Failed to unmarshal log entry: %v (%s)", err, string(b))
+	}
+	return entry
+}
+
+func TestSlogHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(NewSlogHandler(WithOutput(buf), WithServiceName("slog-service"), WithDebug(true)))
+
+	t.Run("Masking", func(t *testing.T) {
+		buf.Reset()
+		logger.Info("user login", "username", "johndoe123", "password", "supersecret", "status", "ok")
+		entry := decodeEntry(t, buf.Bytes())
+		if entry["msg"] != "user login" || entry["severity"] != "INFO" {
+			t.Errorf("Unexpected msg/severity: %v", entry)
+		}
+		if entry["password"] != "****" {
+			t.Errorf("Expected password fully masked, got %v", entry["password"])
+		}
+		if entry["username"] != "jo****23" {
+			t.Errorf("Expected username partially masked, got %v", entry["username"])
+		}
+		if entry["status"] != "ok" {
+			t.Errorf("Expected status unmasked, got %v", entry["status"])
+		}
+	})
+
+	t.Run("StructValue", func(t *testing.T) {
+		buf.Reset()
+		type Creds struct {
+			Secret string `json:"secret" log:"masked:full"`
+		}
+		logger.Warn("creds", "creds", Creds{Secret: "topsecret"})
+		entry := decodeEntry(t, buf.Bytes())
+		creds, _ := entry["creds"].(map[string]any)
+		if creds["secret"] != "****" {
+			t.Errorf("Expected struct field masked, got %v", entry["creds"])
+		}
+		if entry["severity"] != "WARNING" || entry["source"] == nil {
+			t.Errorf("Expected WARNING with source, got %v", entry)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		buf.Reset()
+		logger.Error("failed", "err", errors.New("boom"))
+		entry := decodeEntry(t, buf.Bytes())
+		if entry["err"] != "boom" || entry["severity"] != "ERROR" {
+			t.Errorf("Unexpected error entry: %v", entry)
+		}
+	})
+
+	t.Run("WithAttrs", func(t *testing.T) {
+		buf.Reset()
+		logger.With("token", "abc123", "tenant", "acme").Debug("debug msg")
+		entry := decodeEntry(t, buf.Bytes())
+		if entry["token"] != "****" || entry["tenant"] != "acme" || entry["severity"] != "DEBUG" {
+			t.Errorf("Unexpected attrs: %v", entry)
+		}
+	})
+
+	t.Run("WithGroup", func(t *testing.T) {
+		buf.Reset()
+		logger.With("top", 1).
+			WithGroup("request").With("method", "POST").
+			WithGroup("auth").Info("nested", "password", "secret", "user_id", "usr-001")
+		entry := decodeEntry(t, buf.Bytes())
+		if entry["top"] != float64(1) || entry["severity"] != "INFO" {
+			t.Errorf("Expected top-level attrs outside groups, got %v", entry)
+		}
+		request, ok := entry["request"].(map[string]any)
+		if !ok || request["method"] != "POST" {
+			t.Fatalf("Expected request group with method, got %v", entry["request"])
+		}
+		auth, ok := request["auth"].(map[string]any)
+		if !ok || auth["password"] != "****" || auth["user_id"] != "us****01" {
+			t.Errorf("Expected masked attrs nested in request.auth, got %v", request["auth"])
+		}
+	})
+
+	t.Run("EmptyGroupOmitted", func(t *testing.T) {
+		buf.Reset()
+		logger.WithGroup("empty").Info("no attrs")
+		entry := decodeEntry(t, buf.Bytes())
+		if _, ok := entry["empty"]; ok {
+			t.Errorf("Expected empty group to be omitted, got %v", entry)
+		}
+	})
+
+	t.Run("GroupAttr", func(t *testing.T) {
+		buf.Reset()
+		logger.Info("group attr", slog.Group("db", "secret_key", "k", "table", "users"), slog.Group("", "inline", true))
+		entry := decodeEntry(t, buf.Bytes())
+		db, _ := entry["db"].(map[string]any)
+		if db["secret_key"] != "****" || db["table"] != "users" || entry["inline"] != true {
+			t.Errorf("Unexpected group attrs: %v", entry)
+		}
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		h := NewSlogHandler(WithOutput(buf))
+		if h.Enabled(context.Background(), slog.LevelDebug) {
+			t.Error("Expected Debug to be disabled at Info level")
+		}
+		if !h.Enabled(context.Background(), slog.LevelError) {
+			t.Error("Expected Error to be enabled")
+		}
+	})
+}
+
+func TestSlogHandlerRedaction(t *testing.T) {
+	buf := &bytes.Buffer{}
+	masker := func(name string, value any) (any, bool) {
+		if name == "account" {
+			return "acct-****", true
+		}
+		return nil, false
+	}
+	logger := slog.New(NewSlogHandler(WithOutput(buf), WithRedactFields([]string{"ssn", "internal"}), WithFieldMasker(masker)))
+
+	t.Run("TopLevel", func(t *testing.T) {
+		buf.Reset()
+		logger.With("ssn", "123-45-6789").Info("user updated",
+			"SSN", "987-65-4321", "account", "AC-1234567", "status", "ok",
+			slog.Group("internal", "note", "vip"))
+		entry := decodeEntry(t, buf.Bytes())
+		for _, key := range []string{"ssn", "SSN", "internal"} {
+			if v, ok := entry[key]; ok {
+				t.Errorf("Expected %s redacted, got %v", key, v)
+			}
+		}
+		if entry["account"] != "acct-****" || entry["status"] != "ok" {
+			t.Errorf("Expected account from the FieldMasker and status as is, got %v / %v", entry["account"], entry["status"])
+		}
+	})
+
+	t.Run("Groups", func(t *testing.T) {
+		buf.Reset()
+		logger.WithGroup("req").With("ssn", "123-45-6789").Info("user updated",
+			slog.Group("user", "ssn", "987-65-4321", "account", "AC-1234567", "name", "jane"))
+		req, _ := decodeEntry(t, buf.Bytes())["req"].(map[string]any)
+		if v, ok := req["ssn"]; ok {
+			t.Errorf("Expected req.ssn redacted, got %v", v)
+		}
+		user, _ := req["user"].(map[string]any)
+		if v, ok := user["ssn"]; ok {
+			t.Errorf("Expected req.user.ssn redacted, got %v", v)
+		}
+		if user["account"] != "acct-****" || user["name"] != "jane" {
+			t.Errorf("Expected account from the FieldMasker and name as is, got %v", user)
+		}
+	})
+}
+
+// TestSlogFieldParity checks that the slog handler, built on the same zap core
+// as Logger, produces entries with the same field shapes.
+func TestSlogFieldParity(t *testing.T) {
+	zapBuf, slogBuf := &bytes.Buffer{}, &bytes.Buffer{}
+	opts := func(buf *bytes.Buffer) []Option {
+		return []Option{WithOutput(buf), WithServiceName("parity"), WithDebug(true), WithSource(true)}
+	}
+	logger := setupLog(opts(zapBuf)...)
+	handler := slog.New(NewSlogHandler(opts(slogBuf)...))
+
+	tests := []struct {
+		name    string
+		zapLog  func()
+		slogLog func()
+	}{
+		{
+			"Info",
+			func() { logger.Info("trace-001", "auth", MESSAGE_TYPE_EVENT, "user login", nil) },
+			func() {
+				handler.Info("user login", "trace_id", "trace-001", "module", "auth", "msg_type", MESSAGE_TYPE_EVENT)
+			},
+		},
+		{
+			"Debug",
+			func() { logger.Debug("trace-001", "auth", MESSAGE_TYPE_EVENT, "cache miss", nil) },
+			func() {
+				handler.Debug("cache miss", "trace_id", "trace-001", "module", "auth", "msg_type", MESSAGE_TYPE_EVENT)
+			},
+		},
+		{
+			"Warning",
+			func() { logger.Warning("trace-001", "auth", "slow login", nil) },
+			func() { handler.Warn("slow login", "trace_id", "trace-001", "module", "auth") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zapBuf.Reset()
+			slogBuf.Reset()
+			tt.zapLog()
+			tt.slogLog()
+			want, got := decodeEntry(t, zapBuf.Bytes()), decodeEntry(t, slogBuf.Bytes())
+
+			for key, wantValue := range want {
+				gotValue, ok := got[key]
+				if !ok {
+					t.Errorf("slog entry is missing %q: %v", key, got)
+					continue
+				}
+				switch key {
+				case "time", "source", "function":
+					// Differ by call site and clock
+				default:
+					if gotValue != wantValue {
+						t.Errorf("%q: expected %v, got %v", key, wantValue, gotValue)
+					}
+				}
+			}
+			if len(got) != len(want) {
+				t.Errorf("Expected %d fields, got %d: %v vs %v", len(want), len(got), want, got)
+			}
+		})
+	}
+}
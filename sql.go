@@ -0,0 +1,286 @@
+package goslogx
+
+import (
+	"reflect"
+	"strings"
+)
+
+// sqlDialect selects how statement literals are recognized, see sqlDialectOf.
+type sqlDialect uint8
+
+const (
+	// sqlStandard has 'strings' with doubled quotes, "identifiers", and
+	// ? or :name placeholders.
+	sqlStandard sqlDialect = iota
+	// sqlPostgres adds E'strings' with backslash escapes, $tag$strings$tag$
+	// dollar quoting, and $1 placeholders.
+	sqlPostgres
+	// sqlMySQL adds backslash escapes, "strings", and `identifiers`.
+	sqlMySQL
+)
+
+// sqlDialectOf returns the dialect of the database/sql driver name, such as
+// DBData.Driver, defaulting to sqlStandard for unknown drivers.
+func sqlDialectOf(driver string) sqlDialect {
+	switch strings.ToLower(driver) {
+	case "postgres", "postgresql", "pgx", "pq", "cockroach", "cockroachdb":
+		return sqlPostgres
+	case "mysql", "mariadb":
+		return sqlMySQL
+	}
+	return sqlStandard
+}
+
+// statementDriver returns the Driver field of the struct rv holding a
+// log:"masked:sql" statement, such as DBData, or "" if it has none.
+func statementDriver(rv reflect.Value) string {
+	if f := rv.FieldByName("Driver"); f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
+
+// scrubSQLLiterals is maskSQLLiterals for a statement of unknown dialect.
+//
+// Example:
+//
+//	scrubSQLLiterals("SELECT * FROM users WHERE email = 'a@b.co' AND status = 'active'")
+//	// SELECT * FROM users WHERE email = '****' AND status = '****'
+func scrubSQLLiterals(stmt string) string {
+	return maskSQLLiterals("", stmt)
+}
+
+// maskSQLLiterals replaces the contents of string literals that follow an =
+// comparison (including !=, <= and >=) in stmt with ****, recognizing the
+// literals, quoted identifiers, and placeholders of driver's dialect, see
+// sqlDialectOf. Doubled quotes inside a literal are treated as escapes, as are
+// backslashes in MySQL and in Postgres E'...' strings. Other literals, such as
+// those in an IN list or a LIKE pattern, are left as is.
+//
+// Example:
+//
+//	maskSQLLiterals("postgres", "UPDATE users SET bio = $$it's me$$ WHERE id = $1")
+//	// UPDATE users SET bio = $$****$$ WHERE id = $1
+//	maskSQLLiterals("mysql", `SELECT * FROM users WHERE name = 'O\'Brien'`)
+//	// SELECT * FROM users WHERE name = '****'
+func maskSQLLiterals(driver, stmt string) string {
+	if !strings.ContainsAny(stmt, "'\"$") {
+		return stmt
+	}
+	d := sqlDialectOf(driver)
+	var b strings.Builder
+	b.Grow(len(stmt))
+	afterEquals := false
+	for i := 0; i < len(stmt); {
+		c := stmt[i]
+		// Literal: open and close are the quoting around its contents
+		var open, close string
+		end := -1
+		switch {
+		case c == '\'' || c == '"' && d == sqlMySQL:
+			end = sqlLiteralEnd(stmt, i, d == sqlMySQL)
+			open, close = stmt[i:i+1], stmt[i:i+1]
+		case c == '"' || c == '`':
+			// Quoted identifier, kept as is
+			n := strings.IndexByte(stmt[i+1:], c)
+			if n < 0 {
+				n = len(stmt) - i - 2
+			}
+			b.WriteString(stmt[i : i+n+2])
+			i += n + 2
+			afterEquals = false
+			continue
+		case c == '$' && d == sqlPostgres && (i == 0 || !isSQLIdentByte(stmt[i-1])):
+			if tag, ok := dollarTag(stmt, i); ok {
+				end = len(stmt)
+				if n := strings.Index(stmt[i+len(tag):], tag); n >= 0 {
+					end = i + len(tag) + n + len(tag)
+				}
+				open, close = tag, tag
+			}
+		case isSQLIdentByte(c) && (i == 0 || !isSQLIdentByte(stmt[i-1])):
+			// Prefixed string, such as E'...', N'...' or _utf8mb4'...'
+			j := i
+			for j < len(stmt) && isSQLIdentByte(stmt[j]) {
+				j++
+			}
+			if j < len(stmt) && stmt[j] == '\'' {
+				escapes := d == sqlMySQL || d == sqlPostgres && j == i+1 && (c == 'E' || c == 'e')
+				end = sqlLiteralEnd(stmt, j, escapes)
+				open, close = stmt[i:j+1], "'"
+			}
+		}
+		if end < 0 {
+			b.WriteByte(c)
+			switch c {
+			case '=':
+				afterEquals = true
+			case ' ', '\t', '\n', '\r':
+			default:
+				afterEquals = false
+			}
+			i++
+			continue
+		}
+		if afterEquals {
+			b.WriteString(open + "****" + close)
+		} else {
+			b.WriteString(stmt[i:end])
+		}
+		i = end
+		afterEquals = false
+	}
+	return b.String()
+}
+
+// dollarTag returns the $tag$ or $$ opening a Postgres dollar-quoted string at
+// stmt[start], reporting false for other uses of $, such as $1 placeholders.
+func dollarTag(stmt string, start int) (string, bool) {
+	i := start + 1
+	if i < len(stmt) && stmt[i] >= '0' && stmt[i] <= '9' {
+		return "", false
+	}
+	for i < len(stmt) && isSQLIdentByte(stmt[i]) {
+		i++
+	}
+	if i == len(stmt) || stmt[i] != '$' {
+		return "", false
+	}
+	return stmt[start : i+1], true
+}
+
+// sqlLiteralEnd returns the index just past the literal quoted with the quote
+// at stmt[start], or len(stmt) if it is unterminated. With escapes, a
+// backslash escapes the next byte.
+func sqlLiteralEnd(stmt string, start int, escapes bool) int {
+	q := stmt[start]
+	for i := start + 1; i < len(stmt); i++ {
+		if escapes && stmt[i] == '\\' {
+			i++
+			continue
+		}
+		if stmt[i] != q {
+			continue
+		}
+		if i+1 < len(stmt) && stmt[i+1] == q {
+			i++ // Escaped quote
+			continue
+		}
+		return i + 1
+	}
+	return len(stmt)
+}
+
+// normalizeSQL returns stmt with its literals replaced by ? placeholders, so
+// that statements differing only in their values compare equal. String and
+// numeric literals become ?, lists of them after IN collapse to (?), and runs
+// of whitespace become a single space. Quoted identifiers and bind
+// placeholders such as $1 or :name are kept.
+//
+// Example:
+//
+//	normalizeSQL("SELECT * FROM users WHERE id IN (1, 2, 3) AND name = 'x'")
+//	// SELECT * FROM users WHERE id IN (?) AND name = ?
+func normalizeSQL(stmt string) string {
+	b := make([]byte, 0, len(stmt))
+	space := false
+	for i := 0; i < len(stmt); {
+		c := stmt[i]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			space = true
+			i++
+			continue
+		}
+		if space && len(b) > 0 {
+			b = append(b, ' ')
+		}
+		space = false
+		switch {
+		case c == '\'':
+			b = append(b, '?')
+			i = sqlLiteralEnd(stmt, i, false)
+		case c == '"' || c == '`':
+			end := strings.IndexByte(stmt[i+1:], c)
+			if end < 0 {
+				end = len(stmt) - i - 2
+			}
+			b = append(b, stmt[i:i+end+2]...)
+			i += end + 2
+		case c >= '0' && c <= '9':
+			b = append(b, '?')
+			for i < len(stmt) && (isSQLIdentByte(stmt[i]) || stmt[i] == '.') {
+				i++
+			}
+		case c == '(' && endsWithIN(b):
+			if end, ok := literalListEnd(stmt, i); ok {
+				b = append(b, "(?)"...)
+				i = end
+				continue
+			}
+			b = append(b, c)
+			i++
+		case isSQLIdentByte(c) || c == '$' || c == ':':
+			start := i
+			for i++; i < len(stmt) && (isSQLIdentByte(stmt[i]) || stmt[i] == '$'); i++ {
+			}
+			b = append(b, stmt[start:i]...)
+		default:
+			b = append(b, c)
+			i++
+		}
+	}
+	return string(b)
+}
+
+// isSQLIdentByte reports whether c can appear in an unquoted identifier.
+func isSQLIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// endsWithIN reports whether b ends with the IN keyword, optionally followed by a space.
+func endsWithIN(b []byte) bool {
+	s := strings.TrimSuffix(string(b), " ")
+	if len(s) < 2 || !strings.EqualFold(s[len(s)-2:], "in") {
+		return false
+	}
+	return len(s) == 2 || !isSQLIdentByte(s[len(s)-3])
+}
+
+// literalListEnd reports whether stmt[start] opens a parenthesized list of
+// literals and placeholders, returning the index just past its closing ')'.
+func literalListEnd(stmt string, start int) (int, bool) {
+	i := start + 1
+	for {
+		for i < len(stmt) && (stmt[i] == ' ' || stmt[i] == '\t' || stmt[i] == '\n' || stmt[i] == '\r') {
+			i++
+		}
+		if i == len(stmt) {
+			return 0, false
+		}
+		switch c := stmt[i]; {
+		case c == '\'':
+			i = sqlLiteralEnd(stmt, i, false)
+		case c == '?':
+			i++
+		case c >= '0' && c <= '9' || c == '-' || c == '$' || c == ':':
+			for i++; i < len(stmt) && (isSQLIdentByte(stmt[i]) || stmt[i] == '.'); i++ {
+			}
+		default:
+			return 0, false
+		}
+		for i < len(stmt) && (stmt[i] == ' ' || stmt[i] == '\t' || stmt[i] == '\n' || stmt[i] == '\r') {
+			i++
+		}
+		if i == len(stmt) {
+			return 0, false
+		}
+		switch stmt[i] {
+		case ',':
+			i++
+		case ')':
+			return i + 1, true
+		default:
+			return 0, false
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package goslogx
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestScrubSQLLiterals(t *testing.T) {
+	tests := []struct {
+		name string
+		stmt string
+		want string
+	}{
+		{"NoLiterals", "SELECT * FROM users WHERE id = $1", "SELECT * FROM users WHERE id = $1"},
+		{"Password", "SELECT id FROM users WHERE username = 'admin' AND password = 'hunter2'",
+			"SELECT id FROM users WHERE username = '****' AND password = '****'"},
+		{"NoSpace", "UPDATE users SET token='abc' WHERE id=1", "UPDATE users SET token='****' WHERE id=1"},
+		{"Comparisons", "SELECT 1 WHERE a != 'x' OR b >= 'y'", "SELECT 1 WHERE a != '****' OR b >= '****'"},
+		{"EscapedQuote", "SELECT 1 WHERE name = 'O''Brien' AND x = 1", "SELECT 1 WHERE name = '****' AND x = 1"},
+		{"OtherLiterals", "SELECT 'a=b' FROM t WHERE c IN ('x', 'y') AND d LIKE 'z%'",
+			"SELECT 'a=b' FROM t WHERE c IN ('x', 'y') AND d LIKE 'z%'"},
+		{"Unterminated", "SELECT 1 WHERE a = 'oops", "SELECT 1 WHERE a = '****'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scrubSQLLiterals(tt.stmt); got != tt.want {
+				t.Errorf("scrubSQLLiterals(%q) = %q, want %q", tt.stmt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskSQLLiterals(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver string
+		stmt   string
+		want   string
+	}{
+		{"PostgresPlaceholders", "postgres", "SELECT * FROM users WHERE id = $1 AND email = 'a@b.co'",
+			"SELECT * FROM users WHERE id = $1 AND email = '****'"},
+		{"PostgresDollarQuoted", "postgres", "UPDATE users SET bio = $$it's me$$, token = $t$x$$y$t$ WHERE id = $2",
+			"UPDATE users SET bio = $$****$$, token = $t$****$t$ WHERE id = $2"},
+		{"PostgresDollarQuotedKept", "pgx", "SELECT $$it's me$$ FROM t WHERE a = 'x'",
+			"SELECT $$it's me$$ FROM t WHERE a = '****'"},
+		{"PostgresEscapeString", "postgres", `SELECT 1 WHERE a = E'it\'s' AND b = 'c\' AND d = 'e'`,
+			`SELECT 1 WHERE a = E'****' AND b = '****' AND d = '****'`},
+		{"PostgresCastAndNamed", "postgres", "SELECT 1 WHERE a::text = 'x' AND b = :name",
+			"SELECT 1 WHERE a::text = '****' AND b = :name"},
+		{"PostgresIdentifier", "postgres", `SELECT "it's" FROM t WHERE "na=me" = 'x'`,
+			`SELECT "it's" FROM t WHERE "na=me" = '****'`},
+		{"MySQLBackticks", "mysql", "SELECT * FROM `it's` WHERE `key` = 'secret' AND id = ?",
+			"SELECT * FROM `it's` WHERE `key` = '****' AND id = ?"},
+		{"MySQLBackslash", "mysql", `SELECT 1 WHERE name = 'O\'Brien' AND x = 1`,
+			`SELECT 1 WHERE name = '****' AND x = 1`},
+		{"MySQLDoubleQuoted", "mysql", `UPDATE users SET password = "hunter2" WHERE id = ?`,
+			`UPDATE users SET password = "****" WHERE id = ?`},
+		{"MySQLCharset", "mysql", "SELECT 1 WHERE name = _utf8mb4'jane'", "SELECT 1 WHERE name = _utf8mb4'****'"},
+		{"StandardDollar", "", "SELECT $$x$$ WHERE a = 'y'", "SELECT $$x$$ WHERE a = '****'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maskSQLLiterals(tt.driver, tt.stmt); got != tt.want {
+				t.Errorf("maskSQLLiterals(%q, %q) = %q, want %q", tt.driver, tt.stmt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeSQL(t *testing.T) {
+	tests := []struct {
+		name string
+		stmt string
+		want string
+	}{
+		{"Number", "SELECT * FROM users WHERE id = 1", "SELECT * FROM users WHERE id = ?"},
+		{"String", "SELECT * FROM users WHERE email = 'a@b.co' AND name = 'O''Brien'",
+			"SELECT * FROM users WHERE email = ? AND name = ?"},
+		{"Decimal", "UPDATE accounts SET balance = balance - 10.50 WHERE id = 7",
+			"UPDATE accounts SET balance = balance - ? WHERE id = ?"},
+		{"INList", "SELECT * FROM t WHERE id IN (1, 2, 3) AND s in ('a','b')",
+			"SELECT * FROM t WHERE id IN (?) AND s in (?)"},
+		{"INPlaceholders", "DELETE FROM t WHERE id IN ($1, $2, $3)", "DELETE FROM t WHERE id IN (?)"},
+		{"INSubquery", "SELECT * FROM t WHERE id IN (SELECT id FROM u WHERE age > 18)",
+			"SELECT * FROM t WHERE id IN (SELECT id FROM u WHERE age > ?)"},
+		{"Insert", "INSERT INTO t (a, b) VALUES (1, 'x')", "INSERT INTO t (a, b) VALUES (?, ?)"},
+		{"IdentifiersKept", "SELECT t1.col2, \"9lives\" FROM t1 WHERE x = $1 AND y = :name AND z::int > 0",
+			"SELECT t1.col2, \"9lives\" FROM t1 WHERE x = $1 AND y = :name AND z::int > ?"},
+		{"Whitespace", "SELECT *\n  FROM users\n\tWHERE id = 42  ", "SELECT * FROM users WHERE id = ?"},
+		{"NotIN", "SELECT main(1, 2) FROM t", "SELECT main(?, ?) FROM t"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeSQL(tt.stmt); got != tt.want {
+				t.Errorf("normalizeSQL(%q) = %q, want %q", tt.stmt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskSensitiveValue(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"john.doe@example.com", "jo****@example.com"},
+		{"4111 1111 1111 1111", "****1111"},
+		{"4111-1111-1111-1111", "****1111"},
+		{"4111111111111112", "4111111111111112"}, // Fails the Luhn check
+		{"+6281234567890", "+62********90"},
+		{"081234567890", "********90"},
+		{"1234567890", "1234567890"}, // No phone prefix
+		{"active", "active"},
+		{"user@localhost", "user@localhost"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := maskSensitiveValue(tt.input); got != tt.want {
+			t.Errorf("maskSensitiveValue(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestDBDataMasking(t *testing.T) {
+	data := DBData{
+		Driver:    "postgres",
+		Statement: "SELECT id FROM users WHERE email = $1 AND password = 'hunter2'",
+		Args:      []any{"jane.doe@example.com", 42, "active", []byte("raw"), nil},
+	}
+	logged := func(t *testing.T, opts ...Option) map[string]any {
+		t.Helper()
+		buf := &bytes.Buffer{}
+		setupLog(append([]Option{WithOutput(buf)}, opts...)...).
+			Info("trace-1", "db", MESSSAGE_TYPE_IN, "query executed", data)
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to decode entry: %v", err)
+		}
+		return entry["data"].(map[string]any)
+	}
+
+	got := logged(t, WithSQLLiteralMasking(true))
+	if want := "SELECT id FROM users WHERE email = $1 AND password = '****'"; got["statement"] != want {
+		t.Errorf("Expected statement %q, got %v", want, got["statement"])
+	}
+	wantArgs := []any{"ja****@example.com", float64(42), "active", "cmF3", nil}
+	if !reflect.DeepEqual(got["args"], wantArgs) {
+		t.Errorf("Expected args %v, got %v", wantArgs, got["args"])
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		got := logged(t)
+		if got["statement"] != data.Statement {
+			t.Errorf("Expected statement to be left as is by default, got %v", got["statement"])
+		}
+		if args := got["args"].([]any); args[0] != "ja****@example.com" {
+			t.Errorf("Expected args to be masked by default, got %v", args)
+		}
+	})
+
+	t.Run("Normalize", func(t *testing.T) {
+		got := logged(t, WithSQLLiteralMasking(true), WithSQLNormalization(true))
+		if want := "SELECT id FROM users WHERE email = $1 AND password = ?"; got["statement_normalized"] != want {
+			t.Errorf("Expected statement_normalized %q, got %v", want, got["statement_normalized"])
+		}
+		if _, ok := logged(t)["statement_normalized"]; ok {
+			t.Error("Expected no statement_normalized by default")
+		}
+	})
+
+	t.Run("Dialect", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		setupLog(WithOutput(buf), WithSQLLiteralMasking(true)).Info("trace-1", "db", MESSSAGE_TYPE_IN, "query executed", DBData{
+			Driver:    "mysql",
+			Statement: `UPDATE users SET password = "hunter2", note = 'it\'s' WHERE id = ?`,
+		})
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to decode entry: %v", err)
+		}
+		want := `UPDATE users SET password = "****", note = '****' WHERE id = ?`
+		if got := entry["data"].(map[string]any)["statement"]; got != want {
+			t.Errorf("Expected statement %q, got %v", want, got)
+		}
+	})
+
+	t.Run("NamedArgs", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		setupLog(WithOutput(buf)).Info("trace-1", "db", MESSSAGE_TYPE_IN, "query executed", DBData{
+			Driver:    "postgres",
+			Statement: "UPDATE users SET password = :password WHERE id = :id",
+			NamedArgs: map[string]any{"password": "hunter2", "id": 42},
+		})
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to decode entry: %v", err)
+		}
+		want := map[string]any{"password": "****", "id": float64(42)}
+		if got := entry["data"].(map[string]any)["named_args"]; !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected named_args %v, got %v", want, got)
+		}
+	})
+
+	t.Run("MaskingDisabled", func(t *testing.T) {
+		got := logged(t, WithMasking(false))
+		if args := got["args"].([]any); args[0] != "jane.doe@example.com" {
+			t.Errorf("Expected args as is with masking disabled, got %v", args)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package goslogx
+
+import (
+	"bytes"
+	"log"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// StdlogModule is the module of entries written through StdlogAt.
+const StdlogModule = "stdlog"
+
+// StdlogAt returns a *log.Logger, for third-party libraries that only accept
+// the standard library's logger, whose output becomes entries of l at level,
+// one per Print call, with module StdlogModule. Levels above ErrorLevel log
+// at ErrorLevel: log.Fatal and log.Panic exit or panic on their own after
+// writing. Messages are scrubbed like any other when WithMessageScanning is
+// set.
+//
+// Example:
+//
+//	srv := &http.Server{
+//	    Addr:     ":8080",
+//	    ErrorLog: logger.StdlogAt(zapcore.ErrorLevel),
+//	}
+func (l *Logger) StdlogAt(level zapcore.Level) *log.Logger {
+	if level > zapcore.ErrorLevel {
+		level = zapcore.ErrorLevel
+	}
+	return log.New(&stdlogWriter{l: l, level: level}, "", 0)
+}
+
+// StdlogAt returns a *log.Logger writing to the global logger, see
+// Logger.StdlogAt.
+func StdlogAt(level zapcore.Level) *log.Logger {
+	return globalLog.Load().StdlogAt(level)
+}
+
+// stdlogWriter logs each write of a *log.Logger as an entry at level.
+type stdlogWriter struct {
+	l     *Logger
+	level zapcore.Level
+}
+
+// Write implements io.Writer. The log package writes each message in one
+// call, ending it with a newline.
+func (w *stdlogWriter) Write(p []byte) (int, error) {
+	l := w.l
+	if !l.logger.Core().Enabled(w.level) {
+		return len(p), nil
+	}
+	traceID := ""
+	if l.config.BaseContext != nil && !l.inContext {
+		l, traceID = l.fromContext(l.config.BaseContext, traceID)
+	}
+	keys := &l.config.FieldKeys
+
+	// Like Logger, Info and Debug entries carry no source unless enabled
+	logger := l.logger
+	if l.config.Source || w.level >= zapcore.WarnLevel {
+		callerSkip := l.config.CallerSkip
+		if callerSkip <= 0 {
+			callerSkip = stdlogCallerSkip(l.config.CallerMaxDepth)
+		}
+		logger = logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(callerSkip))
+	}
+	msg := string(bytes.TrimSuffix(p, []byte("\n")))
+	logger.Log(w.level, msg,
+		zap.String(keys.TraceID, traceID),
+		zap.String(keys.Module, StdlogModule),
+		zap.String(keys.Severity, l.severity(w.level)),
+	)
+	return len(p), nil
+}
+
+// stdlogCallerSkip is detectCallerSkip for a stdlogWriter: it also skips the
+// log package, finding the function that called log.Print or similar.
+func stdlogCallerSkip(maxDepth int) int {
+	return findCallerSkip(maxDepth, func(fn string) bool {
+		return isPackageFrame(fn) || strings.HasPrefix(fn, "log.")
+	})
+}
@@ -0,0 +1,68 @@
+package goslogx_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/muhammadluth/goslogx"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestStdlogAt verifies a stdlib logger routed through StdlogAt writes
+// structured, masked entries at the chosen level
+func TestStdlogAt(t *testing.T) {
+	t.Run("Info", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := goslogx.NewLogger(goslogx.WithOutput(buf), goslogx.WithMessageScanning(true))
+		std := logger.StdlogAt(zapcore.InfoLevel)
+		std.Printf("retrying request for %s", "john.doe@example.com")
+
+		entry := decode(t, buf)
+		if entry[goslogx.KeyMessage] != "retrying request for jo****@example.com" {
+			t.Errorf("Expected the message without trailing newline and masked, got %q", entry[goslogx.KeyMessage])
+		}
+		if entry[goslogx.KeyLevel] != "info" || entry[goslogx.KeySeverity] != "INFO" {
+			t.Errorf("Expected info level, got %v / %v", entry[goslogx.KeyLevel], entry[goslogx.KeySeverity])
+		}
+		if entry[goslogx.KeyModule] != goslogx.StdlogModule {
+			t.Errorf("Expected module %s, got %v", goslogx.StdlogModule, entry[goslogx.KeyModule])
+		}
+		if _, ok := entry[goslogx.KeySource]; ok {
+			t.Errorf("Expected no source on info entries, got %v", entry[goslogx.KeySource])
+		}
+	})
+
+	t.Run("ErrorSource", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := goslogx.NewLogger(goslogx.WithOutput(buf))
+		// Fatal is lowered to Error, so it does not exit
+		logger.StdlogAt(zapcore.FatalLevel).Print("http: TLS handshake error")
+
+		entry := decode(t, buf)
+		if entry[goslogx.KeyLevel] != "error" {
+			t.Errorf("Expected error level, got %v", entry[goslogx.KeyLevel])
+		}
+		if source, _ := entry[goslogx.KeySource].(string); !strings.Contains(source, "stdlog_test.go") {
+			t.Errorf("Expected source in stdlog_test.go, got %q", source)
+		}
+	})
+
+	t.Run("LevelFiltered", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := goslogx.NewLogger(goslogx.WithOutput(buf))
+		logger.StdlogAt(zapcore.DebugLevel).Print("verbose detail")
+		if buf.Len() != 0 {
+			t.Errorf("Expected debug output dropped, got %s", buf.String())
+		}
+	})
+
+	t.Run("Global", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		defer goslogx.ReplaceGlobal(goslogx.WithOutput(buf))()
+		goslogx.StdlogAt(zapcore.WarnLevel).Println("connection reset")
+		if entry := decode(t, buf); entry[goslogx.KeyMessage] != "connection reset" || entry[goslogx.KeyLevel] != "warn" {
+			t.Errorf("Expected a warn entry from the global logger, got %v", entry)
+		}
+	})
+}
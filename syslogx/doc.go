@@ -0,0 +1,11 @@
+// Package syslogx sends goslogx entries to syslog, with each entry's syslog
+// priority taken from its severity. It is a separate package so that the
+// core builds on platforms without log/syslog, such as Windows.
+//
+// Basic Usage:
+//
+//	goslogx.New(
+//		goslogx.WithServiceName("user-service"),
+//		syslogx.WithSyslog("", "", "user-service"), // local syslog daemon
+//	)
+package syslogx
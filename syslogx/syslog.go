@@ -0,0 +1,217 @@
+//go:build !windows && !plan9
+
+package syslogx
+
+import (
+	"bytes"
+	"log/syslog"
+	"strings"
+	"sync"
+
+	"github.com/muhammadluth/goslogx"
+	"go.uber.org/zap/zapcore"
+)
+
+// Facility is the syslog facility entries are sent with.
+const Facility = syslog.LOG_USER
+
+// WithSyslog returns a goslogx option that sends entries to the syslog
+// daemon at addr over network, tagged with tag, instead of Output. An empty
+// network and addr use the local daemon. The connection is made on the
+// first entry and remade after a failed write, so the option never fails;
+// write errors are reported like those of any other output.
+//
+// Each entry's priority follows its severity field, taking any
+// WithSeverityMapping overrides into account: Error entries are sent as
+// LOG_ERR, Warning as LOG_WARNING, Info as LOG_INFO, Debug as LOG_DEBUG and
+// Fatal as LOG_CRIT. The JSON encoding must be kept for this; other lines
+// are sent as LOG_INFO.
+//
+// Example:
+//
+//	logger := goslogx.New(
+//	    goslogx.WithServiceName("my-service"),
+//	    syslogx.WithSyslog("udp", "logs.internal:514", "my-service"),
+//	)
+func WithSyslog(network, addr, tag string) goslogx.Option {
+	return func(c *goslogx.Config) {
+		w := NewWriter(network, addr, tag)
+		goslogx.WithOutput(w)(c)
+		goslogx.WithWrapCore(func(core zapcore.Core) zapcore.Core {
+			// Read the key and mapping when the core is built, after all
+			// options are applied
+			w.setSeverities(c.FieldKeys.Severity, c.SeverityMapping)
+			return core
+		})(c)
+	}
+}
+
+// Writer is a zapcore.WriteSyncer that sends each line written to it to
+// syslog as one message, at the priority of the line's severity field.
+// It is safe for concurrent use.
+type Writer struct {
+	network, addr, tag string
+
+	mu         sync.Mutex
+	conn       *syslog.Writer
+	key        []byte                     // `"severity":"`, with the configured key
+	priorities map[string]syslog.Priority // Severity values mapped to priorities
+}
+
+// NewWriter returns a Writer for the syslog daemon at addr over network,
+// see WithSyslog. It reads the default severity field and values; use
+// WithSyslog to follow WithFieldKeys and WithSeverityMapping too.
+func NewWriter(network, addr, tag string) *Writer {
+	w := &Writer{network: network, addr: addr, tag: tag}
+	w.setSeverities(goslogx.KeySeverity, nil)
+	return w
+}
+
+// setSeverities sets the severity field key and the values that map to
+// each priority, with overrides as set via goslogx.WithSeverityMapping.
+func (w *Writer) setSeverities(key string, overrides map[zapcore.Level]string) {
+	priorities := map[string]syslog.Priority{
+		"DEBUG":     syslog.LOG_DEBUG,
+		"INFO":      syslog.LOG_INFO,
+		"NOTICE":    syslog.LOG_NOTICE,
+		"WARNING":   syslog.LOG_WARNING,
+		"ERROR":     syslog.LOG_ERR,
+		"CRITICAL":  syslog.LOG_CRIT,
+		"ALERT":     syslog.LOG_ALERT,
+		"EMERGENCY": syslog.LOG_EMERG,
+	}
+	for lvl, severity := range overrides {
+		// Standard names keep their own priority, e.g. Fatal mapped to EMERGENCY
+		if _, ok := priorities[strings.ToUpper(severity)]; !ok {
+			priorities[strings.ToUpper(severity)] = levelPriority(lvl)
+		}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.key = []byte(`"` + key + `":"`)
+	w.priorities = priorities
+}
+
+// levelPriority returns the syslog priority for entries logged at lvl.
+func levelPriority(lvl zapcore.Level) syslog.Priority {
+	switch {
+	case lvl <= zapcore.DebugLevel:
+		return syslog.LOG_DEBUG
+	case lvl == zapcore.InfoLevel:
+		return syslog.LOG_INFO
+	case lvl == zapcore.WarnLevel:
+		return syslog.LOG_WARNING
+	case lvl == zapcore.ErrorLevel:
+		return syslog.LOG_ERR
+	}
+	return syslog.LOG_CRIT
+}
+
+// Write implements io.Writer, sending each line of p as a message. Lines
+// without a severity, such as those of a multi-line stack trace, share the
+// priority of the line before them.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		conn, err := syslog.Dial(w.network, w.addr, Facility|syslog.LOG_INFO, w.tag)
+		if err != nil {
+			return 0, err
+		}
+		w.conn = conn
+	}
+	priority := syslog.LOG_INFO
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if severity, ok := topLevelString(line, w.key); ok {
+			if pr, ok := w.priorities[strings.ToUpper(severity)]; ok {
+				priority = pr
+			}
+		}
+		if err := w.send(priority, string(line)); err != nil {
+			// Reconnect on the next write
+			_ = w.conn.Close()
+			w.conn = nil
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// send writes msg to syslog at priority.
+func (w *Writer) send(priority syslog.Priority, msg string) error {
+	switch priority {
+	case syslog.LOG_EMERG:
+		return w.conn.Emerg(msg)
+	case syslog.LOG_ALERT:
+		return w.conn.Alert(msg)
+	case syslog.LOG_CRIT:
+		return w.conn.Crit(msg)
+	case syslog.LOG_ERR:
+		return w.conn.Err(msg)
+	case syslog.LOG_WARNING:
+		return w.conn.Warning(msg)
+	case syslog.LOG_NOTICE:
+		return w.conn.Notice(msg)
+	case syslog.LOG_DEBUG:
+		return w.conn.Debug(msg)
+	}
+	return w.conn.Info(msg)
+}
+
+// Sync implements zapcore.WriteSyncer. Messages are sent as they are
+// written, so there is nothing to flush.
+func (w *Writer) Sync() error {
+	return nil
+}
+
+// Close closes the connection to the syslog daemon, if one is open.
+// A later write opens a new one.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// topLevelString returns the string value that follows key, given as
+// `"name":"`, in the JSON object line, ignoring keys of nested objects and
+// text inside string values.
+func topLevelString(line, key []byte) (string, bool) {
+	depth := 0
+	inString := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+		case '"':
+			if depth == 1 && bytes.HasPrefix(line[i:], key) {
+				value := line[i+len(key):]
+				if end := bytes.IndexByte(value, '"'); end >= 0 {
+					return string(value[:end]), true
+				}
+				return "", false
+			}
+			inString = true
+		}
+	}
+	return "", false
+}
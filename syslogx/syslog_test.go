@@ -0,0 +1,114 @@
+//go:build !windows && !plan9
+
+package syslogx
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muhammadluth/goslogx"
+	"go.uber.org/zap/zapcore"
+)
+
+// listen returns a local UDP listener standing in for a syslog daemon.
+func listen(t *testing.T) net.PacketConn {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// receive returns the next datagram sent to conn.
+func receive(t *testing.T, conn net.PacketConn) string {
+	t.Helper()
+	buf := make([]byte, 64<<10)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Failed to receive a datagram: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestWithSyslog(t *testing.T) {
+	conn := listen(t)
+	logger := goslogx.NewLogger(
+		goslogx.WithServiceName("syslog-test"),
+		goslogx.WithDebug(true),
+		WithSyslog("udp", conn.LocalAddr().String(), "myapp"),
+	)
+
+	tests := []struct {
+		name     string
+		log      func()
+		priority string // Facility LOG_USER (8) plus the severity
+	}{
+		{"Error", func() { logger.Error("trace-1", "db", errors.New("boom")) }, "<11>"},
+		{"Warning", func() { logger.Warning("trace-2", "db", "slow query", nil) }, "<12>"},
+		{"Info", func() { logger.Info("trace-3", "db", goslogx.MESSAGE_TYPE_EVENT, "connected", nil) }, "<14>"},
+		{"Debug", func() { logger.Debug("trace-4", "db", goslogx.MESSAGE_TYPE_EVENT, "ping", nil) }, "<15>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.log()
+			msg := receive(t, conn)
+			if !strings.HasPrefix(msg, tt.priority) {
+				t.Errorf("Expected priority %s, got %q", tt.priority, msg)
+			}
+			if !strings.Contains(msg, " myapp[") || !strings.Contains(msg, `"application_name":"syslog-test"`) {
+				t.Errorf("Expected the tagged JSON entry, got %q", msg)
+			}
+		})
+	}
+}
+
+func TestWithSyslogSeverityMapping(t *testing.T) {
+	conn := listen(t)
+	logger := goslogx.NewLogger(
+		WithSyslog("udp", conn.LocalAddr().String(), "myapp"),
+		goslogx.WithFieldKeys(map[string]string{goslogx.KeySeverity: "level_name"}),
+		goslogx.WithSeverityMapping(map[zapcore.Level]string{
+			zapcore.ErrorLevel: "err",
+			zapcore.WarnLevel:  "ALERT",
+		}),
+	)
+
+	logger.Error("trace-1", "db", errors.New("boom"))
+	if msg := receive(t, conn); !strings.HasPrefix(msg, "<11>") || !strings.Contains(msg, `"level_name":"err"`) {
+		t.Errorf("Expected a custom severity to keep its level's priority, got %q", msg)
+	}
+	logger.Warning("trace-2", "db", "disk almost full", nil)
+	if msg := receive(t, conn); !strings.HasPrefix(msg, "<9>") {
+		t.Errorf("Expected a standard severity name to use its own priority, got %q", msg)
+	}
+}
+
+func TestTopLevelString(t *testing.T) {
+	key := []byte(`"severity":"`)
+	tests := []struct {
+		name string
+		line string
+		want string
+		ok   bool
+	}{
+		{"TopLevel", `{"msg":"hi","severity":"ERROR"}`, "ERROR", true},
+		{"Nested", `{"data":{"severity":"DEBUG"},"severity":"INFO"}`, "INFO", true},
+		{"InString", `{"msg":"\"severity\":\"DEBUG\"","severity":"WARNING"}`, "WARNING", true},
+		{"Missing", `{"msg":"hi"}`, "", false},
+		{"NotJSON", `goroutine 1 [running]:`, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := topLevelString([]byte(tt.line), key)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("topLevelString(%q) = %q, %v, want %q, %v", tt.line, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
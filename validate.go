@@ -0,0 +1,104 @@
+package goslogx
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// validateCore wraps a zapcore.Core to check that every entry carries the
+// required fields with a non-empty value, see WithValidateFields.
+type validateCore struct {
+	zapcore.Core
+	required []string
+	bound    map[string]bool // Required fields bound with a non-empty value via With
+	panics   bool            // Panic instead of writing a warning entry
+	severity zapcore.Field   // Severity of the warning entry
+}
+
+// newValidateCore wraps core so that entries missing a required field are reported.
+func newValidateCore(core zapcore.Core, required []string, panics bool, keys *FieldKeys, severities map[zapcore.Level]string) zapcore.Core {
+	return &validateCore{
+		Core:     core,
+		required: required,
+		panics:   panics,
+		severity: zap.String(keys.Severity, severityFor(zapcore.WarnLevel, severities)),
+	}
+}
+
+// With implements zapcore.Core.
+func (c *validateCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	copied := false
+	for _, key := range c.required {
+		if c.bound[key] || !hasValue(fields, key) {
+			continue
+		}
+		if !copied {
+			// The parent keeps its own set
+			clone.bound = make(map[string]bool, len(c.bound)+1)
+			for k := range c.bound {
+				clone.bound[k] = true
+			}
+			copied = true
+		}
+		clone.bound[key] = true
+	}
+	return &clone
+}
+
+// Check implements zapcore.Core.
+func (c *validateCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core. The entry is always written; a warning entry
+// naming the missing fields follows it, or the call panics before writing it.
+// Both go through writeChecked, so the warning is only written where, and
+// if, the configured level lets warnings through.
+func (c *validateCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	var missing []string
+	for _, key := range c.required {
+		if !c.bound[key] && !hasValue(fields, key) {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 && c.panics {
+		panic(fmt.Sprintf("goslogx: entry %q is missing required fields %v", ent.Message, missing))
+	}
+	writeChecked(c.Core, ent, fields)
+	if len(missing) == 0 {
+		return nil
+	}
+	warning := zapcore.Entry{
+		Level:   zapcore.WarnLevel,
+		Time:    ent.Time,
+		Message: "log entry missing required fields",
+	}
+	writeChecked(c.Core, warning, []zapcore.Field{
+		c.severity,
+		zap.Strings("missing_fields", missing),
+		zap.String("entry_msg", ent.Message),
+	})
+	return nil
+}
+
+// hasValue reports whether fields hold key with a non-empty value.
+func hasValue(fields []zapcore.Field, key string) bool {
+	for i := range fields {
+		f := &fields[i]
+		if f.Key != key || f.Type == zapcore.SkipType {
+			continue
+		}
+		if f.Type == zapcore.StringType {
+			return f.String != ""
+		}
+		return true
+	}
+	return false
+}